@@ -238,3 +238,92 @@ func BenchmarkPlaylyfeGraphQLMaster(b *testing.B) {
 		executor.Execute(context, query, variables, "")
 	}
 }
+
+func benchmarkPlaylyfeGraphQLScheduler(b *testing.B, scheduler *pgql.Scheduler) {
+	executor, _ := pgql.NewExecutor(schema2, "DataType", "", resolvers)
+	executor.Scheduler = scheduler
+	for i := 0; i < b.N; i++ {
+		context := map[string]interface{}{}
+		variables := map[string]interface{}{}
+		executor.Execute(context, query, variables, "")
+	}
+}
+
+func BenchmarkPlaylyfeGraphQLUnboundedScheduler(b *testing.B) {
+	benchmarkPlaylyfeGraphQLScheduler(b, nil)
+}
+
+func BenchmarkPlaylyfeGraphQLSerialScheduler(b *testing.B) {
+	scheduler := pgql.NewScheduler(0, 0)
+	scheduler.Serial = true
+	benchmarkPlaylyfeGraphQLScheduler(b, scheduler)
+}
+
+var listSchema = `
+	type Item {
+		a: String
+		b: String
+		c: String
+		d: String
+		e: String
+		f: String
+		g: String
+		h: String
+	}
+
+	type Query {
+		items: [Item]
+	}
+`
+
+var listQuery = `{ items { a, b, c, d, e, f, g, h } }`
+
+func benchmarkPlaylyfeGraphQLList(b *testing.B, scheduler *pgql.Scheduler) {
+	b.StopTimer()
+	items := []interface{}{}
+	for i := 0; i < 5000; i++ {
+		items = append(items, map[string]interface{}{"a": "A", "b": "B", "c": "C", "d": "D"})
+	}
+	listResolvers := map[string]interface{}{
+		"Query/items": func(params *pgql.ResolveParams) (interface{}, error) {
+			return items, nil
+		},
+	}
+	for _, field := range []string{"e", "f", "g", "h"} {
+		listResolvers["Item/"+field] = func(params *pgql.ResolveParams) (interface{}, error) {
+			return "Resolved", nil
+		}
+	}
+	executor, _ := pgql.NewExecutor(listSchema, "Query", "", listResolvers)
+	executor.Scheduler = scheduler
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		context := map[string]interface{}{}
+		variables := map[string]interface{}{}
+		executor.Execute(context, listQuery, variables, "")
+	}
+}
+
+func BenchmarkPlaylyfeGraphQLListDefaultScheduler(b *testing.B) {
+	benchmarkPlaylyfeGraphQLList(b, pgql.NewScheduler(pgql.DEFAULT_MAX_CONCURRENCY, 0))
+}
+
+func BenchmarkPlaylyfeGraphQLListUnboundedScheduler(b *testing.B) {
+	benchmarkPlaylyfeGraphQLList(b, nil)
+}
+
+func BenchmarkPlaylyfeGraphQLListSerialScheduler(b *testing.B) {
+	scheduler := pgql.NewScheduler(0, 0)
+	scheduler.Serial = true
+	benchmarkPlaylyfeGraphQLList(b, scheduler)
+}
+
+func BenchmarkPlaylyfeGraphQLWithDocumentCache(b *testing.B) {
+	executor, _ := pgql.NewExecutor(schema2, "DataType", "", resolvers)
+	executor.DocumentCache = pgql.NewDocumentCache(pgql.DEFAULT_DOCUMENT_CACHE_SIZE)
+	for i := 0; i < b.N; i++ {
+		context := map[string]interface{}{}
+		variables := map[string]interface{}{}
+		executor.Execute(context, query, variables, "")
+	}
+}
@@ -0,0 +1,260 @@
+package graphql
+
+import (
+	"fmt"
+	"strings"
+
+	. "github.com/playlyfe/go-graphql/language"
+)
+
+// ComplexityFn returns the cost of a field from the values of its arguments
+// and the cost of its selection set. A list field taking a page size would
+// typically multiply childComplexity by it. As the arguments come from the
+// client, the cost should saturate at MAX_COMPLEXITY instead of overflowing;
+// a negative cost is taken for an overflow and counts as MAX_COMPLEXITY.
+type ComplexityFn func(args map[string]interface{}, childComplexity int) int
+
+// MAX_COMPLEXITY is the complexity at which the analysis of an operation
+// saturates.
+const MAX_COMPLEXITY = int(^uint(0) >> 1)
+
+// Analysis is the result of the static analysis of an operation.
+type Analysis struct {
+	// Depth is the number of nested fields down to the deepest field of the
+	// operation, the root fields having a depth of 1.
+	Depth int
+	// Complexity is the sum of the costs of the fields of the operation,
+	// saturating at MAX_COMPLEXITY. A field without a complexity function
+	// costs 1 plus the cost of its selection set.
+	Complexity int
+}
+
+// Analyze computes the depth and the complexity of the selected operation of
+// the request without executing it. Introspection fields are not counted.
+func (executor *Executor) Analyze(request string, variables map[string]interface{}, operationName string) (*Analysis, error) {
+	document, _, errs := executor.parseRequest(request)
+	if len(errs) > 0 {
+		return nil, errs[0]
+	}
+	reqCtx := &RequestContext{
+		Document:  document,
+		ErrorList: &ErrorList{},
+		Variables: variables,
+	}
+	operation := executor.selectOperation(reqCtx, operationName)
+	if operation == nil {
+		return nil, reqCtx.ErrorList.Errors[0].Error
+	}
+	return executor.analyzeOperation(reqCtx, operation, 0), nil
+}
+
+// withinLimits reports whether the operation is within the MaxDepth and
+// MaxComplexity of the executor, adding an error to the request otherwise.
+// The analysis stops as soon as the complexity exceeds MaxComplexity.
+func (executor *Executor) withinLimits(reqCtx *RequestContext, operation *OperationDefinition) bool {
+	if executor.MaxDepth <= 0 && executor.MaxComplexity <= 0 {
+		return true
+	}
+	analysis := executor.analyzeOperation(reqCtx, operation, executor.MaxComplexity)
+	if executor.MaxDepth > 0 && analysis.Depth > executor.MaxDepth {
+		reqCtx.ErrorList.Add(&Error{
+			Error: &GraphQLError{
+				Message: fmt.Sprintf("GraphQL Runtime Error: Operation has a depth of %d, which exceeds the maximum depth of %d", analysis.Depth, executor.MaxDepth),
+			},
+		})
+		return false
+	}
+	if executor.MaxComplexity > 0 && analysis.Complexity > executor.MaxComplexity {
+		reqCtx.ErrorList.Add(&Error{
+			Error: &GraphQLError{
+				Message: fmt.Sprintf("GraphQL Runtime Error: Operation has a complexity of at least %d, which exceeds the maximum complexity of %d", analysis.Complexity, executor.MaxComplexity),
+			},
+		})
+		return false
+	}
+	return true
+}
+
+// analyzeOperation returns the depth and the complexity of the operation. When
+// limit is positive the analysis stops once the complexity exceeds it, the
+// depth and the complexity returned then being lower bounds.
+func (executor *Executor) analyzeOperation(reqCtx *RequestContext, operation *OperationDefinition, limit int) *Analysis {
+	var rootType *ObjectTypeDefinition
+	switch operation.Operation {
+	case "query":
+		rootType = executor.Schema.QueryRoot
+	case "mutation":
+		rootType = executor.Schema.MutationRoot
+	case "subscription":
+		rootType = executor.Schema.SubscriptionRoot
+	}
+	if rootType == nil {
+		return &Analysis{}
+	}
+	reqCtx.VariableDefinitionIndex = operation.VariableDefinitionIndex
+	depth, complexity := executor.analyzeSelectionSet(reqCtx, rootType.Name.Value, operation.SelectionSet, map[string]bool{}, limit)
+	return &Analysis{
+		Depth:      depth,
+		Complexity: complexity,
+	}
+}
+
+// analyzedField is the group of fields selected for a response key on a type.
+type analyzedField struct {
+	typeName    string
+	responseKey string
+	fields      []*Field
+}
+
+// analyzeSelectionSet returns the depth and the complexity of a selection set
+// of the named type. A response key selected on several type conditions costs
+// the most expensive of them, as only one of them applies to a given value;
+// the fields selected on the named type itself are merged into each of them.
+// The fragments spread in the selection sets enclosing it are kept in visiting
+// so that fragment cycles are not followed. When limit is positive the fields
+// are no longer walked once the complexity exceeds it, so that fragments
+// spread over and over again are not expanded any further.
+func (executor *Executor) analyzeSelectionSet(reqCtx *RequestContext, typeName string, selectionSet *SelectionSet, visiting map[string]bool, limit int) (int, int) {
+	if selectionSet == nil {
+		return 0, 0
+	}
+	groups := []*analyzedField{}
+	groupIndex := map[[2]string]*analyzedField{}
+	entered := executor.analyzeSelections(reqCtx, typeName, selectionSet, visiting, groupIndex, &groups)
+	depth := 0
+	complexity := 0
+	costs := map[string]int{}
+	for _, group := range groups {
+		field := group.fields[0]
+		if strings.HasPrefix(field.Name.Value, "__") {
+			continue
+		}
+		fieldDefinition := executor.fieldDefinition(group.typeName, field.Name.Value)
+		if fieldDefinition == nil {
+			continue
+		}
+		fields := group.fields
+		if group.typeName != typeName {
+			if common, ok := groupIndex[[2]string{typeName, group.responseKey}]; ok {
+				fields = append(append([]*Field{}, common.fields...), group.fields...)
+			}
+		}
+		childType := executor.resolveNamedType(fieldDefinition.Type).Name.Value
+		childDepth, childComplexity := executor.analyzeSelectionSet(reqCtx, childType, executor.mergeSelectionSets(fields), visiting, limit)
+		if childDepth+1 > depth {
+			depth = childDepth + 1
+		}
+		cost := addComplexity(1, childComplexity)
+		if complexityFn, ok := executor.Complexity[group.typeName+"/"+field.Name.Value]; ok {
+			// Invalid arguments are reported when the field is executed
+			args, _ := executor.argumentValues(reqCtx, fieldDefinition.ArgumentIndex, field.ArgumentIndex, reqCtx.Variables, reqCtx.VariableDefinitionIndex)
+			cost = complexityFn(args, childComplexity)
+			if cost < 0 {
+				cost = MAX_COMPLEXITY
+			}
+		}
+		if previous := costs[group.responseKey]; cost > previous {
+			costs[group.responseKey] = cost
+			complexity = addComplexity(complexity, cost-previous)
+		}
+		if limit > 0 && complexity > limit {
+			break
+		}
+	}
+	for _, name := range entered {
+		delete(visiting, name)
+	}
+	return depth, complexity
+}
+
+// addComplexity adds two complexities, saturating at MAX_COMPLEXITY.
+func addComplexity(a int, b int) int {
+	if b > MAX_COMPLEXITY-a {
+		return MAX_COMPLEXITY
+	}
+	return a + b
+}
+
+// analyzeSelections groups the fields of the selection set by type condition
+// and response key, following its fragments whatever their type condition so
+// that the analysis covers every possible type. It returns the fragments it
+// entered.
+func (executor *Executor) analyzeSelections(reqCtx *RequestContext, typeName string, selectionSet *SelectionSet, visiting map[string]bool, groupIndex map[[2]string]*analyzedField, groups *[]*analyzedField) []string {
+	entered := []string{}
+	for _, item := range selectionSet.Selections {
+		switch selection := item.(type) {
+		case *Field:
+			if executor.isSkipped(reqCtx, selection.DirectiveIndex) {
+				continue
+			}
+			responseKey := selection.Name.Value
+			if selection.Alias != nil {
+				responseKey = selection.Alias.Value
+			}
+			group, ok := groupIndex[[2]string{typeName, responseKey}]
+			if !ok {
+				group = &analyzedField{typeName: typeName, responseKey: responseKey}
+				groupIndex[[2]string{typeName, responseKey}] = group
+				*groups = append(*groups, group)
+			}
+			group.fields = append(group.fields, selection)
+		case *FragmentSpread:
+			if visiting[selection.Name.Value] || executor.isSkipped(reqCtx, selection.DirectiveIndex) {
+				continue
+			}
+			fragment, ok := reqCtx.Document.FragmentIndex[selection.Name.Value]
+			if !ok || executor.isSkipped(reqCtx, fragment.DirectiveIndex) {
+				continue
+			}
+			visiting[selection.Name.Value] = true
+			entered = append(entered, selection.Name.Value)
+			entered = append(entered, executor.analyzeSelections(reqCtx, fragment.TypeCondition.Name.Value, fragment.SelectionSet, visiting, groupIndex, groups)...)
+		case *InlineFragment:
+			if executor.isSkipped(reqCtx, selection.DirectiveIndex) {
+				continue
+			}
+			fragmentTypeName := typeName
+			if selection.TypeCondition != nil {
+				fragmentTypeName = selection.TypeCondition.Name.Value
+			}
+			entered = append(entered, executor.analyzeSelections(reqCtx, fragmentTypeName, selection.SelectionSet, visiting, groupIndex, groups)...)
+		}
+	}
+	return entered
+}
+
+// fieldDefinition returns the definition of a field of an object or interface
+// type, or nil if the type has no such field.
+func (executor *Executor) fieldDefinition(typeName string, fieldName string) *FieldDefinition {
+	schema := executor.Schema.Document
+	if objectType, ok := schema.ObjectTypeIndex[typeName]; ok {
+		return objectType.FieldIndex[fieldName]
+	}
+	if interfaceType, ok := schema.InterfaceTypeIndex[typeName]; ok {
+		for _, field := range interfaceType.Fields {
+			if field.Name.Value == fieldName {
+				return field
+			}
+		}
+	}
+	return nil
+}
+
+// isSkipped reports whether the @skip or @include directives exclude a
+// selection.
+func (executor *Executor) isSkipped(reqCtx *RequestContext, directiveIndex map[string]*Directive) bool {
+	booleanType := &NamedType{Name: &Name{Value: "Boolean"}}
+	if directive, ok := directiveIndex["skip"]; ok && directive.ArgumentIndex["if"] != nil {
+		value, err := executor.valueFromAST(reqCtx.AppContext, directive.ArgumentIndex["if"].Value, booleanType, reqCtx.Variables, reqCtx.VariableDefinitionIndex)
+		if err == nil && value == true {
+			return true
+		}
+	}
+	if directive, ok := directiveIndex["include"]; ok && directive.ArgumentIndex["if"] != nil {
+		value, err := executor.valueFromAST(reqCtx.AppContext, directive.ArgumentIndex["if"].Value, booleanType, reqCtx.Variables, reqCtx.VariableDefinitionIndex)
+		if err == nil && value == false {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,157 @@
+package graphql
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestComplexity(t *testing.T) {
+
+	Convey("Execute: Limits the depth and complexity of operations", t, func() {
+		schema := `
+        interface Named {
+            name: String
+        }
+
+        type User implements Named {
+            name: String
+            friends(first: Int = 10): [User]
+            posts(first: Int): [Post]
+        }
+
+        type Bot implements Named {
+            name: String
+            friends(first: Int = 10): [User]
+        }
+
+        type Post {
+            title: String
+            author: User
+        }
+
+        type Query {
+            me: User
+            named: Named
+        }
+        `
+		resolved := 0
+		resolvers := map[string]interface{}{}
+		resolvers["Query/me"] = func(params *ResolveParams) (interface{}, error) {
+			resolved++
+			return map[string]interface{}{"name": "me"}, nil
+		}
+		executor, err := NewExecutor(schema, "Query", "", resolvers)
+		So(err, ShouldEqual, nil)
+		pageComplexity := func(args map[string]interface{}, childComplexity int) int {
+			first := 1
+			if value, ok := args["first"].(int32); ok {
+				first = int(value)
+			}
+			return first * (1 + childComplexity)
+		}
+		executor.Complexity["User/friends"] = pageComplexity
+		executor.Complexity["User/posts"] = pageComplexity
+		executor.Complexity["Bot/friends"] = pageComplexity
+
+		analyze := func(request string, variables map[string]interface{}) *Analysis {
+			analysis, err := executor.Analyze(request, variables, "")
+			So(err, ShouldEqual, nil)
+			return analysis
+		}
+
+		Convey("computes the depth and complexity of an operation", func() {
+			So(analyze(`{ me { name } }`, nil), ShouldResemble, &Analysis{Depth: 2, Complexity: 2})
+			So(analyze(`{ me { friends { name } } }`, nil), ShouldResemble, &Analysis{Depth: 3, Complexity: 1 + 10*2})
+			So(analyze(`query q($n: Int) { me { posts(first: $n) { title, author { name } } } }`, map[string]interface{}{"n": 5}), ShouldResemble, &Analysis{
+				Depth:      4,
+				Complexity: 1 + 5*(1+3),
+			})
+		})
+
+		Convey("follows fragments and skips excluded fields", func() {
+			So(analyze(`
+            { me { ...friends, others: friends { name } }, named { ... on User { posts(first: 2) { title } } } }
+            fragment friends on User { friends(first: 3) { name } }
+            `, nil), ShouldResemble, &Analysis{Depth: 3, Complexity: 1 + 3*2 + 10*2 + 1 + 2*2})
+			So(analyze(`{ me { name, friends @skip(if: true) { name } } }`, nil), ShouldResemble, &Analysis{Depth: 2, Complexity: 2})
+		})
+
+		Convey("counts the most expensive type condition of a response key", func() {
+			So(analyze(`{ named { ... on User { friends(first: 2) { name } }, ... on Bot { friends(first: 5) { name } } } }`, nil), ShouldResemble, &Analysis{
+				Depth:      3,
+				Complexity: 1 + 5*2,
+			})
+			So(analyze(`{ named { name, ... on User { name, friends(first: 2) { friends(first: 3) { name } } }, ... on Bot { friends(first: 5) { name } } } }`, nil), ShouldResemble, &Analysis{
+				Depth:      4,
+				Complexity: 1 + 1 + 2*(1+3*2),
+			})
+		})
+
+		Convey("does not count introspection fields", func() {
+			So(analyze(`{ __typename, __schema { types { fields { type { ofType { name } } } } } }`, nil), ShouldResemble, &Analysis{})
+		})
+
+		Convey("rejects deep operations before running any resolver", func() {
+			executor.MaxDepth = 3
+			result, err := executor.Execute(nil, `{ me { friends { friends { name } } } }`, map[string]interface{}{}, "")
+			So(err, ShouldEqual, nil)
+			So(result, ShouldResemble, map[string]interface{}{
+				"errors": []map[string]interface{}{
+					{"message": "GraphQL Runtime Error: Operation has a depth of 4, which exceeds the maximum depth of 3"},
+				},
+			})
+			So(resolved, ShouldEqual, 0)
+
+			result, err = executor.Execute(nil, `{ me { friends { name } } }`, map[string]interface{}{}, "")
+			So(err, ShouldEqual, nil)
+			So(result["errors"], ShouldEqual, nil)
+			So(resolved, ShouldEqual, 1)
+		})
+
+		Convey("rejects complex operations before running any resolver", func() {
+			executor.MaxComplexity = 100
+			result, err := executor.Execute(nil, `{ me { friends(first: 100) { name } } }`, map[string]interface{}{}, "")
+			So(err, ShouldEqual, nil)
+			So(result, ShouldResemble, map[string]interface{}{
+				"errors": []map[string]interface{}{
+					{"message": "GraphQL Runtime Error: Operation has a complexity of at least 201, which exceeds the maximum complexity of 100"},
+				},
+			})
+			So(resolved, ShouldEqual, 0)
+		})
+
+		Convey("saturates the complexity instead of overflowing", func() {
+			executor.Complexity["User/friends"] = func(args map[string]interface{}, childComplexity int) int {
+				return MAX_COMPLEXITY
+			}
+			So(analyze(`{ me { a: friends { name }, b: friends { name } } }`, nil), ShouldResemble, &Analysis{Depth: 3, Complexity: MAX_COMPLEXITY})
+			// A negative cost is an overflowed cost
+			executor.Complexity["User/posts"] = func(args map[string]interface{}, childComplexity int) int {
+				return -1
+			}
+			So(analyze(`{ me { name, posts { title } } }`, nil), ShouldResemble, &Analysis{Depth: 3, Complexity: MAX_COMPLEXITY})
+		})
+
+		Convey("stops walking the operation once it is too complex", func() {
+			// Each fragment spreads the next one twice, which would take 2^40
+			// steps to walk
+			request := `{ me { ...f0 } }`
+			for index := 0; index < 40; index++ {
+				request += fmt.Sprintf(` fragment f%d on User { a: friends(first: 1) { ...f%d }, b: friends(first: 1) { ...f%d } }`, index, index+1, index+1)
+			}
+			request += ` fragment f40 on User { name }`
+			executor.MaxComplexity = 100
+			result, err := executor.Execute(nil, request, map[string]interface{}{}, "")
+			So(err, ShouldEqual, nil)
+			So(result, ShouldResemble, map[string]interface{}{
+				"errors": []map[string]interface{}{
+					{"message": "GraphQL Runtime Error: Operation has a complexity of at least 225, which exceeds the maximum complexity of 100"},
+				},
+			})
+			So(resolved, ShouldEqual, 0)
+		})
+	})
+
+}
@@ -0,0 +1,265 @@
+package dataloader
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
+// Thunk returns the value loaded for a key. Calling it dispatches the pending
+// batch of its loader if that has not happened yet and waits for the result.
+type Thunk func() (interface{}, error)
+
+// Result is the value or the error loaded for a key.
+type Result struct {
+	Value interface{}
+	Error error
+}
+
+// PanicError is the error loaded for the keys of a batch whose batch function
+// panicked. It holds the value the batch function panicked with and the stack
+// of the goroutine it panicked in.
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+func (err *PanicError) Error() string {
+	return fmt.Sprintf("The batch function panicked: %v", err.Value)
+}
+
+// BatchFn loads the values of many keys at once. It must return one result for
+// each key, in the order of the keys.
+type BatchFn func(ctx context.Context, keys []interface{}) []*Result
+
+// Loader collects the keys loaded by resolvers and loads them together with
+// its batch function when it is dispatched. The result of every key is cached
+// for the lifetime of the loader. Keys must be comparable.
+type Loader struct {
+	// MaxBatchSize limits the number of keys passed to the batch function at
+	// once. Zero means no limit.
+	MaxBatchSize int
+	batchFn      BatchFn
+	ctx          context.Context
+	mutex        sync.Mutex
+	cache        map[interface{}]*entry
+	pending      []*entry
+}
+
+type entry struct {
+	key        interface{}
+	dispatched bool
+	done       chan struct{}
+	result     *Result
+}
+
+// NewLoader returns a loader calling batchFn with ctx.
+func NewLoader(ctx context.Context, batchFn BatchFn) *Loader {
+	return &Loader{
+		batchFn: batchFn,
+		ctx:     ctx,
+		cache:   map[interface{}]*entry{},
+	}
+}
+
+// Load enqueues the key in the next batch unless its result is already cached
+// or pending, and returns a thunk for its value.
+func (loader *Loader) Load(key interface{}) Thunk {
+	loader.mutex.Lock()
+	e, ok := loader.cache[key]
+	if !ok {
+		e = &entry{
+			key:  key,
+			done: make(chan struct{}),
+		}
+		loader.cache[key] = e
+		loader.pending = append(loader.pending, e)
+	}
+	loader.mutex.Unlock()
+	return func() (interface{}, error) {
+		loader.mutex.Lock()
+		dispatched := e.dispatched
+		loader.mutex.Unlock()
+		if !dispatched {
+			loader.Dispatch()
+		}
+		if loader.ctx != nil {
+			select {
+			case <-e.done:
+			case <-loader.ctx.Done():
+				// The result may have been loaded while the context
+				// was being cancelled
+				select {
+				case <-e.done:
+				default:
+					return nil, loader.ctx.Err()
+				}
+			}
+		} else {
+			<-e.done
+		}
+		return e.result.Value, e.result.Error
+	}
+}
+
+// LoadMany loads every key and returns a thunk for the list of their values.
+// The thunk fails with the first error of the keys.
+func (loader *Loader) LoadMany(keys []interface{}) Thunk {
+	thunks := make([]Thunk, len(keys))
+	for index, key := range keys {
+		thunks[index] = loader.Load(key)
+	}
+	return func() (interface{}, error) {
+		values := make([]interface{}, len(thunks))
+		for index, thunk := range thunks {
+			value, err := thunk()
+			if err != nil {
+				return nil, err
+			}
+			values[index] = value
+		}
+		return values, nil
+	}
+}
+
+// Prime caches the value of the key unless it has already been loaded.
+func (loader *Loader) Prime(key interface{}, value interface{}) {
+	loader.mutex.Lock()
+	defer loader.mutex.Unlock()
+	if _, ok := loader.cache[key]; ok {
+		return
+	}
+	e := &entry{
+		key:        key,
+		dispatched: true,
+		done:       make(chan struct{}),
+		result:     &Result{Value: value},
+	}
+	close(e.done)
+	loader.cache[key] = e
+}
+
+// Clear removes the key from the cache so that it is loaded again by the next
+// call to Load.
+func (loader *Loader) Clear(key interface{}) {
+	loader.mutex.Lock()
+	defer loader.mutex.Unlock()
+	if e, ok := loader.cache[key]; ok && e.dispatched {
+		delete(loader.cache, key)
+	}
+}
+
+// Dispatch loads the pending keys with the batch function, in batches of at
+// most MaxBatchSize keys.
+func (loader *Loader) Dispatch() {
+	loader.mutex.Lock()
+	pending := loader.pending
+	loader.pending = nil
+	for _, e := range pending {
+		e.dispatched = true
+	}
+	loader.mutex.Unlock()
+	for len(pending) > 0 {
+		size := len(pending)
+		if loader.MaxBatchSize > 0 && size > loader.MaxBatchSize {
+			size = loader.MaxBatchSize
+		}
+		loader.load(pending[:size])
+		pending = pending[size:]
+	}
+}
+
+// load calls the batch function with the keys of the entries and completes
+// them with its results. The entries are completed even if the batch function
+// panics, with a PanicError, so that no thunk waits for them forever.
+func (loader *Loader) load(entries []*entry) {
+	keys := make([]interface{}, len(entries))
+	for index, e := range entries {
+		keys[index] = e.key
+	}
+	var results []*Result
+	defer func() {
+		var panicErr *PanicError
+		if r := recover(); r != nil {
+			panicErr = &PanicError{
+				Value: r,
+				Stack: debug.Stack(),
+			}
+		}
+		for index, e := range entries {
+			if panicErr != nil {
+				e.result = &Result{Error: panicErr}
+			} else if len(results) != len(keys) {
+				e.result = &Result{
+					Error: fmt.Errorf("The batch function returned %d results for %d keys", len(results), len(keys)),
+				}
+			} else if results[index] == nil {
+				e.result = &Result{}
+			} else {
+				e.result = results[index]
+			}
+			close(e.done)
+		}
+	}()
+	results = loader.batchFn(loader.ctx, keys)
+}
+
+// Registry holds the loaders of a request, which are created from their batch
+// functions the first time they are used.
+type Registry struct {
+	ctx      context.Context
+	batchFns map[string]BatchFn
+	mutex    sync.Mutex
+	loaders  map[string]*Loader
+	created  []*Loader
+}
+
+// NewRegistry returns a registry creating its loaders from batchFns.
+func NewRegistry(ctx context.Context, batchFns map[string]BatchFn) *Registry {
+	return &Registry{
+		ctx:      ctx,
+		batchFns: batchFns,
+		loaders:  map[string]*Loader{},
+	}
+}
+
+// Loader returns the loader of the given name, or nil if there is no batch
+// function with that name.
+func (registry *Registry) Loader(name string) *Loader {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	if loader, ok := registry.loaders[name]; ok {
+		return loader
+	}
+	batchFn, ok := registry.batchFns[name]
+	if !ok {
+		return nil
+	}
+	loader := NewLoader(registry.ctx, batchFn)
+	registry.loaders[name] = loader
+	registry.created = append(registry.created, loader)
+	return loader
+}
+
+// Load enqueues the key in the loader of the given name and returns a thunk
+// for its value.
+func (registry *Registry) Load(name string, key interface{}) Thunk {
+	loader := registry.Loader(name)
+	if loader == nil {
+		return func() (interface{}, error) {
+			return nil, fmt.Errorf("No loader named %q", name)
+		}
+	}
+	return loader.Load(key)
+}
+
+// Dispatch dispatches the pending keys of every loader.
+func (registry *Registry) Dispatch() {
+	registry.mutex.Lock()
+	loaders := registry.created
+	registry.mutex.Unlock()
+	for _, loader := range loaders {
+		loader.Dispatch()
+	}
+}
@@ -0,0 +1,163 @@
+package dataloader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestLoader(t *testing.T) {
+
+	Convey("Loader", t, func() {
+		mutex := sync.Mutex{}
+		batches := [][]interface{}{}
+		batchFn := func(ctx context.Context, keys []interface{}) []*Result {
+			mutex.Lock()
+			batches = append(batches, keys)
+			mutex.Unlock()
+			results := []*Result{}
+			for _, key := range keys {
+				if key == "bad" {
+					results = append(results, &Result{Error: errors.New("Bad key")})
+				} else {
+					results = append(results, &Result{Value: fmt.Sprintf("value %v", key)})
+				}
+			}
+			return results
+		}
+		loader := NewLoader(context.Background(), batchFn)
+
+		Convey("loads the keys enqueued before a dispatch in a single batch", func() {
+			one := loader.Load(1)
+			two := loader.Load(2)
+			bad := loader.Load("bad")
+			loader.Dispatch()
+			value, err := one()
+			So(err, ShouldEqual, nil)
+			So(value, ShouldEqual, "value 1")
+			value, err = two()
+			So(value, ShouldEqual, "value 2")
+			_, err = bad()
+			So(err.Error(), ShouldEqual, "Bad key")
+			So(batches, ShouldResemble, [][]interface{}{{1, 2, "bad"}})
+		})
+
+		Convey("dispatches when a thunk is called before a dispatch", func() {
+			one := loader.Load(1)
+			loader.Load(2)
+			value, err := one()
+			So(err, ShouldEqual, nil)
+			So(value, ShouldEqual, "value 1")
+			So(batches, ShouldResemble, [][]interface{}{{1, 2}})
+		})
+
+		Convey("caches the result of every key", func() {
+			loader.Load(1)
+			loader.Dispatch()
+			value, _ := loader.Load(1)()
+			So(value, ShouldEqual, "value 1")
+			values, _ := loader.LoadMany([]interface{}{1, 2})()
+			So(values, ShouldResemble, []interface{}{"value 1", "value 2"})
+			So(batches, ShouldResemble, [][]interface{}{{1}, {2}})
+		})
+
+		Convey("splits the keys in batches of at most MaxBatchSize keys", func() {
+			loader.MaxBatchSize = 2
+			thunk := loader.LoadMany([]interface{}{1, 2, 3, 4, 5})
+			loader.Dispatch()
+			values, err := thunk()
+			So(err, ShouldEqual, nil)
+			So(len(values.([]interface{})), ShouldEqual, 5)
+			So(batches, ShouldResemble, [][]interface{}{{1, 2}, {3, 4}, {5}})
+		})
+
+		Convey("fails a list with the first error of its keys", func() {
+			_, err := loader.LoadMany([]interface{}{1, "bad"})()
+			So(err.Error(), ShouldEqual, "Bad key")
+		})
+
+		Convey("primes and clears the cache", func() {
+			loader.Prime(1, "primed")
+			value, _ := loader.Load(1)()
+			So(value, ShouldEqual, "primed")
+			loader.Clear(1)
+			value, _ = loader.Load(1)()
+			So(value, ShouldEqual, "value 1")
+			So(batches, ShouldResemble, [][]interface{}{{1}})
+		})
+
+		Convey("fails every key when the batch function returns the wrong number of results", func() {
+			loader := NewLoader(context.Background(), func(ctx context.Context, keys []interface{}) []*Result {
+				return []*Result{}
+			})
+			_, err := loader.Load(1)()
+			So(err.Error(), ShouldEqual, "The batch function returned 0 results for 1 keys")
+		})
+
+		Convey("fails every key of a batch whose batch function panics", func() {
+			loader := NewLoader(context.Background(), func(ctx context.Context, keys []interface{}) []*Result {
+				panic("boom")
+			})
+			one := loader.Load(1)
+			two := loader.Load(2)
+			So(loader.Dispatch, ShouldNotPanic)
+			_, err := one()
+			So(err.Error(), ShouldEqual, "The batch function panicked: boom")
+			So(err.(*PanicError).Value, ShouldEqual, "boom")
+			So(string(err.(*PanicError).Stack), ShouldContainSubstring, "dataloader_test.go")
+			_, err = two()
+			So(err, ShouldHaveSameTypeAs, &PanicError{})
+		})
+
+		Convey("stops waiting for a result once its context is cancelled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			started := make(chan struct{})
+			release := make(chan struct{})
+			loader := NewLoader(ctx, func(ctx context.Context, keys []interface{}) []*Result {
+				close(started)
+				<-release
+				return []*Result{{Value: 1}}
+			})
+			thunk := loader.Load(1)
+			go loader.Dispatch()
+			<-started
+			cancel()
+			_, err := thunk()
+			So(err, ShouldEqual, context.Canceled)
+			close(release)
+		})
+	})
+
+	Convey("Registry", t, func() {
+		ctx := context.WithValue(context.Background(), "user", "alice")
+		registry := NewRegistry(ctx, map[string]BatchFn{
+			"user": func(ctx context.Context, keys []interface{}) []*Result {
+				results := []*Result{}
+				for _, key := range keys {
+					results = append(results, &Result{Value: fmt.Sprintf("%v of %v", key, ctx.Value("user"))})
+				}
+				return results
+			},
+		})
+
+		Convey("creates a loader per batch function", func() {
+			So(registry.Loader("user"), ShouldEqual, registry.Loader("user"))
+			So(registry.Loader("post"), ShouldBeNil)
+			thunk := registry.Load("user", 1)
+			registry.Dispatch()
+			value, err := thunk()
+			So(err, ShouldEqual, nil)
+			So(value, ShouldEqual, "1 of alice")
+		})
+
+		Convey("fails the keys of unknown loaders", func() {
+			_, err := registry.Load("post", 1)()
+			So(err.Error(), ShouldEqual, `No loader named "post"`)
+		})
+	})
+
+}
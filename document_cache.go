@@ -0,0 +1,220 @@
+package graphql
+
+import (
+	"crypto/sha256"
+	"sync"
+	"sync/atomic"
+
+	. "github.com/playlyfe/go-graphql/language"
+	"github.com/playlyfe/go-graphql/utils"
+)
+
+// DEFAULT_DOCUMENT_CACHE_SIZE is a reasonable number of documents to keep in a
+// document cache, as in NewDocumentCache(DEFAULT_DOCUMENT_CACHE_SIZE).
+const DEFAULT_DOCUMENT_CACHE_SIZE = 1000
+
+// DocumentCache keeps the parsed and validated documents of the most recently
+// executed requests, keyed by the hash of their text, so that repeated
+// requests are neither parsed nor validated again. Only valid documents are
+// cached. The cached documents are not revalidated, so a new cache should be
+// used after changing the schema or the validation rules of the executor.
+type DocumentCache struct {
+	lru       *utils.LRU
+	hits      uint64
+	misses    uint64
+	evictions uint64
+}
+
+// DocumentCacheStats are the metrics of a document cache.
+type DocumentCacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	Size      int
+}
+
+// NewDocumentCache returns a cache keeping at most maxSize documents.
+func NewDocumentCache(maxSize int) *DocumentCache {
+	return &DocumentCache{
+		lru: utils.NewLRU(maxSize),
+	}
+}
+
+// Stats returns the number of requests found in and missing from the cache,
+// the number of documents evicted from it and its current size.
+func (cache *DocumentCache) Stats() DocumentCacheStats {
+	return DocumentCacheStats{
+		Hits:      atomic.LoadUint64(&cache.hits),
+		Misses:    atomic.LoadUint64(&cache.misses),
+		Evictions: atomic.LoadUint64(&cache.evictions),
+		Size:      cache.lru.Len(),
+	}
+}
+
+type cachedDocument struct {
+	document *Document
+	plans    *fieldPlans
+}
+
+func (cache *DocumentCache) get(key [sha256.Size]byte) (*cachedDocument, bool) {
+	value, ok := cache.lru.Get(key)
+	if !ok {
+		atomic.AddUint64(&cache.misses, 1)
+		return nil, false
+	}
+	atomic.AddUint64(&cache.hits, 1)
+	return value.(*cachedDocument), true
+}
+
+func (cache *DocumentCache) add(key [sha256.Size]byte, cached *cachedDocument) {
+	if evicted := cache.lru.Add(key, cached); evicted > 0 {
+		atomic.AddUint64(&cache.evictions, uint64(evicted))
+	}
+}
+
+// fieldPlans holds the fields collected from the selection sets of a cached
+// document for each object type they were executed on, so that they are
+// collected once for all the requests using the document. Collections that
+// depend on the variables of the request through @skip, @include or @defer are
+// not kept, nor are those of the selection sets merged from several fields,
+// which are not part of the document.
+type fieldPlans struct {
+	selectionSets map[*SelectionSet]bool
+	plans         map[fieldPlanKey]*fieldPlan
+	sync.RWMutex
+}
+
+type fieldPlanKey struct {
+	objectType   *ObjectTypeDefinition
+	selectionSet *SelectionSet
+	incremental  bool
+}
+
+type fieldPlan struct {
+	groupedFields []*GroupedField
+	deferred      []*deferredFragment
+	cacheable     bool
+}
+
+func newFieldPlans(document *Document) *fieldPlans {
+	plans := &fieldPlans{
+		selectionSets: map[*SelectionSet]bool{},
+		plans:         map[fieldPlanKey]*fieldPlan{},
+	}
+	for _, definition := range document.Definitions {
+		switch definition := definition.(type) {
+		case *OperationDefinition:
+			plans.addSelectionSet(definition.SelectionSet)
+		case *FragmentDefinition:
+			plans.addSelectionSet(definition.SelectionSet)
+		}
+	}
+	return plans
+}
+
+func (plans *fieldPlans) addSelectionSet(selectionSet *SelectionSet) {
+	if selectionSet == nil {
+		return
+	}
+	plans.selectionSets[selectionSet] = true
+	for _, item := range selectionSet.Selections {
+		switch selection := item.(type) {
+		case *Field:
+			plans.addSelectionSet(selection.SelectionSet)
+		case *InlineFragment:
+			plans.addSelectionSet(selection.SelectionSet)
+		}
+	}
+}
+
+// groupedFields collects the fields of the selection set for the object type,
+// along with the fragments deferred when the request is executed
+// incrementally, reusing the plans of the document when it has been cached.
+func (executor *Executor) groupedFields(reqCtx *RequestContext, objectType *ObjectTypeDefinition, selectionSet *SelectionSet) ([]*GroupedField, []*deferredFragment, error) {
+	if reqCtx.plans == nil || !reqCtx.plans.selectionSets[selectionSet] {
+		return executor.collectIncrementalFields(reqCtx, objectType, selectionSet)
+	}
+	key := fieldPlanKey{
+		objectType:   objectType,
+		selectionSet: selectionSet,
+		incremental:  reqCtx.incremental != nil,
+	}
+	reqCtx.plans.RLock()
+	plan, ok := reqCtx.plans.plans[key]
+	reqCtx.plans.RUnlock()
+	if ok && plan.cacheable {
+		return plan.groupedFields, plan.deferred, nil
+	}
+	groupedFields, deferred, err := executor.collectIncrementalFields(reqCtx, objectType, selectionSet)
+	if err != nil || ok {
+		return groupedFields, deferred, err
+	}
+	plan = &fieldPlan{
+		cacheable: !dependsOnVariables(reqCtx.Document, selectionSet, &utils.Set{}),
+	}
+	if plan.cacheable {
+		plan.groupedFields = groupedFields
+		plan.deferred = deferred
+	}
+	reqCtx.plans.Lock()
+	reqCtx.plans.plans[key] = plan
+	reqCtx.plans.Unlock()
+	return groupedFields, deferred, nil
+}
+
+// collectIncrementalFields collects the fields of the selection set, setting
+// the deferred fragments aside when the request is executed incrementally.
+func (executor *Executor) collectIncrementalFields(reqCtx *RequestContext, objectType *ObjectTypeDefinition, selectionSet *SelectionSet) ([]*GroupedField, []*deferredFragment, error) {
+	if reqCtx.incremental == nil {
+		groupedFields, err := executor.collectFields(reqCtx, objectType, selectionSet, &utils.Set{}, nil)
+		return groupedFields, nil, err
+	}
+	deferred := []*deferredFragment{}
+	groupedFields, err := executor.collectFields(reqCtx, objectType, selectionSet, &utils.Set{}, &deferred)
+	return groupedFields, deferred, err
+}
+
+// dependsOnVariables reports whether the fields collected from the selection
+// set depend on variables, which is the case when a variable is given to the
+// @skip, @include or @defer directive of one of its selections or fragments.
+func dependsOnVariables(document *Document, selectionSet *SelectionSet, visitedFragments *utils.Set) bool {
+	for _, item := range selectionSet.Selections {
+		switch selection := item.(type) {
+		case *Field:
+			if hasVariableCondition(selection.DirectiveIndex) {
+				return true
+			}
+		case *FragmentSpread:
+			if hasVariableCondition(selection.DirectiveIndex) {
+				return true
+			}
+			if visitedFragments.Has(selection.Name.Value) {
+				continue
+			}
+			visitedFragments.Add(selection.Name.Value, true)
+			fragment, ok := document.FragmentIndex[selection.Name.Value]
+			if !ok {
+				continue
+			}
+			if hasVariableCondition(fragment.DirectiveIndex) || dependsOnVariables(document, fragment.SelectionSet, visitedFragments) {
+				return true
+			}
+		case *InlineFragment:
+			if hasVariableCondition(selection.DirectiveIndex) || dependsOnVariables(document, selection.SelectionSet, visitedFragments) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func hasVariableCondition(directiveIndex map[string]*Directive) bool {
+	for _, name := range []string{"skip", "include", "defer"} {
+		if directive, ok := directiveIndex[name]; ok && directive.ArgumentIndex["if"] != nil {
+			if _, ok := directive.ArgumentIndex["if"].Value.(*Variable); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+package graphql
+
+import (
+	"sync"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDocumentCache(t *testing.T) {
+
+	Convey("Execute: Caches parsed and validated documents", t, func() {
+		schema := `
+        type Item {
+            id: Int
+            name: String
+        }
+
+        type Query {
+            item: Item
+            items: [Item]
+        }
+        `
+		resolvers := map[string]interface{}{}
+		resolvers["Query/item"] = func(params *ResolveParams) (interface{}, error) {
+			return map[string]interface{}{"id": 1, "name": "one"}, nil
+		}
+		resolvers["Query/items"] = func(params *ResolveParams) (interface{}, error) {
+			return []interface{}{
+				map[string]interface{}{"id": 1, "name": "one"},
+				map[string]interface{}{"id": 2, "name": "two"},
+			}, nil
+		}
+		executor, err := NewExecutor(schema, "Query", "", resolvers)
+		So(err, ShouldEqual, nil)
+		executor.DocumentCache = NewDocumentCache(2)
+
+		execute := func(request string, variables map[string]interface{}) map[string]interface{} {
+			result, err := executor.Execute(nil, request, variables, "")
+			So(err, ShouldEqual, nil)
+			return result
+		}
+
+		Convey("and counts the hits and misses of the cache", func() {
+			for i := 0; i < 3; i++ {
+				So(execute(`{ item { id } }`, nil), ShouldResemble, map[string]interface{}{
+					"data": map[string]interface{}{
+						"item": map[string]interface{}{"id": int32(1)},
+					},
+				})
+			}
+			So(executor.DocumentCache.Stats(), ShouldResemble, DocumentCacheStats{
+				Hits:   2,
+				Misses: 1,
+				Size:   1,
+			})
+		})
+
+		Convey("and evicts the least recently used documents", func() {
+			execute(`{ item { id } }`, nil)
+			execute(`{ item { name } }`, nil)
+			execute(`{ item { id } }`, nil)
+			execute(`{ items { id } }`, nil)
+			execute(`{ item { id } }`, nil)
+			So(executor.DocumentCache.Stats(), ShouldResemble, DocumentCacheStats{
+				Hits:      2,
+				Misses:    3,
+				Evictions: 1,
+				Size:      2,
+			})
+			execute(`{ item { name } }`, nil)
+			So(executor.DocumentCache.Stats().Misses, ShouldEqual, 4)
+		})
+
+		Convey("but not invalid documents", func() {
+			for i := 0; i < 2; i++ {
+				result := execute(`{ item { unknown } }`, nil)
+				So(result["errors"], ShouldNotEqual, nil)
+			}
+			So(executor.DocumentCache.Stats(), ShouldResemble, DocumentCacheStats{
+				Misses: 2,
+			})
+		})
+
+		Convey("and collects the fields again when they depend on variables", func() {
+			request := `query q($skip: Boolean!) { items { id, name @skip(if: $skip) } }`
+			So(execute(request, map[string]interface{}{"skip": true}), ShouldResemble, map[string]interface{}{
+				"data": map[string]interface{}{
+					"items": []interface{}{
+						map[string]interface{}{"id": int32(1)},
+						map[string]interface{}{"id": int32(2)},
+					},
+				},
+			})
+			So(execute(request, map[string]interface{}{"skip": false}), ShouldResemble, map[string]interface{}{
+				"data": map[string]interface{}{
+					"items": []interface{}{
+						map[string]interface{}{"id": int32(1), "name": "one"},
+						map[string]interface{}{"id": int32(2), "name": "two"},
+					},
+				},
+			})
+			So(executor.DocumentCache.Stats().Hits, ShouldEqual, 1)
+		})
+
+		Convey("and shares the documents between concurrent requests", func() {
+			wg := sync.WaitGroup{}
+			results := make([]map[string]interface{}, 20)
+			for i := range results {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					results[i], _ = executor.Execute(nil, `{ items { ...item } } fragment item on Item { id, name }`, map[string]interface{}{}, "")
+				}(i)
+			}
+			wg.Wait()
+			for _, result := range results {
+				So(result, ShouldResemble, map[string]interface{}{
+					"data": map[string]interface{}{
+						"items": []interface{}{
+							map[string]interface{}{"id": int32(1), "name": "one"},
+							map[string]interface{}{"id": int32(2), "name": "two"},
+						},
+					},
+				})
+			}
+		})
+	})
+
+}
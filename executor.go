@@ -1,19 +1,20 @@
 package graphql
 
 import (
-	"encoding/json"
+	"context"
+	"crypto/sha256"
 	"fmt"
 	"math"
 	"reflect"
-	"runtime/debug"
+	"strconv"
 	"strings"
-
-	//"log"
-
 	"sync"
 
+	"github.com/playlyfe/go-graphql/dataloader"
 	. "github.com/playlyfe/go-graphql/language"
+	"github.com/playlyfe/go-graphql/persisted"
 	"github.com/playlyfe/go-graphql/utils"
+	"github.com/playlyfe/go-graphql/validation"
 )
 
 type ResolveParams struct {
@@ -21,33 +22,170 @@ type ResolveParams struct {
 	Request  *Document
 	Schema   *Document
 	Context  interface{}
-	Source   interface{}
-	Args     map[string]interface{}
-	Field    *Field
+	// Ctx carries the cancellation signal and deadline of the request.
+	Ctx    context.Context
+	Source interface{}
+	Args   map[string]interface{}
+	Field  *Field
+	// Path is the path of the field in the response.
+	Path *ResponsePath
+	// Done is closed when the subscription the field is being resolved for
+	// has been closed. It is nil outside of subscriptions.
+	Done <-chan struct{}
+	// Loaders holds the loaders of the request created from the batch
+	// functions of Executor.Loaders. It is nil if there are none.
+	Loaders *dataloader.Registry
+}
+
+// HasArg reports whether the argument is set in Args, either because the
+// request gave it a value, including an explicit null, or because it has a
+// default value. Omitted arguments without a default value are not set, so
+// a resolver can tell update(name: null) apart from an update without a name.
+func (params *ResolveParams) HasArg(name string) bool {
+	_, ok := params.Args[name]
+	return ok
 }
 
 type Error struct {
 	Error error
 	Field *Field
+	// Path is the path in the response of the field that failed, made of
+	// response keys and list indices.
+	Path []interface{}
+}
+
+// ExtendedError is an error carrying extensions, such as an error code, which
+// are added to the entry of the error in the response by the default
+// ErrorHandler. Resolvers return it like any other error.
+type ExtendedError struct {
+	Message    string
+	Extensions map[string]interface{}
+}
+
+func (err *ExtendedError) Error() string {
+	return err.Message
+}
+
+// ResponsePath is the path of a field or list item in the response. Every
+// element holds a response key or a list index and points to the path of its
+// parent.
+type ResponsePath struct {
+	Prev *ResponsePath
+	Key  interface{}
+}
+
+// WithKey returns the path of the child of the element at the given response
+// key or list index.
+func (path *ResponsePath) WithKey(key interface{}) *ResponsePath {
+	return &ResponsePath{
+		Prev: path,
+		Key:  key,
+	}
+}
+
+// AsArray returns the keys of the path from the root of the response.
+func (path *ResponsePath) AsArray() []interface{} {
+	if path == nil {
+		return nil
+	}
+	keys := []interface{}{}
+	for element := path; element != nil; element = element.Prev {
+		keys = append(keys, element.Key)
+	}
+	for i, j := 0, len(keys)-1; i < j; i, j = i+1, j-1 {
+		keys[i], keys[j] = keys[j], keys[i]
+	}
+	return keys
 }
 
 type ErrorList struct {
 	Errors []*Error
+	// paths holds the keys of the paths of the errors added with Add
+	paths map[string]bool
 	sync.Mutex
 }
 
 func (list *ErrorList) Add(err *Error) {
 	list.Lock()
 	list.Errors = append(list.Errors, err)
+	if err.Path != nil {
+		if list.paths == nil {
+			list.paths = map[string]bool{}
+		}
+		list.paths[pathKey(err.Path)] = true
+	}
 	list.Unlock()
 }
 
+// reported reports whether an error has been added at the given path.
+func (list *ErrorList) reported(path []interface{}) bool {
+	list.Lock()
+	defer list.Unlock()
+	return list.paths[pathKey(path)]
+}
+
+// pathKey returns a string identifying the path, telling response keys and
+// list indices apart.
+func pathKey(path []interface{}) string {
+	key := make([]byte, 0, 8*len(path))
+	for _, element := range path {
+		switch element := element.(type) {
+		case int:
+			key = append(key, '/')
+			key = strconv.AppendInt(key, int64(element), 10)
+		default:
+			key = append(key, '.')
+			key = append(key, fmt.Sprint(element)...)
+		}
+	}
+	return string(key)
+}
+
+// firstError returns the first of the errors of values completed together,
+// such as the fields of an object or the items of a list, which all null the
+// value holding them. The other GraphQL errors are added to the errors of the
+// request so that every failure is reported, the first one being reported by
+// the nullable value it nulls.
+func firstError(reqCtx *RequestContext, path *ResponsePath, errs []error) error {
+	var first error
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		if _, ok := err.(*GraphQLError); !ok {
+			return err
+		}
+		if first == nil {
+			first = err
+		}
+	}
+	for _, err := range errs {
+		if err != nil && err != first {
+			catchError(reqCtx, path, err)
+		}
+	}
+	return first
+}
+
+// errNulled is returned in place of a non-null value that is null because of
+// an error that has already been reported, so that it nulls the nearest
+// nullable value holding it without being reported again.
+var errNulled = &GraphQLError{
+	Message: "GraphQL Runtime Error: Non-null value nulled by an error",
+}
+
 type RequestContext struct {
+	Ctx                     context.Context
 	AppContext              interface{}
 	Document                *Document
 	ErrorList               *ErrorList
 	Variables               map[string]interface{}
 	VariableDefinitionIndex map[string]*VariableDefinition
+	Done                    <-chan struct{}
+	Loaders                 *dataloader.Registry
+	workers                 chan struct{}
+	plans                   *fieldPlans
+	incremental             *incrementalState
 }
 
 type ResolveFn func(params *ResolveParams) (interface{}, error)
@@ -55,6 +193,14 @@ type AroundFn func(resolveFn ResolveFn, params *ResolveParams) (interface{}, err
 type BeforeFn func(params *ResolveParams) (interface{}, error)
 type AfterFn func(params *ResolveParams, result interface{}) (interface{}, error)
 
+// DirectiveFn wraps the resolution of a field carrying the directive in the
+// schema. It receives the arguments given to the directive in the schema and
+// continues the resolution of the field by calling resolveFn. Only the
+// directives applied to the field definition of the object type are used; the
+// directives applied to the fields of interfaces are not inherited by the
+// objects implementing them and are ignored.
+type DirectiveFn func(resolveFn ResolveFn, params *ResolveParams, directiveArgs map[string]interface{}) (interface{}, error)
+
 type FieldParams struct {
 	Resolve ResolveFn
 	Around  AroundFn
@@ -69,14 +215,58 @@ type Scalar struct {
 }
 
 type Executor struct {
-	ResolveType  func(value interface{}) string
-	IsNullish    func(value interface{}) bool
-	Schema       *Schema
-	Resolvers    map[string]interface{}
-	Scalars      map[string]*Scalar
-	ErrorHandler func(err *Error) map[string]interface{}
-	Before       func(params *ResolveParams, operation string) error
-	After        func(params *ResolveParams, result map[string]interface{}) error
+	ResolveType     func(value interface{}) string
+	IsNullish       func(value interface{}) bool
+	Schema          *Schema
+	Resolvers       map[string]interface{}
+	Scalars         map[string]*Scalar
+	ErrorHandler    func(err *Error) map[string]interface{}
+	Before          func(params *ResolveParams, operation string) error
+	After           func(params *ResolveParams, result map[string]interface{}) error
+	ValidationRules []validation.Rule
+	// Directives holds the functions wrapping the resolution of fields whose
+	// definition carries the schema directive of the same name.
+	Directives map[string]DirectiveFn
+	// Scheduler bounds the goroutines used to resolve fields and complete
+	// list items. A nil scheduler, the default, uses a goroutine for each of
+	// them, which keeps small queries fast but lets a request returning large
+	// lists start as many goroutines as it has items. Servers executing
+	// untrusted queries should set NewScheduler(DEFAULT_MAX_CONCURRENCY, n).
+	Scheduler *Scheduler
+	// Loaders holds the batch functions of the loaders available to the
+	// resolvers through ResolveParams.Loaders. Every request gets its own
+	// loaders, so results are only cached for the duration of a request.
+	Loaders map[string]dataloader.BatchFn
+	// MaxDepth rejects the operations whose fields are nested deeper than
+	// MaxDepth before any resolver runs. Zero means no limit.
+	MaxDepth int
+	// MaxComplexity rejects the operations whose complexity is higher than
+	// MaxComplexity before any resolver runs. Zero means no limit.
+	MaxComplexity int
+	// Complexity holds the complexity functions of fields, keyed like the
+	// resolvers by "Type/field".
+	Complexity map[string]ComplexityFn
+	// DocumentCache keeps the parsed and validated documents of recent
+	// requests. A nil cache, the default, parses and validates every request.
+	DocumentCache *DocumentCache
+	// PersistedQueries holds the queries clients may refer to by hash with
+	// ResolvePersistedQuery.
+	PersistedQueries persisted.Store
+	// OnlyPersistedQueries rejects the requests whose query is not one of the
+	// PersistedQueries, which clients may not register anymore.
+	OnlyPersistedQueries bool
+	// OrderedResults returns the objects of the results as *OrderedMap
+	// values, which are marshalled to JSON with their fields in the order they
+	// were selected in, instead of map[string]interface{} values.
+	OrderedResults bool
+	// Logger receives the panics recovered while resolving fields along with
+	// their stack, and the errors registering persisted queries. A nil
+	// logger, the default, discards them.
+	Logger Logger
+	// CrashOnPanic lets the panics raised while resolving fields crash the
+	// program instead of reporting them as errors of the fields, which is
+	// useful during development.
+	CrashOnPanic bool
 	Debug        bool
 }
 
@@ -138,7 +328,9 @@ func (executor *Executor) printType(ttype ASTNode) string {
 
 /**
  * Prepares an object map of argument values given a list of argument
- * definitions and list of argument AST nodes.
+ * definitions and list of argument AST nodes. Arguments explicitly set to null
+ * are included with a nil value while arguments that were omitted and have no
+ * default value are left out.
  */
 func (executor *Executor) argumentValues(reqCtx *RequestContext, argDefs map[string]*InputValueDefinition, argASTMap map[string]*Argument, variableValues map[string]interface{}, variableDefinitionIndex map[string]*VariableDefinition) (map[string]interface{}, error) {
 	result := map[string]interface{}{}
@@ -148,23 +340,74 @@ func (executor *Executor) argumentValues(reqCtx *RequestContext, argDefs map[str
 		if argAST, ok := argASTMap[name]; ok {
 			valueAST = argAST.Value
 		}
-		value, err := executor.valueFromAST(reqCtx.AppContext, valueAST, argDef.Type, variableValues, variableDefinitionIndex)
+		value, ok, err := executor.inputValueFromAST(reqCtx.AppContext, argDef, valueAST, variableValues, variableDefinitionIndex)
 		if err != nil {
 			return nil, err
 		}
-		if executor.IsNullish(value) {
-			value, err = executor.valueFromAST(reqCtx.AppContext, argDef.DefaultValue, argDef.Type, nil, nil)
-			if err != nil {
-				return nil, err
-			}
-		}
-		if !executor.IsNullish(value) {
+		if ok {
 			result[name] = value
 		}
 	}
 	return result, nil
 }
 
+// inputValueFromAST coerces the value given to an argument or input object
+// field, falling back to the default value of its definition when no value was
+// given. The returned boolean is false when the value was omitted and there is
+// no default value, which tells an omitted value apart from an explicit null.
+func (executor *Executor) inputValueFromAST(context interface{}, definition *InputValueDefinition, valueAST ASTNode, variables map[string]interface{}, variableDefinitionIndex map[string]*VariableDefinition) (interface{}, bool, error) {
+	if executor.isNullValue(valueAST, variables, variableDefinitionIndex) {
+		if _, ok := definition.Type.(*NonNullType); ok {
+			return nil, false, &GraphQLError{
+				Message: fmt.Sprintf("Expected \"%s\", found null", executor.printType(definition.Type)),
+			}
+		}
+		return nil, true, nil
+	}
+	var value interface{}
+	var err error
+	if valueAST != nil || definition.DefaultValue == nil {
+		value, err = executor.valueFromAST(context, valueAST, definition.Type, variables, variableDefinitionIndex)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+	if value == nil {
+		// The value was omitted or could not be coerced
+		if definition.DefaultValue == nil {
+			return nil, false, nil
+		}
+		value, err = executor.valueFromAST(context, definition.DefaultValue, definition.Type, nil, nil)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+	return value, true, nil
+}
+
+// isNullValue reports whether the value AST is the null literal or a nullable
+// variable that was explicitly set to null.
+func (executor *Executor) isNullValue(valueAST ASTNode, variables map[string]interface{}, variableDefinitionIndex map[string]*VariableDefinition) bool {
+	switch valueAST := valueAST.(type) {
+	case *Null:
+		return true
+	case *Variable:
+		variableDefinition := variableDefinitionIndex[valueAST.Name.Value]
+		if variableDefinition == nil {
+			return false
+		}
+		if _, ok := variableDefinition.Type.(*NonNullType); ok {
+			return false
+		}
+		if value, ok := variables[valueAST.Name.Value]; ok {
+			return value == nil
+		}
+		_, ok := variableDefinition.DefaultValue.(*Null)
+		return ok
+	}
+	return false
+}
+
 func (executor *Executor) variableValue(context interface{}, ntype ASTNode, input interface{}) (interface{}, error) {
 	if ttype, ok := ntype.(*NonNullType); ok {
 		value, err := executor.variableValue(context, ttype.Type, input)
@@ -271,15 +514,13 @@ func (executor *Executor) variableValue(context interface{}, ntype ASTNode, inpu
 								}
 								return nil, err
 							}
-							if executor.IsNullish(fieldValue) {
-								fieldValue, err = executor.valueFromAST(context, field.DefaultValue, field.Type, nil, nil)
-								if err != nil {
-									return nil, err
-								}
-							}
-							if !executor.IsNullish(fieldValue) {
-								result[field.Name.Value] = fieldValue
+							result[field.Name.Value] = fieldValue
+						} else if field.DefaultValue != nil {
+							fieldValue, err := executor.valueFromAST(context, field.DefaultValue, field.Type, nil, nil)
+							if err != nil {
+								return nil, err
 							}
+							result[field.Name.Value] = fieldValue
 						} else {
 							// We ensure that the missing value is nullable
 							_, err := executor.variableValue(context, field.Type, nil)
@@ -343,6 +584,11 @@ func (executor *Executor) variableValue(context interface{}, ntype ASTNode, inpu
 
 func (executor *Executor) valueFromAST(context interface{}, valueAST ASTNode, ntype ASTNode, variables map[string]interface{}, variableDefinitionIndex map[string]*VariableDefinition) (interface{}, error) {
 	if ttype, ok := ntype.(*NonNullType); ok {
+		if _, ok := valueAST.(*Null); ok {
+			return nil, &GraphQLError{
+				Message: fmt.Sprintf("Expected \"%s\", found null", executor.printType(ttype)),
+			}
+		}
 		value, err := executor.valueFromAST(context, valueAST, ttype.Type, variables, variableDefinitionIndex)
 		if err != nil {
 			return nil, err
@@ -357,6 +603,9 @@ func (executor *Executor) valueFromAST(context interface{}, valueAST ASTNode, nt
 	if valueAST == nil {
 		return nil, nil
 	}
+	if _, ok := valueAST.(*Null); ok {
+		return nil, nil
+	}
 	if ttype, ok := valueAST.(*Variable); ok {
 		variableName := ttype.Name.Value
 		if variables == nil {
@@ -499,45 +748,20 @@ func (executor *Executor) valueFromAST(context interface{}, valueAST ASTNode, nt
 					fieldASTs := object.FieldIndex
 					result := map[string]interface{}{}
 					for _, field := range fields {
-						var fieldAST *ObjectField
-						var fieldValue interface{}
-						var err error
-						if v, ok := fieldASTs[field.Name.Value]; ok {
-							fieldAST = v
+						var fieldValueAST ASTNode
+						if fieldAST, ok := fieldASTs[field.Name.Value]; ok {
+							fieldValueAST = fieldAST.Value
 						}
-						if fieldAST != nil {
-							fieldValue, err = executor.valueFromAST(context, fieldAST.Value, field.Type, variables, variableDefinitionIndex)
-							if err != nil {
-								if gqlErr, ok := err.(*GraphQLError); ok {
-									return nil, &GraphQLError{
-										Message: fmt.Sprintf("In field %q: %s", field.Name.Value, gqlErr.Message),
-									}
-								}
-								return nil, err
-							}
-						} else {
-							fieldValue, err = executor.valueFromAST(context, nil, field.Type, variables, variableDefinitionIndex)
-							if err != nil {
-								if gqlErr, ok := err.(*GraphQLError); ok {
-									return nil, &GraphQLError{
-										Message: fmt.Sprintf("In field %q: %s", field.Name.Value, gqlErr.Message),
-									}
+						fieldValue, ok, err := executor.inputValueFromAST(context, field, fieldValueAST, variables, variableDefinitionIndex)
+						if err != nil {
+							if gqlErr, ok := err.(*GraphQLError); ok {
+								return nil, &GraphQLError{
+									Message: fmt.Sprintf("In field %q: %s", field.Name.Value, gqlErr.Message),
 								}
-								return nil, err
 							}
+							return nil, err
 						}
-						if executor.IsNullish(fieldValue) {
-							fieldValue, err = executor.valueFromAST(context, field.DefaultValue, field.Type, nil, nil)
-							if err != nil {
-								if gqlErr, ok := err.(*GraphQLError); ok {
-									return nil, &GraphQLError{
-										Message: fmt.Sprintf("In field %q: %s", field.Name.Value, gqlErr.Message),
-									}
-								}
-								return nil, err
-							}
-						}
-						if !executor.IsNullish(fieldValue) {
+						if ok {
 							result[field.Name.Value] = fieldValue
 						}
 					}
@@ -581,10 +805,14 @@ func NewExecutor(schemaDefinition string, queryRoot string, mutationRoot string,
 	}
 
 	return &Executor{
-		Debug:     false,
-		Schema:    schema,
-		Resolvers: resolvers,
-		Scalars:   map[string]*Scalar{},
+		Debug:           false,
+		Schema:          schema,
+		Resolvers:       resolvers,
+		Scalars:         map[string]*Scalar{},
+		ValidationRules: validation.SpecifiedRules,
+		Directives:      map[string]DirectiveFn{},
+		Loaders:         map[string]dataloader.BatchFn{},
+		Complexity:      map[string]ComplexityFn{},
 		IsNullish: func(value interface{}) bool {
 			if value, ok := value.(string); ok {
 				return value == ""
@@ -612,118 +840,29 @@ func NewExecutor(schemaDefinition string, queryRoot string, mutationRoot string,
 					},
 				}
 			}
+			if err.Path != nil {
+				result["path"] = err.Path
+			}
+			if extendedErr, ok := err.Error.(*ExtendedError); ok && extendedErr.Extensions != nil {
+				result["extensions"] = extendedErr.Extensions
+			}
 			return result
 		},
 	}, nil
 }
 
+// PrintSchema returns the schema in the GraphQL schema language, without the
+// built-in introspection types.
 func (executor *Executor) PrintSchema() string {
-	introspectionQuery := `
-	query IntrospectionQuery {
-		__schema {
-			queryType { name }
-			mutationType { name }
-			subscriptionType { name }
-			types {
-				...FullType
-			}
-			directives {
-				name
-				description
-				args {
-					...InputValue
-				}
-				onOperation
-				onFragment
-				onField
-			}
-		}
-	}
-	fragment FullType on __Type {
-		kind
-		name
-		description
-		fields(includeDeprecated: true) {
-			name
-			description
-			args {
-				...InputValue
-			}
-			type {
-				...TypeRef
-			}
-			isDeprecated
-			deprecationReason
-		}
-		inputFields {
-			...InputValue
-		}
-		interfaces {
-			...TypeRef
-		}
-		enumValues(includeDeprecated: true) {
-			name
-			description
-			isDeprecated
-			deprecationReason
-		}
-		possibleTypes {
-			...TypeRef
-		}
-	}
-	fragment InputValue on __InputValue {
-		name
-		description
-		type { ...TypeRef }
-		defaultValue
-	}
-	fragment TypeRef on __Type {
-		kind
-		name
-		ofType {
-			kind
-			name
-			ofType {
-				kind
-				name
-				ofType {
-					kind
-					name
-					ofType {
-						kind
-						name
-						ofType {
-							kind
-							name
-							ofType {
-								kind
-								name
-								ofType {
-									kind
-									name
-								}
-							}
-						}
-					}
-				}
-			}
-		}
-	}
-	`
-	result, err := executor.Execute(nil, introspectionQuery, map[string]interface{}{}, "IntrospectionQuery")
-	if err != nil {
-		panic(err)
-	}
-	output, err := json.MarshalIndent(result, "", "    ")
-	if err != nil {
-		panic(err)
-	}
-	return string(output)
+	return executor.Schema.Print(&PrintSchemaParams{})
 }
 
 // We use this function to capture any graphql errors that may have escaped capture within the request context.
 // This happens because we try to conform to the same behaviour as graphql-js with respect to handling errors on non-nullable fields
 func handleGQLError(result map[string]interface{}, err error) (map[string]interface{}, error) {
+	if err == errNulled {
+		return result, nil
+	}
 	if gqlErr, ok := err.(*GraphQLError); ok {
 		errors, ok := result["errors"].([]map[string]interface{})
 		if !ok {
@@ -749,6 +888,9 @@ func handleGQLError(result map[string]interface{}, err error) (map[string]interf
 				},
 			}
 		}
+		if gqlErr.Path != nil {
+			gqlErrorData["path"] = gqlErr.Path
+		}
 		errors = append(errors, gqlErrorData)
 		result["errors"] = errors
 		return result, nil
@@ -756,25 +898,173 @@ func handleGQLError(result map[string]interface{}, err error) (map[string]interf
 	return nil, err
 }
 
-func (executor *Executor) Execute(context interface{}, request string, variables map[string]interface{}, operationName string) (map[string]interface{}, error) {
-	parser := &Parser{}
+func (executor *Executor) Execute(appContext interface{}, request string, variables map[string]interface{}, operationName string) (map[string]interface{}, error) {
+	return executor.ExecuteContext(context.Background(), appContext, request, variables, operationName)
+}
+
+// ExecuteContext executes the request like Execute but stops resolving fields
+// once ctx is cancelled or its deadline is exceeded. Fields that were not
+// resolved in time are reported as errors at their location.
+func (executor *Executor) ExecuteContext(ctx context.Context, appContext interface{}, request string, variables map[string]interface{}, operationName string) (map[string]interface{}, error) {
+	result, _, err := executor.execute(ctx, appContext, request, variables, operationName, nil)
+	return result, err
+}
+
+// execute executes the request. When incremental is not nil the fragments
+// deferred and the list items streamed by the operation are added to it
+// rather than being executed. The request context is returned along with the
+// result once the selected operation has been executed.
+func (executor *Executor) execute(ctx context.Context, appContext interface{}, request string, variables map[string]interface{}, operationName string, incremental *incrementalState) (map[string]interface{}, *RequestContext, error) {
 	result := map[string]interface{}{}
+	reqCtx, selectedOperation, err := executor.startRequest(ctx, appContext, request, variables, operationName, incremental, result)
+	if err != nil {
+		return nil, nil, err
+	}
+	if reqCtx == nil {
+		return result, nil, nil
+	}
+	if selectedOperation != nil {
+		data, err := executor.executeOperation(reqCtx, selectedOperation)
+		if err != nil {
+			result, err = handleGQLError(result, err)
+			if err != nil {
+				return nil, nil, err
+			}
+			data = map[string]interface{}(nil)
+		}
+		result["data"] = data
+	}
+
+	result, err = executor.completeResult(reqCtx, result)
+	if err != nil || selectedOperation == nil {
+		return result, nil, err
+	}
+	return result, reqCtx, nil
+}
+
+// startRequest parses the request and selects the operation to execute,
+// adding the errors preventing its execution to the result. No request
+// context is returned when the request could not be parsed, and no operation
+// when none could be selected or the operation exceeds the limits of the
+// executor, in which case the result must still be completed.
+func (executor *Executor) startRequest(ctx context.Context, appContext interface{}, request string, variables map[string]interface{}, operationName string, incremental *incrementalState, result map[string]interface{}) (*RequestContext, *OperationDefinition, error) {
+	if errors := executor.persistedQueryErrors(request); errors != nil {
+		result["errors"] = errors
+		return nil, nil, nil
+	}
+	document, plans, errs := executor.parseRequest(request)
+	if len(errs) > 0 {
+		for _, err := range errs {
+			if _, err := handleGQLError(result, err); err != nil {
+				return nil, nil, err
+			}
+		}
+		return nil, nil, nil
+	}
+
+	reqCtx := &RequestContext{
+		Ctx:         ctx,
+		AppContext:  appContext,
+		Document:    document,
+		ErrorList:   &ErrorList{},
+		Variables:   variables,
+		Loaders:     executor.loaders(ctx),
+		workers:     executor.Scheduler.workers(),
+		plans:       plans,
+		incremental: incremental,
+	}
+
+	selectedOperation := executor.selectOperation(reqCtx, operationName)
+	if selectedOperation == nil || !executor.withinLimits(reqCtx, selectedOperation) {
+		return reqCtx, nil, nil
+	}
+	if executor.Before != nil {
+		err := executor.Before(&ResolveParams{
+			Executor: executor,
+			Schema:   executor.Schema.Document,
+			Request:  reqCtx.Document,
+			Context:  reqCtx.AppContext,
+			Ctx:      reqCtx.Ctx,
+		}, selectedOperation.Operation)
+		if err != nil {
+			if _, err := handleGQLError(result, err); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	reqCtx.VariableDefinitionIndex = selectedOperation.VariableDefinitionIndex
+	return reqCtx, selectedOperation, nil
+}
+
+// executeOperation executes the selected query or mutation and returns its
+// data.
+func (executor *Executor) executeOperation(reqCtx *RequestContext, operation *OperationDefinition) (interface{}, error) {
+	switch operation.Operation {
+	case "query":
+		return executor.selectionSet(reqCtx, nil, false, executor.Schema.QueryRoot, map[string]interface{}{}, operation.SelectionSet)
+	case "mutation":
+		return executor.selectionSet(reqCtx, nil, false, executor.Schema.MutationRoot, map[string]interface{}{}, operation.SelectionSet)
+	case "subscription":
+		reqCtx.ErrorList.Add(&Error{
+			Error: &GraphQLError{
+				Message: "GraphQL Runtime Error: Subscription operations must be executed with Subscribe",
+			},
+		})
+	}
+	return map[string]interface{}(nil), nil
+}
+
+// parseRequest parses the request document and runs the executor's validation
+// rules against it, or takes the document from the document cache. The
+// document should only be executed if no errors were returned. The field plans
+// of the document are returned along with it when it is cached.
+func (executor *Executor) parseRequest(request string) (*Document, *fieldPlans, []error) {
+	var key [sha256.Size]byte
+	if executor.DocumentCache != nil {
+		key = sha256.Sum256([]byte(request))
+		if cached, ok := executor.DocumentCache.get(key); ok {
+			return cached.document, cached.plans, nil
+		}
+	}
+	parser := &Parser{}
 	document, err := parser.Parse(&ParseParams{
 		Source: request,
 	})
 	if err != nil {
-		return handleGQLError(result, err)
+		return nil, nil, []error{err}
 	}
 
-	reqCtx := &RequestContext{
-		AppContext: context,
-		Document:   document,
-		ErrorList:  &ErrorList{},
-		Variables:  variables,
+	if len(executor.ValidationRules) > 0 {
+		validationErrors := validation.Validate(&validation.ValidateParams{
+			Schema:           executor.Schema.Document,
+			QueryRoot:        executor.Schema.QueryRoot,
+			MutationRoot:     executor.Schema.MutationRoot,
+			SubscriptionRoot: executor.Schema.SubscriptionRoot,
+			Document:         document,
+			Rules:            executor.ValidationRules,
+		})
+		if len(validationErrors) > 0 {
+			errs := []error{}
+			for _, validationError := range validationErrors {
+				errs = append(errs, validationError)
+			}
+			return nil, nil, errs
+		}
+	}
+	if executor.DocumentCache == nil {
+		return document, nil, nil
 	}
+	plans := newFieldPlans(document)
+	executor.DocumentCache.add(key, &cachedDocument{
+		document: document,
+		plans:    plans,
+	})
+	return document, plans, nil
+}
 
+func (executor *Executor) selectOperation(reqCtx *RequestContext, operationName string) *OperationDefinition {
 	var selectedOperation *OperationDefinition
-	for _, definition := range document.Definitions {
+	for _, definition := range reqCtx.Document.Definitions {
 		if operationDefinition, ok := definition.(*OperationDefinition); ok {
 			if (operationDefinition.Name != nil && operationDefinition.Name.Value == operationName) || operationName == "" {
 				if selectedOperation == nil {
@@ -785,7 +1075,7 @@ func (executor *Executor) Execute(context interface{}, request string, variables
 							Message: "GraphQL Runtime Error: Must provide operation name if query contains multiple operations",
 						},
 					})
-					break
+					return selectedOperation
 				}
 			}
 		}
@@ -796,37 +1086,13 @@ func (executor *Executor) Execute(context interface{}, request string, variables
 				Message: fmt.Sprintf("GraphQL Runtime Error: Operation with name %q not found in document", operationName),
 			},
 		})
-	} else {
-		if executor.Before != nil {
-			err = executor.Before(&ResolveParams{
-				Executor: executor,
-				Schema:   executor.Schema.Document,
-				Request:  reqCtx.Document,
-				Context:  reqCtx.AppContext,
-			}, selectedOperation.Operation)
-			if err != nil {
-				result, err = handleGQLError(result, err)
-				if err != nil {
-					return nil, err
-				}
-			}
-		}
-		reqCtx.VariableDefinitionIndex = selectedOperation.VariableDefinitionIndex
-		var data map[string]interface{}
-		if selectedOperation.Operation == "query" {
-			data, err = executor.selectionSet(reqCtx, false, executor.Schema.QueryRoot, map[string]interface{}{}, selectedOperation.SelectionSet)
-		} else if selectedOperation.Operation == "mutation" {
-			data, err = executor.selectionSet(reqCtx, false, executor.Schema.MutationRoot, map[string]interface{}{}, selectedOperation.SelectionSet)
-		}
-		if err != nil {
-			result, err = handleGQLError(result, err)
-			if err != nil {
-				return nil, err
-			}
-		}
-		result["data"] = data
 	}
+	return selectedOperation
+}
 
+// completeResult adds the errors collected during execution to the result and
+// runs the After middleware.
+func (executor *Executor) completeResult(reqCtx *RequestContext, result map[string]interface{}) (map[string]interface{}, error) {
 	if len(reqCtx.ErrorList.Errors) > 0 {
 		errors, ok := result["errors"].([]map[string]interface{})
 		if !ok {
@@ -840,11 +1106,12 @@ func (executor *Executor) Execute(context interface{}, request string, variables
 	}
 
 	if executor.After != nil {
-		err = executor.After(&ResolveParams{
+		err := executor.After(&ResolveParams{
 			Executor: executor,
 			Schema:   executor.Schema.Document,
 			Request:  reqCtx.Document,
 			Context:  reqCtx.AppContext,
+			Ctx:      reqCtx.Ctx,
 		}, result)
 		if err != nil {
 			return nil, err
@@ -854,18 +1121,33 @@ func (executor *Executor) Execute(context interface{}, request string, variables
 	return result, nil
 }
 
-func (executor *Executor) selectionSet(reqCtx *RequestContext, isParallel bool, objectType *ObjectTypeDefinition, source interface{}, selectionSet *SelectionSet) (map[string]interface{}, error) {
+// selectionSet executes the selection set on the object and returns the
+// resulting object, a map[string]interface{} or an *OrderedMap when
+// OrderedResults is set.
+func (executor *Executor) selectionSet(reqCtx *RequestContext, path *ResponsePath, isParallel bool, objectType *ObjectTypeDefinition, source interface{}, selectionSet *SelectionSet) (interface{}, error) {
 	//log.Printf("collecting fields")
-	groupedFields, err := executor.collectFields(reqCtx, objectType, selectionSet, &utils.Set{})
+	groupedFields, deferred, err := executor.groupedFields(reqCtx, objectType, selectionSet)
 	if err != nil {
 		return nil, err
 	}
 	//log.Printf("resolving fields")
-	return executor.resolveGroupedFields(reqCtx, isParallel, objectType, source, groupedFields)
-
+	result, err := executor.resolveGroupedFields(reqCtx, path, isParallel, objectType, source, groupedFields)
+	if err != nil {
+		return nil, err
+	}
+	if len(deferred) > 0 {
+		executor.deferFragments(reqCtx, path, isParallel, objectType, source, deferred)
+	}
+	if executor.OrderedResults {
+		return orderedObject(groupedFields, result), nil
+	}
+	return result, nil
 }
 
-func (executor *Executor) collectFields(reqCtx *RequestContext, objectType *ObjectTypeDefinition, selectionSet *SelectionSet, visitedFragments *utils.Set) ([]*GroupedField, error) {
+// collectFields groups the fields of the selection set by response key. When
+// deferred is not nil the fragments carrying the @defer directive are added to
+// it instead of having their fields collected.
+func (executor *Executor) collectFields(reqCtx *RequestContext, objectType *ObjectTypeDefinition, selectionSet *SelectionSet, visitedFragments *utils.Set, deferred *[]*deferredFragment) ([]*GroupedField, error) {
 	groupedFieldIndex := map[string]*GroupedField{}
 	groupedFields := []*GroupedField{}
 	for _, item := range selectionSet.Selections {
@@ -975,7 +1257,17 @@ func (executor *Executor) collectFields(reqCtx *RequestContext, objectType *Obje
 			if !executor.doesFragmentTypeApply(objectType, fragment.TypeCondition) {
 				continue
 			}
-			fragmentGroupedFields, err := executor.collectFields(reqCtx, objectType, fragment.SelectionSet, visitedFragments)
+			if deferred != nil {
+				fragment, err := executor.deferredFragment(reqCtx, selection.DirectiveIndex, fragment.SelectionSet)
+				if err != nil {
+					return nil, err
+				}
+				if fragment != nil {
+					*deferred = append(*deferred, fragment)
+					continue
+				}
+			}
+			fragmentGroupedFields, err := executor.collectFields(reqCtx, objectType, fragment.SelectionSet, visitedFragments, deferred)
 			if err != nil {
 				return nil, err
 			}
@@ -1016,7 +1308,17 @@ func (executor *Executor) collectFields(reqCtx *RequestContext, objectType *Obje
 			if selection.TypeCondition != nil && !executor.doesFragmentTypeApply(objectType, selection.TypeCondition) {
 				continue
 			}
-			fragmentGroupedFields, err := executor.collectFields(reqCtx, objectType, selection.SelectionSet, visitedFragments)
+			if deferred != nil {
+				fragment, err := executor.deferredFragment(reqCtx, selection.DirectiveIndex, selection.SelectionSet)
+				if err != nil {
+					return nil, err
+				}
+				if fragment != nil {
+					*deferred = append(*deferred, fragment)
+					continue
+				}
+			}
+			fragmentGroupedFields, err := executor.collectFields(reqCtx, objectType, selection.SelectionSet, visitedFragments, deferred)
 			if err != nil {
 				return nil, err
 			}
@@ -1054,69 +1356,82 @@ func (executor *Executor) doesFragmentTypeApply(objectType *ObjectTypeDefinition
 	return false
 }
 
-func (executor *Executor) resolveGroupedFields(reqCtx *RequestContext, isParallel bool, objectType *ObjectTypeDefinition, source interface{}, groupedFields []*GroupedField) (map[string]interface{}, error) {
+func (executor *Executor) resolveGroupedFields(reqCtx *RequestContext, path *ResponsePath, isParallel bool, objectType *ObjectTypeDefinition, source interface{}, groupedFields []*GroupedField) (map[string]interface{}, error) {
 	result := map[string]interface{}{}
 
-	// TODO: Use go routines?
 	if isParallel && !executor.Debug {
+		keys := make([]string, len(groupedFields))
+		values := make([]interface{}, len(groupedFields))
+		errs := make([]error, len(groupedFields))
+		complete := func(index int, entry *fieldEntry) {
+			keys[index], values[index], errs[index] = executor.completeFieldEntry(reqCtx, objectType, entry)
+		}
+		if prepared, ok := source.(*preparedObject); ok {
+			executor.scheduleFields(reqCtx, objectType, prepared.value, groupedFields, func(index int) {
+				complete(index, prepared.entries[index])
+			})
+		} else if reqCtx.Loaders != nil {
+			// Run the resolvers of the whole tree level by level before
+			// completing any value, so that the keys loaded by the resolvers
+			// of a level are loaded in a single batch
+			entries := executor.resolveFieldEntries(reqCtx, path, objectType, source, groupedFields)
+			executor.prepareFieldEntries(reqCtx, entries)
+			executor.scheduleFields(reqCtx, objectType, source, groupedFields, func(index int) {
+				complete(index, entries[index])
+			})
+		} else {
+			executor.scheduleFields(reqCtx, objectType, source, groupedFields, func(index int) {
+				complete(index, executor.resolveFieldEntry(reqCtx, path, objectType, source, groupedFields[index]))
+			})
+		}
+		if err := firstError(reqCtx, path, errs); err != nil {
+			return nil, err
+		}
+		for index, key := range keys {
+			if key != "" {
+				result[key] = values[index]
+			}
+		}
+
+	} else if reqCtx.Loaders != nil && objectType != executor.Schema.MutationRoot {
+		// The resolvers still run one after the other, but the values are
+		// completed once the resolvers of every level have run
+		entries := make([]*fieldEntry, len(groupedFields))
+		for index, groupForResponseKey := range groupedFields {
+			entries[index] = executor.resolveFieldEntry(reqCtx, path, objectType, source, groupForResponseKey)
+		}
+		executor.prepareFieldEntries(reqCtx, entries)
 		errs := []error{}
-		panics := []interface{}{}
-		wg := sync.WaitGroup{}
-		mutex := sync.Mutex{}
-		errMutex := sync.Mutex{}
-		for _, groupForResponseKey := range groupedFields {
-			//log.Printf("evaluating field entry for '%s'", responseKey)
-			wg.Add(1)
-			go func(responseKey string, fields []*Field) {
-				defer func() {
-					if r := recover(); r != nil {
-						fmt.Printf("panic: %v\n%s", r, debug.Stack())
-						errMutex.Lock()
-						panics = append(panics, r)
-						errMutex.Unlock()
-						wg.Done()
-					}
-				}()
-				var key string
-				var value interface{}
-				var err error
-				if src, ok := source.(func() (interface{}, error)); ok {
-					value, err = src()
-					if err == nil {
-						key, value, err = executor.getFieldEntry(reqCtx, objectType, value, responseKey, fields)
-					}
-				} else {
-					key, value, err = executor.getFieldEntry(reqCtx, objectType, source, responseKey, fields)
-				}
-				if err != nil {
-					errMutex.Lock()
-					errs = append(errs, err)
-					errMutex.Unlock()
-				}
-				//log.Printf("Adding '%s' with value '%#v' to response", key, value)
-				if key != "" {
-					//log.Printf("Adding key '%s' with value '%#v' to response", responseKey, value)
-					mutex.Lock()
-					result[responseKey] = value
-					mutex.Unlock()
+		for _, entry := range entries {
+			key, value, err := executor.completeFieldEntry(reqCtx, objectType, entry)
+			if err != nil {
+				if _, ok := err.(*GraphQLError); !ok {
+					return nil, err
 				}
-				wg.Done()
-			}(groupForResponseKey.ResponseKey, groupForResponseKey.Fields)
-		}
-		wg.Wait()
-		if len(panics) > 0 {
-			panic(panics[0])
+				errs = append(errs, err)
+				continue
+			}
+			if key != "" {
+				result[key] = value
+			}
 		}
-		if len(errs) > 0 {
-			return nil, errs[0]
+		if err := firstError(reqCtx, path, errs); err != nil {
+			return nil, err
 		}
 
 	} else {
+		// The remaining fields are still executed after a field failed, so
+		// that the failures of its siblings are reported as well
+		errs := []error{}
 		for _, groupForResponseKey := range groupedFields {
 			//log.Printf("evaluating field entry for '%s'", responseKey)
-			key, value, err := executor.getFieldEntry(reqCtx, objectType, source, groupForResponseKey.ResponseKey, groupForResponseKey.Fields)
+			key, value, err := executor.getFieldEntry(reqCtx, path, objectType, source, groupForResponseKey.ResponseKey, groupForResponseKey.Fields)
 			if err != nil {
-				return nil, err
+				if _, ok := err.(*GraphQLError); !ok {
+					return nil, err
+				}
+				errs = append(errs, err)
+				continue
 			}
 			//log.Printf("Adding '%s' with value '%#v' to response", key, value)
 			if key != "" {
@@ -1124,83 +1439,177 @@ func (executor *Executor) resolveGroupedFields(reqCtx *RequestContext, isParalle
 				result[groupForResponseKey.ResponseKey] = value
 			}
 		}
+		if err := firstError(reqCtx, path, errs); err != nil {
+			return nil, err
+		}
 	}
 	return result, nil
 }
 
-func (executor *Executor) getFieldEntry(reqCtx *RequestContext, objectType *ObjectTypeDefinition, object interface{}, responseKey string, fields []*Field) (string, interface{}, error) {
-	firstField := fields[0]
-	//log.Printf("Test %#v", firstField.Name.Value)
-	fieldType := executor.getFieldTypeFromObjectType(objectType, firstField)
-	if fieldType == nil {
+// scheduleFields runs the task of every field with the scheduler and waits for
+// all of them to complete.
+func (executor *Executor) scheduleFields(reqCtx *RequestContext, objectType *ObjectTypeDefinition, source interface{}, groupedFields []*GroupedField, task func(index int)) {
+	batch := executor.Scheduler.batch(reqCtx)
+	for index, groupForResponseKey := range groupedFields {
+		index := index
+		// Once the request has been cancelled the remaining fields are
+		// resolved in place so that they are reported without spawning
+		// goroutines
+		inline := (reqCtx.Ctx != nil && reqCtx.Ctx.Err() != nil) || executor.inlineField(objectType, source, groupForResponseKey.Fields[0])
+		batch.do(inline, func() {
+			task(index)
+		})
+	}
+	batch.wait()
+}
+
+// fieldEntry is a field whose resolver has run but whose value has not been
+// completed yet.
+type fieldEntry struct {
+	responseKey string
+	fields      []*Field
+	fieldType   ASTNode
+	value       interface{}
+	err         error
+	path        *ResponsePath
+}
+
+// resolveFieldEntries runs the resolvers of the fields with the scheduler.
+func (executor *Executor) resolveFieldEntries(reqCtx *RequestContext, path *ResponsePath, objectType *ObjectTypeDefinition, source interface{}, groupedFields []*GroupedField) []*fieldEntry {
+	entries := make([]*fieldEntry, len(groupedFields))
+	executor.scheduleFields(reqCtx, objectType, source, groupedFields, func(index int) {
+		entries[index] = executor.resolveFieldEntry(reqCtx, path, objectType, source, groupedFields[index])
+	})
+	return entries
+}
+
+func (executor *Executor) resolveFieldEntry(reqCtx *RequestContext, path *ResponsePath, objectType *ObjectTypeDefinition, object interface{}, groupForResponseKey *GroupedField) *fieldEntry {
+	entry := &fieldEntry{
+		responseKey: groupForResponseKey.ResponseKey,
+		fields:      groupForResponseKey.Fields,
+		path:        path.WithKey(groupForResponseKey.ResponseKey),
+	}
+	if src, ok := object.(func() (interface{}, error)); ok {
+		object, entry.err = src()
+		if entry.err != nil {
+			return entry
+		}
+	}
+	entry.fieldType = executor.getFieldTypeFromObjectType(objectType, entry.fields[0])
+	if entry.fieldType == nil {
+		return entry
+	}
+	entry.value, entry.err = executor.resolveFieldOnObject(reqCtx, entry.path, objectType, object, entry.fieldType, entry.fields[0])
+	return entry
+}
+
+func (executor *Executor) completeFieldEntry(reqCtx *RequestContext, objectType *ObjectTypeDefinition, entry *fieldEntry) (string, interface{}, error) {
+	if entry.err != nil {
+		return "", nil, entry.err
+	}
+	if entry.fieldType == nil {
 		//log.Printf("field type of selection '%s' could not be determined", firstField.Name.Value)
 		return "", nil, nil
 	}
-	resolvedObject, err := executor.resolveFieldOnObject(reqCtx, objectType, object, fieldType, firstField)
-	//log.Printf("field %s resolved to %#v", firstField.Name.Value, resolvedObject)
+	subSelectionSet := executor.mergeSelectionSets(entry.fields)
+	responseValue, err := executor.completeValueCatchingError(reqCtx, entry.path, objectType, entry.fieldType, entry.fields[0], entry.value, subSelectionSet)
 	if err != nil {
 		return "", nil, err
 	}
-	subSelectionSet := executor.mergeSelectionSets(fields)
-	responseValue, err := executor.completeValueCatchingError(reqCtx, objectType, fieldType, firstField, resolvedObject, subSelectionSet)
-	if err != nil {
-		return "", nil, err
-	}
-	if resolvedObject == nil {
-		return responseKey, nil, nil
+	if entry.value == nil {
+		return entry.responseKey, nil, nil
 	}
-	return responseKey, responseValue, nil
+	return entry.responseKey, responseValue, nil
+}
+
+func (executor *Executor) getFieldEntry(reqCtx *RequestContext, path *ResponsePath, objectType *ObjectTypeDefinition, object interface{}, responseKey string, fields []*Field) (string, interface{}, error) {
+	return executor.completeFieldEntry(reqCtx, objectType, executor.resolveFieldEntry(reqCtx, path, objectType, object, &GroupedField{
+		ResponseKey: responseKey,
+		Fields:      fields,
+	}))
 }
 
 func (executor *Executor) mergeSelectionSets(fields []*Field) *SelectionSet {
-	selectionSet := &SelectionSet{}
-	selectionSet.Selections = []ASTNode{}
+	selectionSets := []*SelectionSet{}
 	for _, field := range fields {
 		if field.SelectionSet == nil || len(field.SelectionSet.Selections) == 0 {
 			continue
 		}
-		selectionSet.Selections = append(selectionSet.Selections, field.SelectionSet.Selections...)
+		selectionSets = append(selectionSets, field.SelectionSet)
+	}
+	// The selection set of a single field is returned as is so that the
+	// fields collected from it can be reused by the plans of the document
+	if len(selectionSets) == 1 {
+		return selectionSets[0]
+	}
+	selectionSet := &SelectionSet{}
+	selectionSet.Selections = []ASTNode{}
+	for _, fieldSelectionSet := range selectionSets {
+		selectionSet.Selections = append(selectionSet.Selections, fieldSelectionSet.Selections...)
 	}
 	return selectionSet
 }
 
-func (executor *Executor) completeValueCatchingError(reqCtx *RequestContext, objectType *ObjectTypeDefinition, fieldType ASTNode, field *Field, result interface{}, subSelectionSet *SelectionSet) (interface{}, error) {
+func (executor *Executor) completeValueCatchingError(reqCtx *RequestContext, path *ResponsePath, objectType *ObjectTypeDefinition, fieldType ASTNode, field *Field, result interface{}, subSelectionSet *SelectionSet) (interface{}, error) {
 	if _, ok := fieldType.(*NonNullType); ok {
-		return executor.completeValue(reqCtx, objectType, fieldType, field, result, subSelectionSet)
+		return executor.completeValue(reqCtx, path, objectType, fieldType, field, result, subSelectionSet)
 	}
-	result, err := executor.completeValue(reqCtx, objectType, fieldType, field, result, subSelectionSet)
+	result, err := executor.completeValue(reqCtx, path, objectType, fieldType, field, result, subSelectionSet)
 	if err != nil {
-		if gqlError, ok := err.(*GraphQLError); ok {
-			var errField *Field
-			if gqlError.Field != nil {
-				errField = gqlError.Field
-			}
-			reqCtx.ErrorList.Add(&Error{
-				Error: err,
-				Field: errField,
-			})
-			return nil, nil
-		} else {
-			return nil, err
-		}
+		return nil, catchError(reqCtx, path, err)
 	}
 	return result, err
 }
 
-func (executor *Executor) completeValue(reqCtx *RequestContext, objectType *ObjectTypeDefinition, fieldType ASTNode, field *Field, result interface{}, subSelectionSet *SelectionSet) (interface{}, error) {
+// catchError adds a GraphQL error raised while completing the nullable value
+// at the given path to the errors of the request, the value being completed
+// to null. Any other error is returned.
+func catchError(reqCtx *RequestContext, path *ResponsePath, err error) error {
+	if err == errNulled {
+		return nil
+	}
+	gqlError, ok := err.(*GraphQLError)
+	if !ok {
+		return err
+	}
+	var errField *Field
+	if gqlError.Field != nil {
+		errField = gqlError.Field
+	}
+	errPath := gqlError.Path
+	if errPath == nil {
+		errPath = path.AsArray()
+	}
+	reqCtx.ErrorList.Add(&Error{
+		Error: err,
+		Field: errField,
+		Path:  errPath,
+	})
+	return nil
+}
+
+func (executor *Executor) completeValue(reqCtx *RequestContext, path *ResponsePath, objectType *ObjectTypeDefinition, fieldType ASTNode, field *Field, result interface{}, subSelectionSet *SelectionSet) (completed interface{}, err error) {
+	defer executor.recoverPanic(reqCtx, field, path, &err)
 	//var err error
 	//log.Printf("completing value on %#v", result)
+	result = executor.resolveThunk(reqCtx, path, field, result)
 	if nonNullType, ok := fieldType.(*NonNullType); ok {
 		innerType := nonNullType.Type
-		completedResult, err := executor.completeValue(reqCtx, objectType, innerType, field, result, subSelectionSet)
+		completedResult, err := executor.completeValue(reqCtx, path, objectType, innerType, field, result, subSelectionSet)
 		//log.Printf("completed result of %#v is %#v", result, completedResult)
 		if err != nil {
 			return nil, err
 		}
 		if completedResult == nil {
+			// The error of a field that failed has already been reported
+			// at its path and only has to null the nearest nullable value
+			if reqCtx.ErrorList.reported(path.AsArray()) {
+				return nil, errNulled
+			}
 			return nil, &GraphQLError{
 				Message: fmt.Sprintf("Cannot return null for non-nullable field %s.%s", objectType.Name.Value, field.Name.Value),
 				Field:   field,
+				Path:    path.AsArray(),
 			}
 		}
 		return completedResult, nil
@@ -1217,54 +1626,50 @@ func (executor *Executor) completeValue(reqCtx *RequestContext, objectType *Obje
 			return nil, &GraphQLError{
 				Message: "Expected a list but did not find one",
 				Field:   field,
+				Path:    path.AsArray(),
 			}
 		}
 		resultLen := resultVal.Len()
+		initialCount, err := executor.streamInitialCount(reqCtx, path, field)
+		if err != nil {
+			return nil, err
+		}
+		if initialCount >= 0 && initialCount < resultLen {
+			// The remaining items are completed and sent once the initial
+			// payload has been sent
+			executor.streamItems(reqCtx, path, objectType, innerType, field, resultVal, initialCount, subSelectionSet)
+			resultLen = initialCount
+		}
 		completedResults := make([]interface{}, resultLen, resultLen)
-		errs := []error{}
-		panics := []interface{}{}
-		mutex := sync.Mutex{}
-		errMutex := sync.Mutex{}
-		wg := sync.WaitGroup{}
 
 		if !executor.Debug {
+			errs := make([]error, resultLen)
+			batch := executor.Scheduler.batch(reqCtx)
+			inline := executor.inlineItems(innerType)
+			var contextErr error
 			for index := 0; index < resultLen; index++ {
-				wg.Add(1)
-				go func(idx int) {
-					defer func() {
-						if r := recover(); r != nil {
-							fmt.Printf("panic: %v\n%s", r, debug.Stack())
-							errMutex.Lock()
-							panics = append(panics, r)
-							errMutex.Unlock()
-							wg.Done()
-						}
-					}()
-
-					val := resultVal.Index(idx).Interface()
-					completedItem, err := executor.completeValue(reqCtx, objectType, innerType, field, val, subSelectionSet)
-					if err != nil {
-						errMutex.Lock()
-						errs = append(errs, err)
-						errMutex.Unlock()
-					}
-					mutex.Lock()
-					completedResults[idx] = completedItem
-					mutex.Unlock()
-					wg.Done()
-				}(index)
+				// Stop scheduling items once the request has been cancelled
+				if err := executor.contextError(reqCtx, field); err != nil {
+					contextErr = err
+					break
+				}
+				index := index
+				batch.do(inline, func() {
+					val := resultVal.Index(index).Interface()
+					completedResults[index], errs[index] = executor.completeValueCatchingError(reqCtx, path.WithKey(index), objectType, innerType, field, val, subSelectionSet)
+				})
 			}
-			wg.Wait()
-			if len(panics) > 0 {
-				panic(panics[0])
+			batch.wait()
+			if err := firstError(reqCtx, path, errs); err != nil {
+				return nil, err
 			}
-			if len(errs) > 0 {
-				return nil, errs[0]
+			if contextErr != nil {
+				return nil, contextErr
 			}
 		} else {
 			for index := 0; index < resultLen; index++ {
 				val := resultVal.Index(index).Interface()
-				completedItem, err := executor.completeValue(reqCtx, objectType, innerType, field, val, subSelectionSet)
+				completedItem, err := executor.completeValueCatchingError(reqCtx, path.WithKey(index), objectType, innerType, field, val, subSelectionSet)
 				if err != nil {
 					return nil, err
 				}
@@ -1274,6 +1679,10 @@ func (executor *Executor) completeValue(reqCtx *RequestContext, objectType *Obje
 		return completedResults, nil
 	}
 
+	if prepared, ok := result.(*preparedObject); ok {
+		return executor.selectionSet(reqCtx, path, true, prepared.objectType, prepared, subSelectionSet)
+	}
+
 	switch typeName := fieldType.(*NamedType).Name.Value; typeName {
 	case "Int":
 		val, ok := utils.CoerceInt(result)
@@ -1299,6 +1708,7 @@ func (executor *Executor) completeValue(reqCtx *RequestContext, objectType *Obje
 			if err != nil {
 				if gqlErr, ok := err.(*GraphQLError); ok {
 					gqlErr.Field = field
+					gqlErr.Path = path.AsArray()
 				}
 				return nil, err
 			}
@@ -1330,6 +1740,7 @@ func (executor *Executor) completeValue(reqCtx *RequestContext, objectType *Obje
 				if err != nil {
 					if gqlErr, ok := err.(*GraphQLError); ok {
 						gqlErr.Field = field
+						gqlErr.Path = path.AsArray()
 					}
 					return nil, err
 				}
@@ -1341,153 +1752,217 @@ func (executor *Executor) completeValue(reqCtx *RequestContext, objectType *Obje
 		}
 
 		if objectType, ok := executor.Schema.Document.ObjectTypeIndex[typeName]; ok {
-			return executor.selectionSet(reqCtx, true, objectType, result, subSelectionSet)
+			return executor.selectionSet(reqCtx, path, true, objectType, result, subSelectionSet)
 		}
 		if interfaceType, ok := executor.Schema.Document.InterfaceTypeIndex[typeName]; ok {
-			objectType, err := executor.resolveAbstractType(reqCtx, field, interfaceType, result)
+			objectType, err := executor.resolveAbstractType(reqCtx, path, field, interfaceType, result)
 			if err != nil {
 				return nil, err
 			}
 			if objectType == nil {
 				return nil, nil
 			}
-			return executor.selectionSet(reqCtx, true, objectType, result, subSelectionSet)
+			return executor.selectionSet(reqCtx, path, true, objectType, result, subSelectionSet)
 		}
 		if unionType, ok := executor.Schema.Document.UnionTypeIndex[typeName]; ok {
-			objectType, err := executor.resolveAbstractType(reqCtx, field, unionType, result)
+			objectType, err := executor.resolveAbstractType(reqCtx, path, field, unionType, result)
 			if err != nil {
 				return nil, err
 			}
 			if objectType == nil {
 				return nil, nil
 			}
-			return executor.selectionSet(reqCtx, true, objectType, result, subSelectionSet)
+			return executor.selectionSet(reqCtx, path, true, objectType, result, subSelectionSet)
 		}
 
 		return nil, &GraphQLError{
 			Message: "Unknown type " + typeName,
 			Field:   field,
+			Path:    path.AsArray(),
 		}
 	}
 	return nil, nil
 }
 
-func (executor *Executor) resolveFieldOnObject(reqCtx *RequestContext, objectType *ObjectTypeDefinition, object interface{}, fieldType ASTNode, firstField *Field) (interface{}, error) {
+// contextError returns a located error for the field if the request context
+// has been cancelled or its deadline has been exceeded.
+func (executor *Executor) contextError(reqCtx *RequestContext, field *Field) error {
+	if reqCtx.Ctx == nil || reqCtx.Ctx.Err() == nil {
+		return nil
+	}
+	return &GraphQLError{
+		Message: fmt.Sprintf("GraphQL Runtime Error (%d:%d) %s", field.Name.LOC.Start.Line, field.Name.LOC.Start.Column, reqCtx.Ctx.Err().Error()),
+		Source:  field.Name.LOC.Source,
+		Start:   field.Name.LOC.Start,
+		End:     field.Name.LOC.End,
+		Field:   field,
+	}
+}
+
+func (executor *Executor) resolveFieldOnObject(reqCtx *RequestContext, path *ResponsePath, objectType *ObjectTypeDefinition, object interface{}, fieldType ASTNode, firstField *Field) (interface{}, error) {
+	// A panicking resolver is reported like a failing one
+	defer executor.recoverPanic(reqCtx, firstField, path, nil)
 
 	if firstField.Name.Value == "__typename" {
 		return objectType.Name.Value, nil
 	}
 
+	if err := executor.contextError(reqCtx, firstField); err != nil {
+		reqCtx.ErrorList.Add(&Error{
+			Error: err,
+			Field: firstField,
+			Path:  path.AsArray(),
+		})
+		return nil, nil
+	}
+
+	fieldDefinition := objectType.FieldIndex[firstField.Name.Value]
 	resolverName := objectType.Name.Value + "/" + firstField.Name.Value
-	if resolver, ok := executor.Resolvers[resolverName]; ok {
-		var resolveFn ResolveFn
-		var beforeFn BeforeFn
-		var afterFn AfterFn
-		var aroundFn AroundFn
-		fieldParams, ok := resolver.(*FieldParams)
-		if ok {
-			aroundFn = fieldParams.Around
-			beforeFn = fieldParams.Before
-			resolveFn = fieldParams.Resolve
-			afterFn = fieldParams.After
-		} else {
-			resolveFn = ResolveFn(resolver.(func(*ResolveParams) (interface{}, error)))
-		}
-		args, err := executor.argumentValues(reqCtx, objectType.FieldIndex[firstField.Name.Value].ArgumentIndex, firstField.ArgumentIndex, reqCtx.Variables, reqCtx.VariableDefinitionIndex)
-		if err != nil {
-			if gqlError, ok := err.(*GraphQLError); ok {
-				gqlError.Source = reqCtx.Document.LOC.Source
-				gqlError.Start = firstField.Name.LOC.Start
-				gqlError.End = firstField.Name.LOC.End
-			}
-			reqCtx.ErrorList.Add(&Error{
-				Error: err,
-				Field: firstField,
-			})
-			return nil, nil
+	resolver, hasResolver := executor.Resolvers[resolverName]
+	hasDirectives := false
+	for _, directive := range fieldDefinition.Directives {
+		if _, ok := executor.Directives[directive.Name.Value]; ok {
+			hasDirectives = true
+			break
 		}
+	}
+	if !hasResolver && !hasDirectives {
+		return executor.sourceValue(object, firstField), nil
+	}
 
-		// Execute the before function if it is defined
-		resolveParams := &ResolveParams{
-			Executor: executor,
-			Schema:   executor.Schema.Document,
-			Request:  reqCtx.Document,
-			Context:  reqCtx.AppContext,
-			Source:   object,
-			Args:     args,
-			Field:    firstField,
+	var resolveFn ResolveFn
+	var beforeFn BeforeFn
+	var afterFn AfterFn
+	var aroundFn AroundFn
+	if !hasResolver {
+		resolveFn = func(params *ResolveParams) (interface{}, error) {
+			return executor.sourceValue(params.Source, params.Field), nil
+		}
+	} else if fieldParams, ok := resolver.(*FieldParams); ok {
+		aroundFn = fieldParams.Around
+		beforeFn = fieldParams.Before
+		resolveFn = fieldParams.Resolve
+		afterFn = fieldParams.After
+	} else {
+		resolveFn = ResolveFn(resolver.(func(*ResolveParams) (interface{}, error)))
+	}
+	args, err := executor.argumentValues(reqCtx, fieldDefinition.ArgumentIndex, firstField.ArgumentIndex, reqCtx.Variables, reqCtx.VariableDefinitionIndex)
+	if err != nil {
+		if gqlError, ok := err.(*GraphQLError); ok {
+			gqlError.Source = reqCtx.Document.LOC.Source
+			gqlError.Start = firstField.Name.LOC.Start
+			gqlError.End = firstField.Name.LOC.End
 		}
+		reqCtx.ErrorList.Add(&Error{
+			Error: err,
+			Field: firstField,
+			Path:  path.AsArray(),
+		})
+		return nil, nil
+	}
+
+	resolveParams := &ResolveParams{
+		Executor: executor,
+		Schema:   executor.Schema.Document,
+		Request:  reqCtx.Document,
+		Context:  reqCtx.AppContext,
+		Ctx:      reqCtx.Ctx,
+		Source:   object,
+		Args:     args,
+		Field:    firstField,
+		Path:     path,
+		Done:     reqCtx.Done,
+		Loaders:  reqCtx.Loaders,
+	}
 
+	resolve := func(resolveParams *ResolveParams) (interface{}, error) {
+		// Execute the before function if it is defined
 		if beforeFn != nil {
 			beforeResult, err := beforeFn(resolveParams)
 			if err != nil {
-				reqCtx.ErrorList.Add(&Error{
-					Error: err,
-					Field: firstField,
-				})
-				return nil, nil
+				return nil, err
 			}
 			if beforeResult != nil {
 				return beforeResult, nil
 			}
 		}
 		var result interface{}
+		var err error
 		if aroundFn != nil {
 			result, err = aroundFn(resolveFn, resolveParams)
-			if err != nil {
-				reqCtx.ErrorList.Add(&Error{
-					Error: err,
-					Field: firstField,
-				})
-				return nil, nil
-			}
 		} else {
 			result, err = resolveFn(resolveParams)
-			if err != nil {
-				// TODO: Check how to proceed
-				reqCtx.ErrorList.Add(&Error{
-					Error: err,
-					Field: firstField,
-				})
-				return nil, nil
-			}
 		}
-
+		if err != nil {
+			return nil, err
+		}
 		if afterFn != nil {
-			afterResult, err := afterFn(resolveParams, result)
-			if err != nil {
-				// TODO: Check how to proceed
-				reqCtx.ErrorList.Add(&Error{
-					Error: err,
-					Field: firstField,
-				})
-				return nil, nil
-			}
-			return afterResult, nil
+			return afterFn(resolveParams, result)
 		}
-
 		return result, nil
 	}
 
+	// Wrap the resolution with the functions registered for the directives
+	// applied to the field definition, the first directive being outermost.
+	for i := len(fieldDefinition.Directives) - 1; i >= 0; i-- {
+		directive := fieldDefinition.Directives[i]
+		directiveFn, ok := executor.Directives[directive.Name.Value]
+		if !ok {
+			continue
+		}
+		argumentIndex := map[string]*InputValueDefinition{}
+		if directiveDefinition := validation.LookupDirective(executor.Schema.Document, directive.Name.Value); directiveDefinition != nil {
+			argumentIndex = directiveDefinition.ArgumentIndex
+		}
+		directiveArgs, err := executor.argumentValues(reqCtx, argumentIndex, directive.ArgumentIndex, nil, nil)
+		if err != nil {
+			reqCtx.ErrorList.Add(&Error{
+				Error: err,
+				Field: firstField,
+				Path:  path.AsArray(),
+			})
+			return nil, nil
+		}
+		next := resolve
+		resolve = func(resolveParams *ResolveParams) (interface{}, error) {
+			return directiveFn(next, resolveParams, directiveArgs)
+		}
+	}
+
+	result, err := resolve(resolveParams)
+	if err != nil {
+		reqCtx.ErrorList.Add(&Error{
+			Error: err,
+			Field: firstField,
+			Path:  path.AsArray(),
+		})
+		return nil, nil
+	}
+	return result, nil
+}
+
+// sourceValue resolves the field from the source object when no resolver is
+// defined for it, looking up the field in maps and the fields of structs.
+func (executor *Executor) sourceValue(object interface{}, firstField *Field) interface{} {
 	sourceVal := reflect.ValueOf(object)
 	sourceValType := sourceVal.Type()
 	sourceValKind := sourceValType.Kind()
 	if sourceVal.IsValid() && sourceValKind == reflect.Ptr {
 		sourceVal = sourceVal.Elem()
 		if !sourceVal.IsValid() {
-			return nil, nil
+			return nil
 		}
 		sourceValType = sourceVal.Type()
 		sourceValKind = sourceValType.Kind()
 	}
 	if !sourceVal.IsValid() {
-		return nil, nil
+		return nil
 	}
 
 	// try object as a map[string]interface
 	if sourceMap, ok := object.(map[string]interface{}); ok {
 		if property, ok := sourceMap[firstField.Name.Value]; ok {
-			return property, nil
+			return property
 		}
 	}
 
@@ -1498,7 +1973,7 @@ func (executor *Executor) resolveFieldOnObject(reqCtx *RequestContext, objectTyp
 			typeField := sourceValType.Field(i)
 			// try matching the field name first
 			if typeField.Name == firstField.Name.Value {
-				return valueField.Interface(), nil
+				return valueField.Interface()
 			}
 			tag := typeField.Tag
 
@@ -1519,16 +1994,16 @@ func (executor *Executor) resolveFieldOnObject(reqCtx *RequestContext, objectTyp
 			if valueField.IsValid() && valueField.Kind() == reflect.Ptr {
 				elem := valueField.Elem()
 				if elem.IsValid() {
-					return valueField.Interface(), nil
+					return valueField.Interface()
 				}
-				return nil, nil
+				return nil
 			}
-			return valueField.Interface(), nil
+			return valueField.Interface()
 		}
 	}
 
 	// last resort, return nil
-	return nil, nil
+	return nil
 }
 
 func (executor *Executor) getFieldTypeFromObjectType(objectType *ObjectTypeDefinition, firstField *Field) ASTNode {
@@ -1547,13 +2022,14 @@ func (executor *Executor) getFieldTypeFromObjectType(objectType *ObjectTypeDefin
 	return nil
 }
 
-func (executor *Executor) resolveAbstractType(reqCtx *RequestContext, field *Field, abstractType ASTNode, value interface{}) (*ObjectTypeDefinition, error) {
+func (executor *Executor) resolveAbstractType(reqCtx *RequestContext, path *ResponsePath, field *Field, abstractType ASTNode, value interface{}) (*ObjectTypeDefinition, error) {
 	typeName := executor.ResolveType(value)
 	schema := executor.Schema.Document
 	if typeName == "" {
 		return nil, &GraphQLError{
 			Message: "The type of the value could not be determined",
 			Field:   field,
+			Path:    path.AsArray(),
 		}
 	}
 	switch typeValue := abstractType.(type) {
@@ -1571,6 +2047,7 @@ func (executor *Executor) resolveAbstractType(reqCtx *RequestContext, field *Fie
 				End:     field.Name.LOC.End,
 			},
 			Field: field,
+			Path:  path.AsArray(),
 		})
 		return nil, nil
 	case *UnionTypeDefinition:
@@ -1588,6 +2065,7 @@ func (executor *Executor) resolveAbstractType(reqCtx *RequestContext, field *Fie
 				End:     field.Name.LOC.End,
 			},
 			Field: field,
+			Path:  path.AsArray(),
 		})
 		return nil, nil
 	default:
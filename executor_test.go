@@ -1,12 +1,15 @@
 package graphql
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
-	. "github.com/playlyfe/go-graphql/language"
+	"github.com/playlyfe/go-graphql/language"
 	. "github.com/smartystreets/goconvey/convey"
 )
 
@@ -325,6 +328,7 @@ func TestExecutor(t *testing.T) {
 				"errors": []map[string]interface{}{
 					{
 						"message": "GraphQL Runtime Error (2:17) Runtime object type \"Human\" is not a possible type for interface type \"Pet\"\n\n1|{\n2|                pets {\n                  ^^^^\n3|                    ... on Dog {\n4|                        name",
+						"path":    []interface{}{"pets", 2},
 						"locations": []map[string]interface{}{
 							{
 								"column": 17,
@@ -355,6 +359,8 @@ func TestExecutor(t *testing.T) {
 		variables := map[string]interface{}{}
 		executor, err := NewExecutor(schema, "TestType", "", resolvers)
 		So(err, ShouldEqual, nil)
+		// These queries exercise the executor directly, so validation is skipped.
+		executor.ValidationRules = nil
 
 		Convey("works without directives", func() {
 
@@ -929,11 +935,99 @@ func TestExecutor(t *testing.T) {
 							"onFragment":  true,
 							"onOperation": false,
 						},
+						map[string]interface{}{
+							"args": []interface{}{
+								map[string]interface{}{
+									"defaultValue": "No longer supported",
+									"description":  "Explains why the element was deprecated",
+									"name":         "reason",
+									"type": map[string]interface{}{
+										"kind":   "SCALAR",
+										"name":   "String",
+										"ofType": interface{}(nil),
+									},
+								},
+							},
+							"description": "Marks an element of the schema as no longer supported",
+							"name":        "deprecated",
+							"onField":     false,
+							"onFragment":  false,
+							"onOperation": false,
+						},
+						map[string]interface{}{
+							"args": []interface{}{
+								map[string]interface{}{
+									"defaultValue": "true",
+									"description":  "Delivers the element incrementally when true, which is the default",
+									"name":         "if",
+									"type": map[string]interface{}{
+										"kind":   "SCALAR",
+										"name":   "Boolean",
+										"ofType": interface{}(nil),
+									},
+								},
+								map[string]interface{}{
+									"defaultValue": interface{}(nil),
+									"description":  "Identifies the payloads delivering the element",
+									"name":         "label",
+									"type": map[string]interface{}{
+										"kind":   "SCALAR",
+										"name":   "String",
+										"ofType": interface{}(nil),
+									},
+								},
+							},
+							"description": "Delivers a fragment after the rest of the response",
+							"name":        "defer",
+							"onField":     false,
+							"onFragment":  true,
+							"onOperation": false,
+						},
+						map[string]interface{}{
+							"args": []interface{}{
+								map[string]interface{}{
+									"defaultValue": "true",
+									"description":  "Delivers the element incrementally when true, which is the default",
+									"name":         "if",
+									"type": map[string]interface{}{
+										"kind":   "SCALAR",
+										"name":   "Boolean",
+										"ofType": interface{}(nil),
+									},
+								},
+								map[string]interface{}{
+									"defaultValue": interface{}(nil),
+									"description":  "Identifies the payloads delivering the element",
+									"name":         "label",
+									"type": map[string]interface{}{
+										"kind":   "SCALAR",
+										"name":   "String",
+										"ofType": interface{}(nil),
+									},
+								},
+								map[string]interface{}{
+									"defaultValue": "0",
+									"description":  "The number of items sent in the initial payload",
+									"name":         "initialCount",
+									"type": map[string]interface{}{
+										"kind":   "SCALAR",
+										"name":   "Int",
+										"ofType": interface{}(nil),
+									},
+								},
+							},
+							"description": "Delivers the items of a list field after the rest of the response",
+							"name":        "stream",
+							"onField":     true,
+							"onFragment":  false,
+							"onOperation": false,
+						},
 					},
 					"mutationType": interface{}(nil),
 					"queryType": map[string]interface{}{
 						"name": "QueryRoot",
 					},
+					"subscriptionType": interface{}(nil),
 					"types": []interface{}{
 						map[string]interface{}{
 							"description":   interface{}(nil),
@@ -1104,6 +1198,247 @@ func TestExecutor(t *testing.T) {
 		})
 	})
 
+	Convey("Execute: Handles deprecation introspection", t, func() {
+		schema := `
+		enum Color {
+			RED @deprecated(reason: "Too loud")
+			GREEN
+		}
+
+		input Filter {
+			name: String
+			legacyName: String @deprecated
+		}
+
+		type QueryRoot {
+			search(filter: Filter, query: String @deprecated(reason: "Use filter")): String
+			find(query: String): String @deprecated(reason: "Use search")
+		}
+		`
+		executor, err := NewExecutor(schema, "QueryRoot", "", map[string]interface{}{})
+		So(err, ShouldEqual, nil)
+
+		input := `
+		{
+			queryRoot: __type(name: "QueryRoot") {
+				fields {
+					name
+					args { name }
+				}
+				allFields: fields(includeDeprecated: true) {
+					name
+					isDeprecated
+					deprecationReason
+					allArgs: args(includeDeprecated: true) {
+						name
+						isDeprecated
+						deprecationReason
+					}
+				}
+			}
+			color: __type(name: "Color") {
+				enumValues { name }
+				allEnumValues: enumValues(includeDeprecated: true) {
+					name
+					isDeprecated
+					deprecationReason
+				}
+			}
+			filter: __type(name: "Filter") {
+				inputFields { name }
+				allInputFields: inputFields(includeDeprecated: true) {
+					name
+					isDeprecated
+				}
+			}
+		}
+		`
+		result, err := executor.Execute(nil, input, map[string]interface{}{}, "")
+		So(err, ShouldEqual, nil)
+		So(result, ShouldResemble, map[string]interface{}{
+			"data": map[string]interface{}{
+				"queryRoot": map[string]interface{}{
+					"fields": []interface{}{
+						map[string]interface{}{
+							"name": "search",
+							"args": []interface{}{
+								map[string]interface{}{"name": "filter"},
+							},
+						},
+					},
+					"allFields": []interface{}{
+						map[string]interface{}{
+							"name":              "search",
+							"isDeprecated":      false,
+							"deprecationReason": interface{}(nil),
+							"allArgs": []interface{}{
+								map[string]interface{}{
+									"name":              "filter",
+									"isDeprecated":      false,
+									"deprecationReason": interface{}(nil),
+								},
+								map[string]interface{}{
+									"name":              "query",
+									"isDeprecated":      true,
+									"deprecationReason": "Use filter",
+								},
+							},
+						},
+						map[string]interface{}{
+							"name":              "find",
+							"isDeprecated":      true,
+							"deprecationReason": "Use search",
+							"allArgs": []interface{}{
+								map[string]interface{}{
+									"name":              "query",
+									"isDeprecated":      false,
+									"deprecationReason": interface{}(nil),
+								},
+							},
+						},
+					},
+				},
+				"color": map[string]interface{}{
+					"enumValues": []interface{}{
+						map[string]interface{}{"name": "GREEN"},
+					},
+					"allEnumValues": []interface{}{
+						map[string]interface{}{
+							"name":              "RED",
+							"isDeprecated":      true,
+							"deprecationReason": "Too loud",
+						},
+						map[string]interface{}{
+							"name":              "GREEN",
+							"isDeprecated":      false,
+							"deprecationReason": interface{}(nil),
+						},
+					},
+				},
+				"filter": map[string]interface{}{
+					"inputFields": []interface{}{
+						map[string]interface{}{"name": "name"},
+					},
+					"allInputFields": []interface{}{
+						map[string]interface{}{
+							"name":         "name",
+							"isDeprecated": false,
+						},
+						map[string]interface{}{
+							"name":         "legacyName",
+							"isDeprecated": true,
+						},
+					},
+				},
+			},
+		})
+	})
+
+	Convey("Execute: Handles schema directives", t, func() {
+		schema := `
+		## Requires the user to have a role
+		directive @auth(role: String = "admin") on FIELD_DEFINITION | OBJECT
+
+		directive @upper on FIELD_DEFINITION
+
+		type QueryRoot {
+			name: String @upper
+			secret: String @auth
+			greeting: String @upper @auth(role: "user")
+		}
+		`
+		resolvers := map[string]interface{}{
+			"QueryRoot/name": func(params *ResolveParams) (interface{}, error) {
+				return "tom", nil
+			},
+			"QueryRoot/secret": func(params *ResolveParams) (interface{}, error) {
+				return "42", nil
+			},
+			"QueryRoot/greeting": func(params *ResolveParams) (interface{}, error) {
+				return "hello", nil
+			},
+		}
+		executor, err := NewExecutor(schema, "QueryRoot", "", resolvers)
+		So(err, ShouldEqual, nil)
+		roles := []string{}
+		executor.Directives["upper"] = func(resolveFn ResolveFn, params *ResolveParams, directiveArgs map[string]interface{}) (interface{}, error) {
+			result, err := resolveFn(params)
+			if value, ok := result.(string); ok {
+				return strings.ToUpper(value), err
+			}
+			return result, err
+		}
+		executor.Directives["auth"] = func(resolveFn ResolveFn, params *ResolveParams, directiveArgs map[string]interface{}) (interface{}, error) {
+			role := directiveArgs["role"].(string)
+			roles = append(roles, role)
+			if params.Context.(map[string]interface{})["role"] != role {
+				return nil, errors.New("Not authorized")
+			}
+			return resolveFn(params)
+		}
+		variables := map[string]interface{}{}
+
+		Convey("wraps the resolution of fields carrying the directive", func() {
+			result, err := executor.Execute(map[string]interface{}{"role": "user"}, `{ name, greeting }`, variables, "")
+			So(err, ShouldEqual, nil)
+			So(result, ShouldResemble, map[string]interface{}{
+				"data": map[string]interface{}{
+					"name":     "TOM",
+					"greeting": "HELLO",
+				},
+			})
+			So(roles, ShouldResemble, []string{"user"})
+		})
+
+		Convey("passes the default values of the directive arguments", func() {
+			result, err := executor.Execute(map[string]interface{}{"role": "admin"}, `{ secret }`, variables, "")
+			So(err, ShouldEqual, nil)
+			So(result, ShouldResemble, map[string]interface{}{
+				"data": map[string]interface{}{
+					"secret": "42",
+				},
+			})
+			So(roles, ShouldResemble, []string{"admin"})
+		})
+
+		Convey("reports errors returned by directive functions", func() {
+			result, err := executor.Execute(map[string]interface{}{"role": "user"}, `{ secret }`, variables, "")
+			So(err, ShouldEqual, nil)
+			So(result["data"], ShouldResemble, map[string]interface{}{
+				"secret": nil,
+			})
+			errors := result["errors"].([]map[string]interface{})
+			So(len(errors), ShouldEqual, 1)
+			So(errors[0]["message"], ShouldEqual, "Not authorized")
+		})
+
+		Convey("introspects the schema directives", func() {
+			result, err := executor.Execute(map[string]interface{}{}, `{ __schema { directives { name description locations args { name defaultValue } } } }`, variables, "")
+			So(err, ShouldEqual, nil)
+			directives := result["data"].(map[string]interface{})["__schema"].(map[string]interface{})["directives"].([]interface{})
+			So(len(directives), ShouldEqual, 7)
+			So(directives[0].(map[string]interface{})["locations"], ShouldResemble, []interface{}{"FIELD", "FRAGMENT_SPREAD", "INLINE_FRAGMENT"})
+			So(directives[5], ShouldResemble, map[string]interface{}{
+				"name":        "auth",
+				"description": "Requires the user to have a role\n",
+				"locations":   []interface{}{"FIELD_DEFINITION", "OBJECT"},
+				"args": []interface{}{
+					map[string]interface{}{
+						"name":         "role",
+						"defaultValue": "admin",
+					},
+				},
+			})
+			So(directives[6].(map[string]interface{})["name"], ShouldEqual, "upper")
+		})
+
+		Convey("prints the directive definitions", func() {
+			output := executor.PrintSchema()
+			So(output, ShouldStartWith, "schema {\n  query: QueryRoot\n}\n\n## Requires the user to have a role\ndirective @auth(role: String = \"admin\") on FIELD_DEFINITION | OBJECT\n\ndirective @upper on FIELD_DEFINITION\n\n")
+			So(output, ShouldContainSubstring, "  greeting: String @upper @auth(role: \"user\")\n")
+		})
+	})
+
 	Convey("Execute: Handles list nullability", t, func() {
 
 		check := func(testType string, testData interface{}, expected interface{}) {
@@ -1193,6 +1528,7 @@ func TestExecutor(t *testing.T) {
 						"errors": []map[string]interface{}{
 							{
 								"message": "Cannot return null for non-nullable field DataType.test",
+								"path":    []interface{}{"nest", "test"},
 								"locations": []map[string]interface{}{
 									{
 										"column": 10,
@@ -1228,6 +1564,7 @@ func TestExecutor(t *testing.T) {
 						"errors": []map[string]interface{}{
 							{
 								"message": "Cannot return null for non-nullable field DataType.test",
+								"path":    []interface{}{"nest", "test", 1},
 								"locations": []map[string]interface{}{
 									{
 										"column": 10,
@@ -1270,6 +1607,7 @@ func TestExecutor(t *testing.T) {
 						"errors": []map[string]interface{}{
 							{
 								"message": "Cannot return null for non-nullable field DataType.test",
+								"path":    []interface{}{"nest", "test", 1},
 								"locations": []map[string]interface{}{
 									{
 										"column": 10,
@@ -1288,6 +1626,7 @@ func TestExecutor(t *testing.T) {
 						"errors": []map[string]interface{}{
 							{
 								"message": "Cannot return null for non-nullable field DataType.test",
+								"path":    []interface{}{"nest", "test"},
 								"locations": []map[string]interface{}{
 									{
 										"column": 10,
@@ -1469,6 +1808,7 @@ func TestExecutor(t *testing.T) {
 				"errors": []map[string]interface{}{
 					{
 						"message": syncError.Error(),
+						"path":    []interface{}{"sync"},
 						"locations": []map[string]interface{}{
 							{
 								"line":   3,
@@ -1498,9 +1838,9 @@ func TestExecutor(t *testing.T) {
 				"nest": nil,
 			})
 			errors := result["errors"].([]map[string]interface{})
-			So(len(errors), ShouldEqual, 2)
+			So(len(errors), ShouldEqual, 1)
 			So(errors[0]["message"], ShouldEqual, nonNullSyncError.Error())
-			So(errors[1]["message"], ShouldEqual, "Cannot return null for non-nullable field DataType.nonNullSync")
+			So(errors[0]["path"], ShouldResemble, []interface{}{"nest", "nonNullSync"})
 		})
 
 		Convey("nulls a complex tree of nullable fields that throw", func() {
@@ -1560,9 +1900,9 @@ func TestExecutor(t *testing.T) {
 				"nest": nil,
 			})
 			errors := result["errors"].([]map[string]interface{})
-			So(len(errors), ShouldEqual, 2)
+			So(len(errors), ShouldEqual, 1)
 			So(errors[0]["message"], ShouldEqual, nonNullSyncError.Error())
-			So(errors[1]["message"], ShouldEqual, "Cannot return null for non-nullable field DataType.nonNullSync")
+			So(errors[0]["path"], ShouldResemble, []interface{}{"nest", "nonNullNest", "nonNullNest", "nonNullNest", "nonNullNest", "nonNullSync"})
 		})
 
 		Convey("nulls a nullable field that synchronously returns null", func() {
@@ -1605,6 +1945,7 @@ func TestExecutor(t *testing.T) {
 				"errors": []map[string]interface{}{
 					{
 						"message": "Cannot return null for non-nullable field DataType.nonNullSync",
+						"path":    []interface{}{"nest", "nonNullSync"},
 						"locations": []map[string]interface{}{
 							{
 								"column": 21,
@@ -1671,9 +2012,152 @@ func TestExecutor(t *testing.T) {
 				"nest": nil,
 			})
 			errors := result["errors"].([]map[string]interface{})
-			So(len(errors), ShouldEqual, 2)
+			So(len(errors), ShouldEqual, 1)
 			So(errors[0]["message"], ShouldEqual, nonNullSyncError.Error())
-			So(errors[1]["message"], ShouldEqual, "Cannot return null for non-nullable field DataType.nonNullSync")
+			So(errors[0]["path"], ShouldResemble, []interface{}{"nest", "nonNullNest", "nonNullNest", "nonNullNest", "nonNullNest", "nonNullSync"})
+		})
+
+		Convey("nulls only the nearest nullable list item or field holding a failed non-null field", func() {
+			schema := `
+            type Item {
+                id: Int!
+                name: String
+            }
+
+            type Pair {
+                first: Int!
+                second: Int!
+            }
+
+            type Query {
+                items: [Item]
+                strictItems: [Item!]
+                required: Item!
+                pair: Pair
+                sibling: String
+            }
+
+            type Mutation {
+                first: Int!
+                second: Int!
+            }
+            `
+			items := func(params *ResolveParams) (interface{}, error) {
+				return []interface{}{
+					map[string]interface{}{"id": 1, "name": "one"},
+					map[string]interface{}{"name": "two"},
+					map[string]interface{}{"id": 3},
+				}, nil
+			}
+			resolvers := map[string]interface{}{}
+			resolvers["Query/items"] = items
+			resolvers["Query/strictItems"] = items
+			resolvers["Query/required"] = func(params *ResolveParams) (interface{}, error) {
+				return map[string]interface{}{}, nil
+			}
+			resolvers["Query/pair"] = func(params *ResolveParams) (interface{}, error) {
+				return map[string]interface{}{}, nil
+			}
+			resolvers["Query/sibling"] = func(params *ResolveParams) (interface{}, error) {
+				return "sibling", nil
+			}
+			resolvers["Mutation/first"] = func(params *ResolveParams) (interface{}, error) {
+				return nil, errors.New("first failed")
+			}
+			resolvers["Mutation/second"] = func(params *ResolveParams) (interface{}, error) {
+				return nil, errors.New("second failed")
+			}
+			executor, err := NewExecutor(schema, "Query", "Mutation", resolvers)
+			So(err, ShouldEqual, nil)
+			nullError := func(path []interface{}, column int) []map[string]interface{} {
+				return []map[string]interface{}{
+					{
+						"message": "Cannot return null for non-nullable field Item.id",
+						"path":    path,
+						"locations": []map[string]interface{}{
+							{
+								"line":   1,
+								"column": column,
+							},
+						},
+					},
+				}
+			}
+
+			result, err := executor.Execute(nil, `{ items { id name } sibling }`, map[string]interface{}{}, "")
+			So(err, ShouldEqual, nil)
+			So(result, ShouldResemble, map[string]interface{}{
+				"data": map[string]interface{}{
+					"items": []interface{}{
+						map[string]interface{}{"id": int32(1), "name": "one"},
+						nil,
+						map[string]interface{}{"id": int32(3), "name": nil},
+					},
+					"sibling": "sibling",
+				},
+				"errors": nullError([]interface{}{"items", 1, "id"}, 11),
+			})
+
+			result, err = executor.Execute(nil, `{ strictItems { id } sibling }`, map[string]interface{}{}, "")
+			So(err, ShouldEqual, nil)
+			So(result, ShouldResemble, map[string]interface{}{
+				"data": map[string]interface{}{
+					"strictItems": nil,
+					"sibling":     "sibling",
+				},
+				"errors": nullError([]interface{}{"strictItems", 1, "id"}, 17),
+			})
+
+			result, err = executor.Execute(nil, `{ required { id } sibling }`, map[string]interface{}{}, "")
+			So(err, ShouldEqual, nil)
+			So(result, ShouldResemble, map[string]interface{}{
+				"data":   map[string]interface{}(nil),
+				"errors": nullError([]interface{}{"required", "id"}, 14),
+			})
+
+			result, err = executor.Execute(nil, `{ pair { first second } sibling }`, map[string]interface{}{}, "")
+			So(err, ShouldEqual, nil)
+			So(result["data"], ShouldResemble, map[string]interface{}{
+				"pair":    nil,
+				"sibling": "sibling",
+			})
+			errors := result["errors"].([]map[string]interface{})
+			So(len(errors), ShouldEqual, 2)
+			So(errors, ShouldContain, map[string]interface{}{
+				"message":   "Cannot return null for non-nullable field Pair.first",
+				"path":      []interface{}{"pair", "first"},
+				"locations": []map[string]interface{}{{"line": 1, "column": 10}},
+			})
+			So(errors, ShouldContain, map[string]interface{}{
+				"message":   "Cannot return null for non-nullable field Pair.second",
+				"path":      []interface{}{"pair", "second"},
+				"locations": []map[string]interface{}{{"line": 1, "column": 16}},
+			})
+
+			// Fields executed one after the other report every failure too
+			executor.Debug = true
+			debugResult, err := executor.Execute(nil, `{ pair { first second } sibling }`, map[string]interface{}{}, "")
+			So(err, ShouldEqual, nil)
+			So(debugResult, ShouldResemble, result)
+			executor.Debug = false
+
+			result, err = executor.Execute(nil, `mutation { first second }`, map[string]interface{}{}, "")
+			So(err, ShouldEqual, nil)
+			So(result, ShouldResemble, map[string]interface{}{
+				"data": map[string]interface{}(nil),
+				"errors": []map[string]interface{}{
+					{
+						"message":   "first failed",
+						"path":      []interface{}{"first"},
+						"locations": []map[string]interface{}{{"line": 1, "column": 12}},
+					},
+					{
+						"message":   "second failed",
+						"path":      []interface{}{"second"},
+						"locations": []map[string]interface{}{{"line": 1, "column": 18}},
+					},
+				},
+			})
 		})
 
 	})
@@ -1745,6 +2229,8 @@ func TestExecutor(t *testing.T) {
 		variables := map[string]interface{}{}
 		executor, err := NewExecutor(schema, "Person", "", resolvers)
 		So(err, ShouldEqual, nil)
+		// The queries below are invalid but executable, so validation is skipped.
+		executor.ValidationRules = nil
 		executor.ResolveType = func(value interface{}) string {
 			switch value.(type) {
 			case *Cat:
@@ -2094,6 +2580,8 @@ func TestExecutor(t *testing.T) {
 		variables := map[string]interface{}{}
 		executor, err := NewExecutor(schema, "TestType", "", resolvers)
 		So(err, ShouldEqual, nil)
+		// Input coercion is exercised in the executor directly, so validation is skipped.
+		executor.ValidationRules = nil
 		executor.Scalars["ComplexScalar"] = &Scalar{
 			ParseValue: func(context interface{}, value interface{}) (interface{}, error) {
 				if val, ok := value.(string); ok {
@@ -2101,17 +2589,17 @@ func TestExecutor(t *testing.T) {
 						return "DeserializedValue", nil
 					}
 				}
-				return nil, &GraphQLError{
+				return nil, &language.GraphQLError{
 					Message: "Failed to parse ComplexScalar value",
 				}
 			},
 			ParseLiteral: func(context interface{}, value interface{}) (interface{}, error) {
-				if ast, ok := value.(*String); ok {
+				if ast, ok := value.(*language.String); ok {
 					if ast.Value == "SerializedValue" {
 						return "DeserializedValue", nil
 					}
 				}
-				return nil, &GraphQLError{
+				return nil, &language.GraphQLError{
 					Message: "Failed to parse ComplexScalar value",
 				}
 			},
@@ -2121,7 +2609,7 @@ func TestExecutor(t *testing.T) {
 						return "SerializedValue", nil
 					}
 				}
-				return nil, &GraphQLError{
+				return nil, &language.GraphQLError{
 					Message: "Failed to serialize ComplexScalar value",
 				}
 			},
@@ -2221,6 +2709,7 @@ func TestExecutor(t *testing.T) {
 						"errors": []map[string]interface{}{
 							{
 								"message": "Failed to parse ComplexScalar value\n\n1|\n2|                    {\n3|                        deserializedValue(input: \"BAD\")\n                          ^^^^^^^^^^^^^^^^^\n4|                    }\n5|                    ",
+								"path":    []interface{}{"deserializedValue"},
 								"locations": []map[string]interface{}{
 									{
 										"column": 25,
@@ -2330,6 +2819,7 @@ func TestExecutor(t *testing.T) {
 									},
 								},
 								"message": "Variable \"$input\" got invalid value \nIn field \"c\": Expected \"String!\", found null\n\n1|\n2|                query q($input: TestInputObject) {\n3|                    fieldWithObjectInput(input: $input)\n                      ^^^^^^^^^^^^^^^^^^^^\n4|                }\n5|                ",
+								"path":    []interface{}{"fieldWithObjectInput"},
 							},
 						},
 					})
@@ -2354,6 +2844,7 @@ func TestExecutor(t *testing.T) {
 									},
 								},
 								"message": "Variable \"$input\" got invalid value \nExpected \"TestInputObject\", found not an object\n\n1|\n2|                query q($input: TestInputObject) {\n3|                    fieldWithObjectInput(input: $input)\n                      ^^^^^^^^^^^^^^^^^^^^\n4|                }\n5|                ",
+								"path":    []interface{}{"fieldWithObjectInput"},
 							},
 						},
 					})
@@ -2381,6 +2872,7 @@ func TestExecutor(t *testing.T) {
 									},
 								},
 								"message": "Variable \"$input\" got invalid value \nIn field \"c\": Expected \"String!\", found null\n\n1|\n2|                query q($input: TestInputObject) {\n3|                    fieldWithObjectInput(input: $input)\n                      ^^^^^^^^^^^^^^^^^^^^\n4|                }\n5|                ",
+								"path":    []interface{}{"fieldWithObjectInput"},
 							},
 						},
 					})
@@ -2414,6 +2906,7 @@ func TestExecutor(t *testing.T) {
 									},
 								},
 								"message": "Variable \"$input\" got invalid value \nIn field \"na\": In field \"c\": Expected \"String!\", found null\n\n1|\n2|                    query q($input: TestNestedInputObject) {\n3|                        fieldWithNestedObjectInput(input: $input)\n                          ^^^^^^^^^^^^^^^^^^^^^^^^^^\n4|                    }\n5|                    ",
+								"path":    []interface{}{"fieldWithNestedObjectInput"},
 							},
 						},
 					})
@@ -2443,6 +2936,7 @@ func TestExecutor(t *testing.T) {
 									},
 								},
 								"message": "Variable \"$input\" got invalid value \nIn field \"extra\": Unknown field\n\n1|\n2|                query q($input: TestInputObject) {\n3|                    fieldWithObjectInput(input: $input)\n                      ^^^^^^^^^^^^^^^^^^^^\n4|                }\n5|                ",
+								"path":    []interface{}{"fieldWithObjectInput"},
 							},
 						},
 					})
@@ -2511,7 +3005,7 @@ func TestExecutor(t *testing.T) {
 				So(err, ShouldEqual, nil)
 				So(result, ShouldResemble, map[string]interface{}{
 					"data": map[string]interface{}{
-						"fieldWithNullableStringInput": nil,
+						"fieldWithNullableStringInput": `null`,
 					},
 				})
 			})
@@ -2567,6 +3061,7 @@ func TestExecutor(t *testing.T) {
 					"errors": []map[string]interface{}{
 						{
 							"message": "Variable \"$value\" of required type \"String!\" was not provided\n\n1|\n2|                query SetsNonNullable($value: String!) {\n3|                    fieldWithNonNullableStringInput(input: $value)\n                      ^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^\n4|                }\n5|                ",
+							"path":    []interface{}{"fieldWithNonNullableStringInput"},
 							"locations": []map[string]interface{}{
 								{
 									"column": 21,
@@ -2596,6 +3091,7 @@ func TestExecutor(t *testing.T) {
 					"errors": []map[string]interface{}{
 						{
 							"message": "Variable \"$value\" of required type \"String!\" was not provided\n\n1|\n2|                query SetsNonNullable($value: String!) {\n3|                    fieldWithNonNullableStringInput(input: $value)\n                      ^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^\n4|                }\n5|                ",
+							"path":    []interface{}{"fieldWithNonNullableStringInput"},
 							"locations": []map[string]interface{}{
 								{
 									"column": 21,
@@ -2658,6 +3154,7 @@ func TestExecutor(t *testing.T) {
 					"errors": []map[string]interface{}{
 						{
 							"message": "Value required of type \"String!\" was not provided\n\n1|\n2|                {\n3|                    fieldWithNonNullableStringInput\n                      ^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^\n4|                }\n5|                ",
+							"path":    []interface{}{"fieldWithNonNullableStringInput"},
 							"locations": []map[string]interface{}{
 								{
 									"line":   3,
@@ -2685,7 +3182,7 @@ func TestExecutor(t *testing.T) {
 				So(err, ShouldEqual, nil)
 				So(result, ShouldResemble, map[string]interface{}{
 					"data": map[string]interface{}{
-						"list": nil,
+						"list": `null`,
 					},
 				})
 			})
@@ -2744,6 +3241,7 @@ func TestExecutor(t *testing.T) {
 					"errors": []map[string]interface{}{
 						{
 							"message": "Variable \"$input\" of required type \"String!\" was not provided\n\n1|\n2|                query q($input: [String]!) {\n3|                    nnList(input: $input)\n                      ^^^^^^\n4|                }\n5|                ",
+							"path":    []interface{}{"nnList"},
 							"locations": []map[string]interface{}{
 								{
 									"column": 21,
@@ -2786,7 +3284,7 @@ func TestExecutor(t *testing.T) {
 				So(err, ShouldEqual, nil)
 				So(result, ShouldResemble, map[string]interface{}{
 					"data": map[string]interface{}{
-						"listNN": nil,
+						"listNN": `null`,
 					},
 				})
 			})
@@ -2827,6 +3325,7 @@ func TestExecutor(t *testing.T) {
 					"errors": []map[string]interface{}{
 						{
 							"message": "Variable \"$input\" got invalid value \nIn element #1: Expected \"String!\", found null\n\n1|\n2|                query q($input: [String!]) {\n3|                    listNN(input: $input)\n                      ^^^^^^\n4|                }\n5|                ",
+							"path":    []interface{}{"listNN"},
 							"locations": []map[string]interface{}{
 								{
 									"line":   3,
@@ -2858,6 +3357,7 @@ func TestExecutor(t *testing.T) {
 					"errors": []map[string]interface{}{
 						{
 							"message": "Variable \"$input\" expected value of type \"TestType\" which cannot be used as an input type\n\n1|\n2|                query q($input: TestType!) {\n3|                    fieldWithObjectInput(input: $input)\n                      ^^^^^^^^^^^^^^^^^^^^\n4|                }\n5|                ",
+							"path":    []interface{}{"fieldWithObjectInput"},
 							"locations": []map[string]interface{}{
 								{
 									"line":   3,
@@ -2889,6 +3389,7 @@ func TestExecutor(t *testing.T) {
 					"errors": []map[string]interface{}{
 						{
 							"message": "Variable \"$input\" expected value of type \"UnknownType\" which cannot be used as an input type\n\n1|\n2|                query q($input: UnknownType!) {\n3|                    fieldWithObjectInput(input: $input)\n                      ^^^^^^^^^^^^^^^^^^^^\n4|                }\n5|                ",
+							"path":    []interface{}{"fieldWithObjectInput"},
 							"locations": []map[string]interface{}{
 								{
 									"line":   3,
@@ -2953,6 +3454,81 @@ func TestExecutor(t *testing.T) {
 
 	})
 
+	Convey("Execute: Handles explicit null values", t, func() {
+		schema := `
+        input Profile {
+            name: String
+            nick: String = "none"
+        }
+
+        type Query {
+            update(name: String, age: Int = 3, profile: Profile): String
+        }
+        `
+		resolvers := map[string]interface{}{}
+		resolvers["Query/update"] = func(params *ResolveParams) (interface{}, error) {
+			result, err := json.Marshal(params.Args)
+			if err != nil {
+				return nil, err
+			}
+			return fmt.Sprintf("%s %v", result, params.HasArg("name")), nil
+		}
+		executor, err := NewExecutor(schema, "", "", resolvers)
+		So(err, ShouldEqual, nil)
+
+		Convey("leaves out omitted arguments", func() {
+			result, err := executor.Execute(nil, `{ update }`, map[string]interface{}{}, "")
+			So(err, ShouldEqual, nil)
+			So(result, ShouldResemble, map[string]interface{}{
+				"data": map[string]interface{}{
+					"update": `{"age":3} false`,
+				},
+			})
+		})
+
+		Convey("passes arguments set to null", func() {
+			result, err := executor.Execute(nil, `{ update(name: null, age: null, profile: { name: null }) }`, map[string]interface{}{}, "")
+			So(err, ShouldEqual, nil)
+			So(result, ShouldResemble, map[string]interface{}{
+				"data": map[string]interface{}{
+					"update": `{"age":null,"name":null,"profile":{"name":null,"nick":"none"}} true`,
+				},
+			})
+		})
+
+		Convey("does not treat empty strings as null", func() {
+			result, err := executor.Execute(nil, `{ update(name: "", profile: { nick: "" }) }`, map[string]interface{}{}, "")
+			So(err, ShouldEqual, nil)
+			So(result, ShouldResemble, map[string]interface{}{
+				"data": map[string]interface{}{
+					"update": `{"age":3,"name":"","profile":{"nick":""}} true`,
+				},
+			})
+		})
+
+		Convey("passes variables set to null", func() {
+			query := `query Q($name: String, $age: Int, $profile: Profile) { update(name: $name, age: $age, profile: $profile) }`
+			result, err := executor.Execute(nil, query, map[string]interface{}{
+				"name":    nil,
+				"profile": map[string]interface{}{"name": nil},
+			}, "")
+			So(err, ShouldEqual, nil)
+			So(result, ShouldResemble, map[string]interface{}{
+				"data": map[string]interface{}{
+					"update": `{"age":3,"name":null,"profile":{"name":null,"nick":"none"}} true`,
+				},
+			})
+
+			result, err = executor.Execute(nil, query, map[string]interface{}{}, "")
+			So(err, ShouldEqual, nil)
+			So(result, ShouldResemble, map[string]interface{}{
+				"data": map[string]interface{}{
+					"update": `{"age":3} false`,
+				},
+			})
+		})
+	})
+
 	Convey("Execute: Handles basic execution tasks", t, func() {
 
 		Convey("executes arbitary code", func() {
@@ -3243,6 +3819,7 @@ func TestExecutor(t *testing.T) {
 				"errors": []map[string]interface{}{
 					{
 						"message": "Error getting syncError",
+						"path":    []interface{}{"syncError"},
 						"locations": []map[string]interface{}{
 							{
 								"column": 17,
@@ -3409,6 +3986,8 @@ func TestExecutor(t *testing.T) {
 			variables := map[string]interface{}{}
 			executor, err := NewExecutor(schema, "Type", "", resolvers)
 			So(err, ShouldEqual, nil)
+			// The executor guards against fragment cycles on its own.
+			executor.ValidationRules = nil
 			input := `
             query Q {
                 a
@@ -3430,7 +4009,7 @@ func TestExecutor(t *testing.T) {
 			})
 		})
 
-		Convey("does not include illegal fields in output", func() {
+		Convey("reports illegal fields instead of executing them", func() {
 			schema := `
             type Q {
                 a: String
@@ -3452,7 +4031,17 @@ func TestExecutor(t *testing.T) {
 			result, err := executor.Execute(context, input, variables, "M")
 			So(err, ShouldEqual, nil)
 			So(result, ShouldResemble, map[string]interface{}{
-				"data": map[string]interface{}{},
+				"errors": []map[string]interface{}{
+					map[string]interface{}{
+						"locations": []map[string]interface{}{
+							map[string]interface{}{
+								"line":   3,
+								"column": 17,
+							},
+						},
+						"message": "GraphQL Validation Error (3:17) Cannot query field \"thisIsIllegalDontIncludeMe\" on type \"M\"",
+					},
+				},
 			})
 		})
 
@@ -3563,6 +4152,8 @@ func TestExecutor(t *testing.T) {
 			variables := map[string]interface{}{}
 			executor, err := NewExecutor(schema, "Query", "", resolvers)
 			So(err, ShouldEqual, nil)
+			// The fragment deliberately selects unknown fields, so validation is skipped.
+			executor.ValidationRules = nil
 			input := `
             {
                 feed {
@@ -3699,12 +4290,12 @@ func TestExecutor(t *testing.T) {
 							}
 						}
 					}
-					return nil, &GraphQLError{
+					return nil, &language.GraphQLError{
 						Message: "Failed to parse value FileScalar value",
 					}
 				},
 				ParseLiteral: func(context interface{}, value interface{}) (interface{}, error) {
-					if _, ok := value.(*String); ok {
+					if _, ok := value.(*language.String); ok {
 						if val, ok := context.(map[string]interface{}); ok {
 							if id, ok := val["id"].(string); ok {
 								if id == "admin" {
@@ -3713,7 +4304,7 @@ func TestExecutor(t *testing.T) {
 							}
 						}
 					}
-					return nil, &GraphQLError{
+					return nil, &language.GraphQLError{
 						Message: "Failed to parse literal FileScalar value",
 					}
 				},
@@ -3751,6 +4342,7 @@ func TestExecutor(t *testing.T) {
 								},
 							},
 							"message": "Failed to parse literal FileScalar value\n\n1|\n2|          {\n3|            deserializedValue(input: \"hello\")\n              ^^^^^^^^^^^^^^^^^\n4|          }\n5|        ",
+							"path":    []interface{}{"deserializedValue"},
 						},
 					},
 				})
@@ -3793,6 +4385,7 @@ func TestExecutor(t *testing.T) {
 			executor, err := NewExecutor(schema, "TestType", "", resolvers)
 			executor.Debug = true
 			So(err, ShouldEqual, nil)
+			executor.ValidationRules = nil
 			executor.Scalars["FileScalar"] = &Scalar{
 				ParseValue: func(context interface{}, value interface{}) (interface{}, error) {
 					if _, ok := value.(string); ok {
@@ -3804,12 +4397,12 @@ func TestExecutor(t *testing.T) {
 							}
 						}
 					}
-					return nil, &GraphQLError{
+					return nil, &language.GraphQLError{
 						Message: "Failed to parse value FileScalar value",
 					}
 				},
 				ParseLiteral: func(context interface{}, value interface{}) (interface{}, error) {
-					if _, ok := value.(*String); ok {
+					if _, ok := value.(*language.String); ok {
 						if val, ok := context.(map[string]interface{}); ok {
 							if id, ok := val["id"].(string); ok {
 								if id == "admin" {
@@ -3818,7 +4411,7 @@ func TestExecutor(t *testing.T) {
 							}
 						}
 					}
-					return nil, &GraphQLError{
+					return nil, &language.GraphQLError{
 						Message: "Failed to parse literal FileScalar value",
 					}
 				},
@@ -3860,6 +4453,7 @@ func TestExecutor(t *testing.T) {
 								},
 							},
 							"message": "Variable \"$input\" got invalid value \nIn field \"file\": Failed to parse value FileScalar value\n\n1|\n2|        query q($input: TestInputObject) {\n3|          deserializedValue(input: $input)\n            ^^^^^^^^^^^^^^^^^\n4|        }\n5|      ",
+							"path":    []interface{}{"deserializedValue"},
 						},
 					},
 				})
@@ -3947,6 +4541,7 @@ func TestExecutor(t *testing.T) {
 							},
 						},
 						"message": "Test failed",
+						"path":    []interface{}{"a"},
 					},
 				},
 			})
@@ -4007,12 +4602,184 @@ func TestExecutor(t *testing.T) {
 							},
 						},
 						"message": "Test failed",
+						"path":    []interface{}{"b"},
+					},
+				},
+			})
+		})
+	})
+
+	Convey("Execute: Handles context cancellation", t, func() {
+		schema := `
+        type Item {
+            name: String
+        }
+        type Query {
+            user: String
+            slow: Item
+            items: [Item]
+        }
+        `
+		type userKey struct{}
+		resolvers := map[string]interface{}{}
+		resolvers["Query/user"] = func(params *ResolveParams) (interface{}, error) {
+			return params.Ctx.Value(userKey{}), nil
+		}
+		resolvers["Query/slow"] = func(params *ResolveParams) (interface{}, error) {
+			<-params.Ctx.Done()
+			return map[string]interface{}{"name": "late"}, nil
+		}
+		resolvers["Query/items"] = func(params *ResolveParams) (interface{}, error) {
+			return []map[string]interface{}{{"name": "a"}, {"name": "b"}}, nil
+		}
+		resolvers["Item/name"] = func(params *ResolveParams) (interface{}, error) {
+			return params.Source.(map[string]interface{})["name"], nil
+		}
+		executor, err := NewExecutor(schema, "Query", "", resolvers)
+		So(err, ShouldEqual, nil)
+		variables := map[string]interface{}{}
+
+		Convey("exposes the context to resolvers", func() {
+			ctx := context.WithValue(context.Background(), userKey{}, "john")
+			result, err := executor.ExecuteContext(ctx, nil, `{ user }`, variables, "")
+			So(err, ShouldEqual, nil)
+			So(result, ShouldResemble, map[string]interface{}{
+				"data": map[string]interface{}{
+					"user": "john",
+				},
+			})
+		})
+
+		Convey("reports fields that were not resolved before the deadline", func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+			result, err := executor.ExecuteContext(ctx, nil, `{ slow { name } }`, variables, "")
+			So(err, ShouldEqual, nil)
+			So(result, ShouldResemble, map[string]interface{}{
+				"data": map[string]interface{}{
+					"slow": map[string]interface{}{
+						"name": nil,
+					},
+				},
+				"errors": []map[string]interface{}{
+					{
+						"locations": []map[string]interface{}{
+							{
+								"column": 10,
+								"line":   1,
+							},
+						},
+						"message": "GraphQL Runtime Error (1:10) context deadline exceeded\n\n1|{ slow { name } }\n           ^^^^",
+						"path":    []interface{}{"slow", "name"},
+					},
+				},
+			})
+		})
+
+		Convey("does not resolve fields once cancelled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			result, err := executor.ExecuteContext(ctx, nil, `{ items { name } }`, variables, "")
+			So(err, ShouldEqual, nil)
+			So(result, ShouldResemble, map[string]interface{}{
+				"data": map[string]interface{}{
+					"items": nil,
+				},
+				"errors": []map[string]interface{}{
+					{
+						"locations": []map[string]interface{}{
+							{
+								"column": 3,
+								"line":   1,
+							},
+						},
+						"message": "GraphQL Runtime Error (1:3) context canceled\n\n1|{ items { name } }\n    ^^^^^",
+						"path":    []interface{}{"items"},
 					},
 				},
 			})
 		})
 	})
 
+	Convey("Execute: Reports the path and extensions of errors", t, func() {
+		schema := `
+        type Item {
+            name: String
+        }
+
+        type Query {
+            items: [Item]
+            item: Item
+        }
+        `
+		paths := []string{}
+		resolvers := map[string]interface{}{}
+		resolvers["Query/items"] = func(params *ResolveParams) (interface{}, error) {
+			return []interface{}{
+				map[string]interface{}{"name": "a"},
+				map[string]interface{}{},
+			}, nil
+		}
+		resolvers["Query/item"] = func(params *ResolveParams) (interface{}, error) {
+			return map[string]interface{}{}, nil
+		}
+		resolvers["Item/name"] = func(params *ResolveParams) (interface{}, error) {
+			paths = append(paths, fmt.Sprint(params.Path.AsArray()))
+			if params.Source.(map[string]interface{})["name"] == nil {
+				return nil, &ExtendedError{
+					Message:    "Item has no name",
+					Extensions: map[string]interface{}{"code": "NOT_FOUND"},
+				}
+			}
+			return params.Source.(map[string]interface{})["name"], nil
+		}
+		executor, err := NewExecutor(schema, "Query", "", resolvers)
+		So(err, ShouldEqual, nil)
+		executor.Scheduler = NewScheduler(0, 0)
+		executor.Scheduler.Serial = true
+
+		Convey("with the response keys and list indices of the field", func() {
+			result, err := executor.Execute(nil, `{ list: items { name }, other: item { title: name } }`, map[string]interface{}{}, "")
+			So(err, ShouldEqual, nil)
+			So(result, ShouldResemble, map[string]interface{}{
+				"data": map[string]interface{}{
+					"list": []interface{}{
+						map[string]interface{}{"name": "a"},
+						map[string]interface{}{"name": nil},
+					},
+					"other": map[string]interface{}{
+						"title": nil,
+					},
+				},
+				"errors": []map[string]interface{}{
+					{
+						"message":    "Item has no name",
+						"path":       []interface{}{"list", 1, "name"},
+						"extensions": map[string]interface{}{"code": "NOT_FOUND"},
+						"locations": []map[string]interface{}{
+							{"line": 1, "column": 17},
+						},
+					},
+					{
+						"message":    "Item has no name",
+						"path":       []interface{}{"other", "title"},
+						"extensions": map[string]interface{}{"code": "NOT_FOUND"},
+						"locations": []map[string]interface{}{
+							{"line": 1, "column": 46},
+						},
+					},
+				},
+			})
+			So(paths, ShouldResemble, []string{"[list 0 name]", "[list 1 name]", "[other title]"})
+		})
+
+		Convey("and gives the path of the field to its resolver", func() {
+			_, err := executor.Execute(nil, `{ items { name } }`, map[string]interface{}{}, "")
+			So(err, ShouldEqual, nil)
+			So(paths, ShouldResemble, []string{"[items 0 name]", "[items 1 name]"})
+		})
+	})
+
 }
 
 func SetupBenchmark(name string) (*Executor, interface{}, map[string]interface{}) {
@@ -4,8 +4,10 @@ type GraphQLParams struct {
 	SchemaDefinition string
 	QueryRoot        string
 	MutationRoot     string
+	SubscriptionRoot string
 	Resolvers        map[string]interface{}
 	Scalars          map[string]*Scalar
+	Directives       map[string]DirectiveFn
 	ResolveType      func(value interface{}) string
 }
 
@@ -14,11 +16,20 @@ func NewGraphQL(params *GraphQLParams) (*Executor, error) {
 	if err != nil {
 		return nil, err
 	}
+	if params.SubscriptionRoot != "" {
+		err = executor.Schema.SetSubscriptionRoot(params.SubscriptionRoot)
+		if err != nil {
+			return nil, err
+		}
+	}
 	if params.ResolveType != nil {
 		executor.ResolveType = params.ResolveType
 	}
 	if params.Scalars != nil {
 		executor.Scalars = params.Scalars
 	}
+	if params.Directives != nil {
+		executor.Directives = params.Directives
+	}
 	return executor, nil
 }
@@ -0,0 +1,257 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/playlyfe/go-graphql"
+	. "github.com/playlyfe/go-graphql/language"
+)
+
+const (
+	ContentTypeJSON            = "application/json"
+	ContentTypeGraphQL         = "application/graphql"
+	ContentTypeGraphQLResponse = "application/graphql-response+json"
+	ContentTypeMultipartMixed  = "multipart/mixed"
+)
+
+// RequestParams are the parameters of a GraphQL request as received over HTTP.
+type RequestParams struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+	Extensions    map[string]interface{} `json:"extensions"`
+}
+
+// persistedQueryHash returns the hash of the persisted query the request
+// refers to in its extensions, if any.
+func (params *RequestParams) persistedQueryHash() string {
+	persistedQuery, _ := params.Extensions["persistedQuery"].(map[string]interface{})
+	hash, _ := persistedQuery["sha256Hash"].(string)
+	return hash
+}
+
+// Handler serves GraphQL requests over HTTP as described by the
+// GraphQL-over-HTTP specification.
+type Handler struct {
+	Executor *graphql.Executor
+	// Context builds the app context passed to the executor for each request.
+	// When nil the *http.Request itself is used.
+	Context func(request *http.Request) interface{}
+	// MaxBodySize limits the size of POST bodies in bytes.
+	MaxBodySize int64
+}
+
+// New returns a Handler that executes requests with the given executor.
+func New(executor *graphql.Executor) *Handler {
+	return &Handler{
+		Executor:    executor,
+		MaxBodySize: 1 << 20,
+	}
+}
+
+func (handler *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	responseType := ContentTypeJSON
+	if accepts(r.Header.Get("Accept"), ContentTypeGraphQLResponse) {
+		responseType = ContentTypeGraphQLResponse
+	}
+
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		w.Header().Set("Allow", "GET, POST")
+		writeError(w, responseType, http.StatusMethodNotAllowed, "GraphQL only supports GET and POST requests")
+		return
+	}
+
+	params, status, message := handler.requestParams(w, r)
+	if status != http.StatusOK {
+		writeError(w, responseType, status, message)
+		return
+	}
+	if hash := params.persistedQueryHash(); hash != "" {
+		query, err := handler.Executor.ResolvePersistedQuery(params.Query, hash)
+		if err != nil {
+			handler.writeResult(w, responseType, map[string]interface{}{
+				"errors": []map[string]interface{}{
+					handler.Executor.ErrorHandler(&graphql.Error{Error: err}),
+				},
+			})
+			return
+		}
+		params.Query = query
+	}
+	if params.Query == "" {
+		writeError(w, responseType, http.StatusBadRequest, "Must provide query string")
+		return
+	}
+
+	// Mutations may only be executed over POST. Documents that do not parse
+	// are left to the executor to report.
+	if r.Method == http.MethodGet && operationType(params.Query, params.OperationName) == "mutation" {
+		w.Header().Set("Allow", "POST")
+		writeError(w, responseType, http.StatusMethodNotAllowed, "Can only perform a mutation operation from a POST request")
+		return
+	}
+
+	var appContext interface{} = r
+	if handler.Context != nil {
+		appContext = handler.Context(r)
+	}
+	if params.Variables == nil {
+		params.Variables = map[string]interface{}{}
+	}
+	if accepts(r.Header.Get("Accept"), ContentTypeMultipartMixed) {
+		handler.serveIncremental(w, r, responseType, appContext, params)
+		return
+	}
+	result, err := handler.Executor.ExecuteContext(r.Context(), appContext, params.Query, params.Variables, params.OperationName)
+	if err != nil {
+		writeError(w, responseType, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	handler.writeResult(w, responseType, result)
+}
+
+// serveIncremental executes the request incrementally, writing the initial
+// payload and the subsequent payloads as the parts of a multipart/mixed
+// response. Results without subsequent payloads are written as usual.
+func (handler *Handler) serveIncremental(w http.ResponseWriter, r *http.Request, responseType string, appContext interface{}, params *RequestParams) {
+	result, err := handler.Executor.ExecuteIncremental(r.Context(), appContext, params.Query, params.Variables, params.OperationName)
+	if err != nil {
+		writeError(w, responseType, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer result.Close()
+	if _, ok := result.Initial["hasNext"]; !ok {
+		handler.writeResult(w, responseType, result.Initial)
+		return
+	}
+	writer := NewMultipartWriter(w)
+	if err := writer.WritePart(result.Initial); err != nil {
+		return
+	}
+	for payload := range result.Subsequent {
+		if err := writer.WritePart(payload); err != nil {
+			return
+		}
+	}
+	writer.Close()
+}
+
+func (handler *Handler) writeResult(w http.ResponseWriter, responseType string, result map[string]interface{}) {
+	status := http.StatusOK
+	if _, ok := result["data"]; !ok && responseType == ContentTypeGraphQLResponse {
+		// The request failed before execution started
+		status = http.StatusBadRequest
+	}
+	writeJSON(w, responseType, status, result)
+}
+
+// requestParams extracts the GraphQL parameters from the request. A status
+// other than http.StatusOK is returned along with a message if the request is
+// malformed.
+func (handler *Handler) requestParams(w http.ResponseWriter, r *http.Request) (*RequestParams, int, string) {
+	query := r.URL.Query()
+	params := &RequestParams{
+		Query:         query.Get("query"),
+		OperationName: query.Get("operationName"),
+	}
+	if variables := query.Get("variables"); variables != "" {
+		if err := json.Unmarshal([]byte(variables), &params.Variables); err != nil {
+			return nil, http.StatusBadRequest, "Variables are invalid JSON"
+		}
+	}
+	if extensions := query.Get("extensions"); extensions != "" {
+		if err := json.Unmarshal([]byte(extensions), &params.Extensions); err != nil {
+			return nil, http.StatusBadRequest, "Extensions are invalid JSON"
+		}
+	}
+	if r.Method == http.MethodGet {
+		return params, http.StatusOK, ""
+	}
+
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, http.StatusUnsupportedMediaType, "Missing or invalid Content-Type header"
+	}
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, handler.MaxBodySize))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return nil, http.StatusRequestEntityTooLarge, "Request body is too large"
+		}
+		return nil, http.StatusBadRequest, "Request body could not be read"
+	}
+
+	switch mediaType {
+	case ContentTypeJSON:
+		bodyParams := &RequestParams{}
+		if err := json.Unmarshal(body, bodyParams); err != nil {
+			return nil, http.StatusBadRequest, "POST body sent invalid JSON"
+		}
+		return bodyParams, http.StatusOK, ""
+	case ContentTypeGraphQL:
+		params.Query = string(body)
+		return params, http.StatusOK, ""
+	}
+	return nil, http.StatusUnsupportedMediaType, "Unsupported Content-Type " + mediaType
+}
+
+// operationType returns the type of the operation that would be executed for
+// the given document and operation name, or an empty string if it can not be
+// determined.
+func operationType(query string, operationName string) string {
+	parser := &Parser{}
+	document, err := parser.Parse(&ParseParams{
+		Source:   query,
+		NoSource: true,
+	})
+	if err != nil {
+		return ""
+	}
+	for _, definition := range document.Definitions {
+		if operationDefinition, ok := definition.(*OperationDefinition); ok {
+			if operationName == "" || (operationDefinition.Name != nil && operationDefinition.Name.Value == operationName) {
+				return operationDefinition.Operation
+			}
+		}
+	}
+	return ""
+}
+
+// accepts reports whether the media type is one of the media ranges of the
+// Accept header.
+func accepts(accept string, mediaType string) bool {
+	for _, mediaRange := range strings.Split(accept, ",") {
+		acceptedType, _, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+		if err == nil && acceptedType == mediaType {
+			return true
+		}
+	}
+	return false
+}
+
+func writeError(w http.ResponseWriter, contentType string, status int, message string) {
+	writeJSON(w, contentType, status, map[string]interface{}{
+		"errors": []map[string]interface{}{
+			{
+				"message": message,
+			},
+		},
+	})
+}
+
+func writeJSON(w http.ResponseWriter, contentType string, status int, result map[string]interface{}) {
+	output, err := json.Marshal(result)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", contentType+"; charset=utf-8")
+	w.WriteHeader(status)
+	w.Write(output)
+}
@@ -0,0 +1,261 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/playlyfe/go-graphql"
+	"github.com/playlyfe/go-graphql/persisted"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type failingReader struct{}
+
+func (r failingReader) Read(data []byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+func serve(handler http.Handler, request *http.Request) (int, string, map[string]interface{}) {
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	result := map[string]interface{}{}
+	err := json.Unmarshal(recorder.Body.Bytes(), &result)
+	So(err, ShouldEqual, nil)
+	return recorder.Code, recorder.Header().Get("Content-Type"), result
+}
+
+func TestHandler(t *testing.T) {
+
+	Convey("Handler: Serves GraphQL over HTTP", t, func() {
+		schema := `
+        type Query {
+            hello(name: String): String
+            user: String
+        }
+        type Mutation {
+            setName(name: String): String
+        }
+        `
+		resolvers := map[string]interface{}{}
+		resolvers["Query/hello"] = func(params *graphql.ResolveParams) (interface{}, error) {
+			if name, ok := params.Args["name"].(string); ok {
+				return "Hello " + name, nil
+			}
+			return "Hello World", nil
+		}
+		resolvers["Query/user"] = func(params *graphql.ResolveParams) (interface{}, error) {
+			return params.Context.(map[string]interface{})["user"], nil
+		}
+		resolvers["Mutation/setName"] = func(params *graphql.ResolveParams) (interface{}, error) {
+			return params.Args["name"], nil
+		}
+		executor, err := graphql.NewExecutor(schema, "Query", "Mutation", resolvers)
+		So(err, ShouldEqual, nil)
+		handler := New(executor)
+
+		Convey("handles GET requests", func() {
+			query := url.Values{}
+			query.Set("query", "query Q($name: String) { hello(name: $name) }")
+			query.Set("variables", `{"name": "John"}`)
+			query.Set("operationName", "Q")
+			status, contentType, result := serve(handler, httptest.NewRequest("GET", "/graphql?"+query.Encode(), nil))
+			So(status, ShouldEqual, http.StatusOK)
+			So(contentType, ShouldEqual, "application/json; charset=utf-8")
+			So(result, ShouldResemble, map[string]interface{}{
+				"data": map[string]interface{}{
+					"hello": "Hello John",
+				},
+			})
+		})
+
+		Convey("handles POST requests with a JSON body", func() {
+			request := httptest.NewRequest("POST", "/graphql", strings.NewReader(`{
+                "query": "mutation M($name: String) { setName(name: $name) }",
+                "variables": {"name": "Jane"},
+                "operationName": "M"
+            }`))
+			request.Header.Set("Content-Type", "application/json; charset=utf-8")
+			status, _, result := serve(handler, request)
+			So(status, ShouldEqual, http.StatusOK)
+			So(result, ShouldResemble, map[string]interface{}{
+				"data": map[string]interface{}{
+					"setName": "Jane",
+				},
+			})
+		})
+
+		Convey("handles POST requests with a GraphQL body", func() {
+			request := httptest.NewRequest("POST", "/graphql?variables=%7B%22name%22%3A%22Bob%22%7D", strings.NewReader(`query ($name: String) { hello(name: $name) }`))
+			request.Header.Set("Content-Type", "application/graphql")
+			status, _, result := serve(handler, request)
+			So(status, ShouldEqual, http.StatusOK)
+			So(result, ShouldResemble, map[string]interface{}{
+				"data": map[string]interface{}{
+					"hello": "Hello Bob",
+				},
+			})
+		})
+
+		Convey("builds the app context from the request", func() {
+			handler.Context = func(request *http.Request) interface{} {
+				return map[string]interface{}{
+					"user": request.Header.Get("X-User"),
+				}
+			}
+			request := httptest.NewRequest("GET", "/graphql?query=%7Buser%7D", nil)
+			request.Header.Set("X-User", "admin")
+			_, _, result := serve(handler, request)
+			So(result, ShouldResemble, map[string]interface{}{
+				"data": map[string]interface{}{
+					"user": "admin",
+				},
+			})
+		})
+
+		Convey("rejects mutations over GET", func() {
+			status, _, result := serve(handler, httptest.NewRequest("GET", "/graphql?query=mutation%7BsetName%7D", nil))
+			So(status, ShouldEqual, http.StatusMethodNotAllowed)
+			So(result, ShouldResemble, map[string]interface{}{
+				"errors": []interface{}{
+					map[string]interface{}{"message": "Can only perform a mutation operation from a POST request"},
+				},
+			})
+		})
+
+		Convey("rejects unsupported methods", func() {
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest("PUT", "/graphql", nil))
+			So(recorder.Code, ShouldEqual, http.StatusMethodNotAllowed)
+			So(recorder.Header().Get("Allow"), ShouldEqual, "GET, POST")
+		})
+
+		Convey("rejects malformed requests", func() {
+			status, _, result := serve(handler, httptest.NewRequest("GET", "/graphql", nil))
+			So(status, ShouldEqual, http.StatusBadRequest)
+			So(result, ShouldResemble, map[string]interface{}{
+				"errors": []interface{}{
+					map[string]interface{}{"message": "Must provide query string"},
+				},
+			})
+
+			request := httptest.NewRequest("POST", "/graphql", strings.NewReader(`{"query":`))
+			request.Header.Set("Content-Type", "application/json")
+			status, _, _ = serve(handler, request)
+			So(status, ShouldEqual, http.StatusBadRequest)
+
+			request = httptest.NewRequest("POST", "/graphql", strings.NewReader(`query=%7Bhello%7D`))
+			request.Header.Set("Content-Type", "text/plain")
+			status, _, _ = serve(handler, request)
+			So(status, ShouldEqual, http.StatusUnsupportedMediaType)
+
+			status, _, _ = serve(handler, httptest.NewRequest("GET", "/graphql?query=%7Bhello%7D&variables=nope", nil))
+			So(status, ShouldEqual, http.StatusBadRequest)
+		})
+
+		Convey("rejects bodies that are too large or cannot be read", func() {
+			handler.MaxBodySize = 16
+			request := httptest.NewRequest("POST", "/graphql", strings.NewReader(`{"query": "{ hello }"}`))
+			request.Header.Set("Content-Type", "application/json")
+			status, _, result := serve(handler, request)
+			So(status, ShouldEqual, http.StatusRequestEntityTooLarge)
+			So(result, ShouldResemble, map[string]interface{}{
+				"errors": []interface{}{
+					map[string]interface{}{"message": "Request body is too large"},
+				},
+			})
+
+			request = httptest.NewRequest("POST", "/graphql", failingReader{})
+			request.Header.Set("Content-Type", "application/json")
+			status, _, result = serve(handler, request)
+			So(status, ShouldEqual, http.StatusBadRequest)
+			So(result, ShouldResemble, map[string]interface{}{
+				"errors": []interface{}{
+					map[string]interface{}{"message": "Request body could not be read"},
+				},
+			})
+		})
+
+		Convey("handles automatic persisted queries", func() {
+			executor.PersistedQueries = persisted.NewMemoryStore()
+			query := "{ hello }"
+			extensions := `{"persistedQuery": {"version": 1, "sha256Hash": "` + persisted.Hash(query) + `"}}`
+
+			status, _, result := serve(handler, httptest.NewRequest("GET", "/graphql?extensions="+url.QueryEscape(extensions), nil))
+			So(status, ShouldEqual, http.StatusOK)
+			So(result, ShouldResemble, map[string]interface{}{
+				"errors": []interface{}{
+					map[string]interface{}{
+						"message":    "PersistedQueryNotFound",
+						"extensions": map[string]interface{}{"code": "PERSISTED_QUERY_NOT_FOUND"},
+					},
+				},
+			})
+
+			request := httptest.NewRequest("POST", "/graphql", strings.NewReader(`{"query": "`+query+`", "extensions": `+extensions+`}`))
+			request.Header.Set("Content-Type", "application/json")
+			status, _, result = serve(handler, request)
+			So(status, ShouldEqual, http.StatusOK)
+			So(result, ShouldResemble, map[string]interface{}{
+				"data": map[string]interface{}{
+					"hello": "Hello World",
+				},
+			})
+
+			status, _, result = serve(handler, httptest.NewRequest("GET", "/graphql?extensions="+url.QueryEscape(extensions), nil))
+			So(status, ShouldEqual, http.StatusOK)
+			So(result, ShouldResemble, map[string]interface{}{
+				"data": map[string]interface{}{
+					"hello": "Hello World",
+				},
+			})
+
+			status, _, _ = serve(handler, httptest.NewRequest("GET", "/graphql?query=%7Bhello%7D&extensions=nope", nil))
+			So(status, ShouldEqual, http.StatusBadRequest)
+		})
+
+		Convey("writes incremental results as multipart responses when accepted", func() {
+			request := httptest.NewRequest("GET", "/graphql?query="+url.QueryEscape(`{ ... @defer { hello } }`), nil)
+			request.Header.Set("Accept", "multipart/mixed, application/json")
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			So(recorder.Code, ShouldEqual, http.StatusOK)
+			So(recorder.Header().Get("Content-Type"), ShouldEqual, `multipart/mixed; boundary="-"; deferSpec=20220824`)
+			So(recorder.Body.String(), ShouldEqual, "\r\n---\r\nContent-Type: application/json; charset=utf-8\r\n\r\n"+
+				`{"data":{},"hasNext":true}`+
+				"\r\n---\r\nContent-Type: application/json; charset=utf-8\r\n\r\n"+
+				`{"data":{"hello":"Hello World"},"hasNext":false,"path":[]}`+
+				"\r\n-----\r\n")
+
+			request = httptest.NewRequest("GET", "/graphql?query=%7Bhello%7D", nil)
+			request.Header.Set("Accept", "multipart/mixed, application/json")
+			status, contentType, result := serve(handler, request)
+			So(status, ShouldEqual, http.StatusOK)
+			So(contentType, ShouldEqual, "application/json; charset=utf-8")
+			So(result, ShouldResemble, map[string]interface{}{
+				"data": map[string]interface{}{
+					"hello": "Hello World",
+				},
+			})
+		})
+
+		Convey("uses the GraphQL response media type when accepted", func() {
+			request := httptest.NewRequest("GET", "/graphql?query=%7Bunknown%7D", nil)
+			request.Header.Set("Accept", "application/graphql-response+json, application/json;q=0.9")
+			status, contentType, result := serve(handler, request)
+			So(status, ShouldEqual, http.StatusBadRequest)
+			So(contentType, ShouldEqual, "application/graphql-response+json; charset=utf-8")
+			So(result["data"], ShouldEqual, nil)
+			So(len(result["errors"].([]interface{})), ShouldEqual, 1)
+
+			request = httptest.NewRequest("GET", "/graphql?query=%7Bunknown%7D", nil)
+			status, _, _ = serve(handler, request)
+			So(status, ShouldEqual, http.StatusOK)
+		})
+	})
+
+}
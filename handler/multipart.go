@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// multipartBoundary delimits the parts of incremental responses, as expected
+// by the clients implementing incremental delivery.
+const multipartBoundary = "-"
+
+// MultipartWriter writes payloads as the parts of a multipart/mixed response,
+// flushing every part so that clients receive it as soon as it is written.
+type MultipartWriter struct {
+	w       http.ResponseWriter
+	started bool
+}
+
+// NewMultipartWriter returns a writer of the multipart/mixed response w.
+func NewMultipartWriter(w http.ResponseWriter) *MultipartWriter {
+	return &MultipartWriter{w: w}
+}
+
+// WritePart writes the payload as a JSON part of the response, writing the
+// headers of the response along with the first part.
+func (writer *MultipartWriter) WritePart(payload map[string]interface{}) error {
+	output, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	if !writer.started {
+		writer.w.Header().Set("Content-Type", ContentTypeMultipartMixed+`; boundary="`+multipartBoundary+`"; deferSpec=20220824`)
+		writer.w.WriteHeader(http.StatusOK)
+		writer.started = true
+	}
+	part := "\r\n--" + multipartBoundary + "\r\nContent-Type: " + ContentTypeJSON + "; charset=utf-8\r\n\r\n"
+	if _, err := writer.w.Write(append([]byte(part), output...)); err != nil {
+		return err
+	}
+	if flusher, ok := writer.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return nil
+}
+
+// Close writes the closing delimiter of the response.
+func (writer *MultipartWriter) Close() error {
+	_, err := writer.w.Write([]byte("\r\n--" + multipartBoundary + "--\r\n"))
+	if flusher, ok := writer.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return err
+}
@@ -0,0 +1,329 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+
+	. "github.com/playlyfe/go-graphql/language"
+)
+
+// IncrementalResult is the result of an operation executed with
+// ExecuteIncremental. Initial holds the result of the operation without its
+// deferred fragments and streamed list items, which are then sent on
+// Subsequent as payloads holding the "path" they belong to, their "data" or
+// "items", the "label" given to their directive, their "errors" and whether
+// more payloads follow in "hasNext". Subsequent is closed after the last
+// payload, or right away when nothing was deferred.
+type IncrementalResult struct {
+	Initial    map[string]interface{}
+	Subsequent <-chan map[string]interface{}
+	done       chan struct{}
+	cancel     context.CancelFunc
+	once       sync.Once
+}
+
+// Close stops the execution of the remaining payloads. Callers must call Close
+// once they stop reading from Subsequent.
+func (result *IncrementalResult) Close() {
+	result.once.Do(func() {
+		close(result.done)
+		result.cancel()
+	})
+}
+
+// deferredFragment is a fragment carrying the @defer directive whose fields
+// are set aside when collecting the fields of a selection set.
+type deferredFragment struct {
+	label        string
+	selectionSet *SelectionSet
+}
+
+// incrementalRecord is the execution of a deferred fragment or of a streamed
+// list item, producing a subsequent payload.
+type incrementalRecord struct {
+	label string
+	path  *ResponsePath
+	// parent is the path of the object or list the record belongs to, which
+	// must not have been nulled for the payload of the record to be sent
+	parent  *ResponsePath
+	execute func(reqCtx *RequestContext) (payload map[string]interface{}, value interface{})
+}
+
+// incrementalState collects the records added while executing a payload.
+type incrementalState struct {
+	records []*incrementalRecord
+	sync.Mutex
+}
+
+func (state *incrementalState) add(record *incrementalRecord) {
+	state.Lock()
+	state.records = append(state.records, record)
+	state.Unlock()
+}
+
+// released returns the records whose parent is still part of the value of the
+// payload at the given path.
+func (state *incrementalState) released(path *ResponsePath, value interface{}) []*incrementalRecord {
+	prefix := len(path.AsArray())
+	records := []*incrementalRecord{}
+	for _, record := range state.records {
+		if reachable(value, record.parent.AsArray()[prefix:]) {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+// reachable reports whether following the keys from the value leads to a
+// value that is not null.
+func reachable(value interface{}, keys []interface{}) bool {
+	for _, key := range keys {
+		switch key := key.(type) {
+		case string:
+			switch object := value.(type) {
+			case map[string]interface{}:
+				value = object[key]
+			case *OrderedMap:
+				value, _ = object.Get(key)
+			default:
+				return false
+			}
+		case int:
+			list := reflect.ValueOf(value)
+			if list.Kind() != reflect.Slice && list.Kind() != reflect.Array || key >= list.Len() {
+				return false
+			}
+			value = list.Index(key).Interface()
+		}
+	}
+	return value != nil
+}
+
+// ExecuteIncremental executes the request like ExecuteContext, delivering the
+// fragments carrying the @defer directive and the items of the list fields
+// carrying the @stream directive after the rest of the result. The payloads
+// are executed one after the other, once the previous one has been read.
+func (executor *Executor) ExecuteIncremental(ctx context.Context, appContext interface{}, request string, variables map[string]interface{}, operationName string) (*IncrementalResult, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	subsequent := make(chan map[string]interface{})
+	incremental := &IncrementalResult{
+		Subsequent: subsequent,
+		done:       make(chan struct{}),
+		cancel:     cancel,
+	}
+	state := &incrementalState{}
+	result, reqCtx, err := executor.execute(ctx, appContext, request, variables, operationName, state)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	incremental.Initial = result
+	var records []*incrementalRecord
+	if reqCtx != nil {
+		records = state.released(nil, result["data"])
+	}
+	if len(records) == 0 {
+		close(subsequent)
+		cancel()
+		return incremental, nil
+	}
+	result["hasNext"] = true
+
+	go func() {
+		defer close(subsequent)
+		defer cancel()
+		for len(records) > 0 {
+			payload, released := executor.executeRecord(reqCtx, records[0])
+			records = append(records[1:], released...)
+			payload["hasNext"] = len(records) > 0
+			select {
+			case subsequent <- payload:
+			case <-incremental.done:
+				return
+			}
+		}
+	}()
+	return incremental, nil
+}
+
+// executeRecord produces the payload of the record, returning along with it
+// the records added while executing it.
+func (executor *Executor) executeRecord(reqCtx *RequestContext, record *incrementalRecord) (map[string]interface{}, []*incrementalRecord) {
+	state := &incrementalState{}
+	payloadCtx := &RequestContext{
+		Ctx:                     reqCtx.Ctx,
+		AppContext:              reqCtx.AppContext,
+		Document:                reqCtx.Document,
+		ErrorList:               &ErrorList{},
+		Variables:               reqCtx.Variables,
+		VariableDefinitionIndex: reqCtx.VariableDefinitionIndex,
+		Loaders:                 reqCtx.Loaders,
+		workers:                 reqCtx.workers,
+		plans:                   reqCtx.plans,
+		incremental:             state,
+	}
+	payload, value := record.execute(payloadCtx)
+	path := record.path.AsArray()
+	if path == nil {
+		path = []interface{}{}
+	}
+	payload["path"] = path
+	if record.label != "" {
+		payload["label"] = record.label
+	}
+	if len(payloadCtx.ErrorList.Errors) > 0 {
+		errors, _ := payload["errors"].([]map[string]interface{})
+		for _, err := range payloadCtx.ErrorList.Errors {
+			errors = append(errors, executor.ErrorHandler(err))
+		}
+		payload["errors"] = errors
+	}
+	return payload, state.released(record.path, value)
+}
+
+// payloadError adds an error that escaped the execution of a payload to it.
+func payloadError(payload map[string]interface{}, err error) map[string]interface{} {
+	if _, ok := err.(*GraphQLError); !ok {
+		err = &GraphQLError{
+			Message: err.Error(),
+		}
+	}
+	payload, _ = handleGQLError(payload, err)
+	return payload
+}
+
+// deferredFragment returns the fragment to defer if the directives of a
+// fragment spread or inline fragment include an enabled @defer directive.
+func (executor *Executor) deferredFragment(reqCtx *RequestContext, directiveIndex map[string]*Directive, selectionSet *SelectionSet) (*deferredFragment, error) {
+	directive, ok := directiveIndex["defer"]
+	if !ok {
+		return nil, nil
+	}
+	enabled, label, err := executor.incrementalArguments(reqCtx, directive)
+	if err != nil || !enabled {
+		return nil, err
+	}
+	return &deferredFragment{
+		label:        label,
+		selectionSet: selectionSet,
+	}, nil
+}
+
+// incrementalArguments returns the values of the "if" and "label" arguments of
+// a @defer or @stream directive.
+func (executor *Executor) incrementalArguments(reqCtx *RequestContext, directive *Directive) (bool, string, error) {
+	enabled, label := true, ""
+	if argument, ok := directive.ArgumentIndex["if"]; ok {
+		value, err := executor.valueFromAST(reqCtx.AppContext, argument.Value, &NamedType{Name: &Name{Value: "Boolean"}}, reqCtx.Variables, reqCtx.VariableDefinitionIndex)
+		if err != nil {
+			return false, "", err
+		}
+		if value, ok := value.(bool); ok {
+			enabled = value
+		}
+	}
+	if argument, ok := directive.ArgumentIndex["label"]; ok {
+		value, err := executor.valueFromAST(reqCtx.AppContext, argument.Value, &NamedType{Name: &Name{Value: "String"}}, reqCtx.Variables, reqCtx.VariableDefinitionIndex)
+		if err != nil {
+			return false, "", err
+		}
+		label, _ = value.(string)
+	}
+	return enabled, label, nil
+}
+
+// deferFragments adds a record executing each of the deferred fragments on
+// the object at the given path.
+func (executor *Executor) deferFragments(reqCtx *RequestContext, path *ResponsePath, isParallel bool, objectType *ObjectTypeDefinition, source interface{}, deferred []*deferredFragment) {
+	if prepared, ok := source.(*preparedObject); ok {
+		source = prepared.value
+	}
+	for _, fragment := range deferred {
+		fragment := fragment
+		reqCtx.incremental.add(&incrementalRecord{
+			label:  fragment.label,
+			path:   path,
+			parent: path,
+			execute: func(reqCtx *RequestContext) (map[string]interface{}, interface{}) {
+				payload := map[string]interface{}{}
+				data, err := executor.selectionSet(reqCtx, path, isParallel, objectType, source, fragment.selectionSet)
+				if err != nil {
+					payload = payloadError(payload, err)
+					data = nil
+				}
+				payload["data"] = data
+				return payload, data
+			},
+		})
+	}
+}
+
+// streamInitialCount returns the number of items of the list field sent in the
+// initial payload when the field carries an enabled @stream directive, or -1
+// when all the items are.
+func (executor *Executor) streamInitialCount(reqCtx *RequestContext, path *ResponsePath, field *Field) (int, error) {
+	if reqCtx.incremental == nil || path == nil {
+		return -1, nil
+	}
+	// Only the outermost list of the field is streamed
+	if _, ok := path.Key.(string); !ok {
+		return -1, nil
+	}
+	directive, ok := field.DirectiveIndex["stream"]
+	if !ok {
+		return -1, nil
+	}
+	enabled, _, err := executor.incrementalArguments(reqCtx, directive)
+	if err != nil || !enabled {
+		return -1, err
+	}
+	initialCount := 0
+	if argument, ok := directive.ArgumentIndex["initialCount"]; ok {
+		value, err := executor.valueFromAST(reqCtx.AppContext, argument.Value, &NamedType{Name: &Name{Value: "Int"}}, reqCtx.Variables, reqCtx.VariableDefinitionIndex)
+		if err != nil {
+			return -1, err
+		}
+		if value, ok := value.(int32); ok {
+			initialCount = int(value)
+		}
+	}
+	if initialCount < 0 {
+		return -1, &GraphQLError{
+			Message: fmt.Sprintf("initialCount of @stream on field %q must be a non-negative integer", field.Name.Value),
+			Field:   field,
+			Path:    path.AsArray(),
+		}
+	}
+	return initialCount, nil
+}
+
+// streamItems adds a record completing each of the items of the list from the
+// given index.
+func (executor *Executor) streamItems(reqCtx *RequestContext, path *ResponsePath, objectType *ObjectTypeDefinition, itemType ASTNode, field *Field, list reflect.Value, from int, subSelectionSet *SelectionSet) {
+	label := ""
+	if directive, ok := field.DirectiveIndex["stream"]; ok {
+		_, label, _ = executor.incrementalArguments(reqCtx, directive)
+	}
+	for index := from; index < list.Len(); index++ {
+		itemPath := path.WithKey(index)
+		item := list.Index(index).Interface()
+		reqCtx.incremental.add(&incrementalRecord{
+			label:  label,
+			path:   itemPath,
+			parent: path,
+			execute: func(reqCtx *RequestContext) (map[string]interface{}, interface{}) {
+				payload := map[string]interface{}{}
+				value, err := executor.completeValueCatchingError(reqCtx, itemPath, objectType, itemType, field, item, subSelectionSet)
+				if err != nil {
+					payload = payloadError(payload, err)
+					payload["items"] = nil
+					return payload, nil
+				}
+				payload["items"] = []interface{}{value}
+				return payload, value
+			},
+		})
+	}
+}
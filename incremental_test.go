@@ -0,0 +1,236 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestIncremental(t *testing.T) {
+
+	Convey("Execute: Delivers deferred fragments and streamed items incrementally", t, func() {
+		schema := `
+        type Item {
+            id: Int
+            name: String
+            fail: String
+        }
+
+        type Query {
+            hero: Item
+            items: [Item]
+            numbers: [Int]
+            broken: String!
+        }
+        `
+		resolvers := map[string]interface{}{}
+		resolvers["Query/hero"] = func(params *ResolveParams) (interface{}, error) {
+			return map[string]interface{}{"id": 1, "name": "Luke"}, nil
+		}
+		resolvers["Query/items"] = func(params *ResolveParams) (interface{}, error) {
+			return []interface{}{
+				map[string]interface{}{"id": 1, "name": "one"},
+				map[string]interface{}{"id": 2, "name": "two"},
+				map[string]interface{}{"id": 3, "name": "three"},
+			}, nil
+		}
+		resolvers["Query/numbers"] = func(params *ResolveParams) (interface{}, error) {
+			return []interface{}{1, 2, 3}, nil
+		}
+		resolvers["Query/broken"] = func(params *ResolveParams) (interface{}, error) {
+			return nil, nil
+		}
+		resolvers["Item/fail"] = func(params *ResolveParams) (interface{}, error) {
+			return nil, errors.New("failed")
+		}
+		executor, err := NewExecutor(schema, "Query", "", resolvers)
+		So(err, ShouldEqual, nil)
+		executor.Scheduler = NewScheduler(1, 1)
+
+		execute := func(request string, variables map[string]interface{}) (map[string]interface{}, []map[string]interface{}) {
+			result, err := executor.ExecuteIncremental(context.Background(), nil, request, variables, "")
+			So(err, ShouldEqual, nil)
+			defer result.Close()
+			payloads := []map[string]interface{}{}
+			for payload := range result.Subsequent {
+				payloads = append(payloads, payload)
+			}
+			return result.Initial, payloads
+		}
+
+		Convey("by sending deferred fragments after the initial payload", func() {
+			initial, payloads := execute(`{ hero { id ... @defer(label: "details") { name } } }`, nil)
+			So(initial, ShouldResemble, map[string]interface{}{
+				"data": map[string]interface{}{
+					"hero": map[string]interface{}{"id": int32(1)},
+				},
+				"hasNext": true,
+			})
+			So(payloads, ShouldResemble, []map[string]interface{}{
+				{
+					"data":    map[string]interface{}{"name": "Luke"},
+					"path":    []interface{}{"hero"},
+					"label":   "details",
+					"hasNext": false,
+				},
+			})
+		})
+
+		Convey("by deferring named fragments and nested fragments", func() {
+			initial, payloads := execute(`{ ...root @defer } fragment root on Query { hero { id ... @defer { name } } }`, nil)
+			So(initial, ShouldResemble, map[string]interface{}{
+				"data":    map[string]interface{}{},
+				"hasNext": true,
+			})
+			So(payloads, ShouldResemble, []map[string]interface{}{
+				{
+					"data": map[string]interface{}{
+						"hero": map[string]interface{}{"id": int32(1)},
+					},
+					"path":    []interface{}{},
+					"hasNext": true,
+				},
+				{
+					"data":    map[string]interface{}{"name": "Luke"},
+					"path":    []interface{}{"hero"},
+					"hasNext": false,
+				},
+			})
+		})
+
+		Convey("by sending the items of streamed lists one by one", func() {
+			initial, payloads := execute(`{ numbers @stream(initialCount: 1) items @stream(initialCount: 2, label: "items") { id } }`, nil)
+			So(initial, ShouldResemble, map[string]interface{}{
+				"data": map[string]interface{}{
+					"numbers": []interface{}{int32(1)},
+					"items": []interface{}{
+						map[string]interface{}{"id": int32(1)},
+						map[string]interface{}{"id": int32(2)},
+					},
+				},
+				"hasNext": true,
+			})
+			So(payloads, ShouldResemble, []map[string]interface{}{
+				{
+					"items":   []interface{}{int32(2)},
+					"path":    []interface{}{"numbers", 1},
+					"hasNext": true,
+				},
+				{
+					"items":   []interface{}{int32(3)},
+					"path":    []interface{}{"numbers", 2},
+					"hasNext": true,
+				},
+				{
+					"items":   []interface{}{map[string]interface{}{"id": int32(3)}},
+					"path":    []interface{}{"items", 2},
+					"label":   "items",
+					"hasNext": false,
+				},
+			})
+		})
+
+		Convey("by streaming every item after an initial count of 0", func() {
+			initial, payloads := execute(`{ numbers @stream(initialCount: 0) }`, nil)
+			So(initial["data"], ShouldResemble, map[string]interface{}{"numbers": []interface{}{}})
+			So(len(payloads), ShouldEqual, 3)
+
+			initial, payloads = execute(`{ numbers @stream(initialCount: -1) }`, nil)
+			So(initial["errors"], ShouldResemble, []map[string]interface{}{
+				{
+					"message":   "initialCount of @stream on field \"numbers\" must be a non-negative integer",
+					"locations": []map[string]interface{}{{"line": 1, "column": 3}},
+					"path":      []interface{}{"numbers"},
+				},
+			})
+			So(payloads, ShouldBeEmpty)
+		})
+
+		Convey("by deferring fragments of streamed items with ordered results", func() {
+			executor.OrderedResults = true
+			initial, payloads := execute(`{ items @stream(initialCount: 1) { id ... @defer { name } } }`, nil)
+			output, err := json.Marshal(initial)
+			So(err, ShouldEqual, nil)
+			So(string(output), ShouldEqual, `{"data":{"items":[{"id":1}]},"hasNext":true}`)
+			output, err = json.Marshal(payloads)
+			So(err, ShouldEqual, nil)
+			So(string(output), ShouldEqual, `[`+
+				`{"hasNext":true,"items":[{"id":2}],"path":["items",1]},`+
+				`{"hasNext":true,"items":[{"id":3}],"path":["items",2]},`+
+				`{"data":{"name":"one"},"hasNext":true,"path":["items",0]},`+
+				`{"data":{"name":"two"},"hasNext":true,"path":["items",1]},`+
+				`{"data":{"name":"three"},"hasNext":false,"path":["items",2]}]`)
+		})
+
+		Convey("by reporting the errors of a payload with it", func() {
+			_, payloads := execute(`{ hero { id ... @defer { fail } } }`, nil)
+			So(payloads, ShouldResemble, []map[string]interface{}{
+				{
+					"data": map[string]interface{}{"fail": nil},
+					"path": []interface{}{"hero"},
+					"errors": []map[string]interface{}{
+						{
+							"message": "failed",
+							"locations": []map[string]interface{}{
+								{"line": 1, "column": 26},
+							},
+							"path": []interface{}{"hero", "fail"},
+						},
+					},
+					"hasNext": false,
+				},
+			})
+		})
+
+		Convey("but not the payloads of values that were nulled", func() {
+			initial, payloads := execute(`{ hero { ... @defer { name } } broken }`, nil)
+			So(initial["data"], ShouldBeNil)
+			So(initial["hasNext"], ShouldEqual, nil)
+			So(payloads, ShouldBeEmpty)
+		})
+
+		Convey("unless the directives are disabled", func() {
+			request := `query q($defer: Boolean) { hero { id ... @defer(if: $defer) { name } } numbers @stream(if: $defer) }`
+			initial, payloads := execute(request, map[string]interface{}{"defer": false})
+			So(initial, ShouldResemble, map[string]interface{}{
+				"data": map[string]interface{}{
+					"hero":    map[string]interface{}{"id": int32(1), "name": "Luke"},
+					"numbers": []interface{}{int32(1), int32(2), int32(3)},
+				},
+			})
+			So(payloads, ShouldBeEmpty)
+
+			initial, payloads = execute(request, map[string]interface{}{"defer": true})
+			So(initial["hasNext"], ShouldEqual, true)
+			So(len(payloads), ShouldEqual, 4)
+		})
+
+		Convey("while Execute ignores the directives", func() {
+			result, err := executor.Execute(nil, `{ hero { id ... @defer { name } } numbers @stream }`, map[string]interface{}{}, "")
+			So(err, ShouldEqual, nil)
+			So(result, ShouldResemble, map[string]interface{}{
+				"data": map[string]interface{}{
+					"hero":    map[string]interface{}{"id": int32(1), "name": "Luke"},
+					"numbers": []interface{}{int32(1), int32(2), int32(3)},
+				},
+			})
+		})
+
+		Convey("and stops executing payloads once closed", func() {
+			result, err := executor.ExecuteIncremental(context.Background(), nil, `{ numbers @stream }`, map[string]interface{}{}, "")
+			So(err, ShouldEqual, nil)
+			So(<-result.Subsequent, ShouldResemble, map[string]interface{}{
+				"items":   []interface{}{int32(1)},
+				"path":    []interface{}{"numbers", 0},
+				"hasNext": true,
+			})
+			result.Close()
+			for range result.Subsequent {
+			}
+		})
+	})
+
+}
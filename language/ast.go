@@ -32,6 +32,8 @@ type Document struct {
 	TypeIndex            map[string]ASTNode
 	OperationIndex       map[string]*OperationDefinition
 	PossibleTypesIndex   map[string][]*ObjectTypeDefinition
+	DirectiveIndex       map[string]*DirectiveDefinition
+	SchemaDefinition     *SchemaDefinition
 	LOC                  *LOC
 }
 
@@ -278,13 +280,21 @@ type Enum struct {
 	LOC   *LOC
 }
 
+// Null is the literal null value, which sets an argument or input field to
+// null explicitly.
+type Null struct {
+	LOC *LOC
+}
+
 type ObjectTypeDefinition struct {
-	Name        *Name
-	Description string
-	Interfaces  []*NamedType
-	Fields      []*FieldDefinition
-	FieldIndex  map[string]*FieldDefinition
-	LOC         *LOC
+	Name           *Name
+	Description    string
+	Interfaces     []*NamedType
+	Fields         []*FieldDefinition
+	FieldIndex     map[string]*FieldDefinition
+	Directives     []*Directive
+	DirectiveIndex map[string]*Directive
+	LOC            *LOC
 }
 
 type FieldDefinition struct {
@@ -295,42 +305,56 @@ type FieldDefinition struct {
 	Arguments         []*InputValueDefinition
 	ArgumentIndex     map[string]*InputValueDefinition
 	Type              ASTNode
+	Directives        []*Directive
+	DirectiveIndex    map[string]*Directive
 	LOC               *LOC
 }
 
 type InputValueDefinition struct {
-	Name         *Name
-	Description  string
-	Type         ASTNode
-	DefaultValue ASTNode
-	LOC          *LOC
+	Name              *Name
+	Description       string
+	IsDeprecated      bool
+	DeprecationReason string
+	Type              ASTNode
+	DefaultValue      ASTNode
+	Directives        []*Directive
+	DirectiveIndex    map[string]*Directive
+	LOC               *LOC
 }
 
 type InterfaceTypeDefinition struct {
-	Name        *Name
-	Description string
-	Fields      []*FieldDefinition
-	LOC         *LOC
+	Name           *Name
+	Description    string
+	Fields         []*FieldDefinition
+	Directives     []*Directive
+	DirectiveIndex map[string]*Directive
+	LOC            *LOC
 }
 
 type UnionTypeDefinition struct {
-	Name        *Name
-	Description string
-	Types       []*NamedType
-	LOC         *LOC
+	Name           *Name
+	Description    string
+	Types          []*NamedType
+	Directives     []*Directive
+	DirectiveIndex map[string]*Directive
+	LOC            *LOC
 }
 
 type ScalarTypeDefinition struct {
-	Name        *Name
-	Description string
-	LOC         *LOC
+	Name           *Name
+	Description    string
+	Directives     []*Directive
+	DirectiveIndex map[string]*Directive
+	LOC            *LOC
 }
 
 type EnumTypeDefinition struct {
-	Name        *Name
-	Description string
-	Values      []*EnumValueDefinition
-	LOC         *LOC
+	Name           *Name
+	Description    string
+	Values         []*EnumValueDefinition
+	Directives     []*Directive
+	DirectiveIndex map[string]*Directive
+	LOC            *LOC
 }
 
 type EnumValueDefinition struct {
@@ -338,19 +362,80 @@ type EnumValueDefinition struct {
 	Description       string
 	IsDeprecated      bool
 	DeprecationReason string
+	Directives        []*Directive
+	DirectiveIndex    map[string]*Directive
 	LOC               *LOC
 }
 
 type InputObjectTypeDefinition struct {
-	Name        *Name
+	Name           *Name
+	Description    string
+	Fields         []*InputValueDefinition
+	FieldIndex     map[string]*InputValueDefinition
+	Directives     []*Directive
+	DirectiveIndex map[string]*Directive
+	LOC            *LOC
+}
+
+// DIRECTIVE_LOCATIONS are the locations a directive may be defined to be used
+// at, starting with the locations in requests followed by those in the schema.
+var DIRECTIVE_LOCATIONS = []string{
+	"QUERY",
+	"MUTATION",
+	"SUBSCRIPTION",
+	"FIELD",
+	"FRAGMENT_DEFINITION",
+	"FRAGMENT_SPREAD",
+	"INLINE_FRAGMENT",
+	"VARIABLE_DEFINITION",
+	"SCHEMA",
+	"SCALAR",
+	"OBJECT",
+	"FIELD_DEFINITION",
+	"ARGUMENT_DEFINITION",
+	"INTERFACE",
+	"UNION",
+	"ENUM",
+	"ENUM_VALUE",
+	"INPUT_OBJECT",
+	"INPUT_FIELD_DEFINITION",
+}
+
+type DirectiveDefinition struct {
+	Name          *Name
+	Description   string
+	Arguments     []*InputValueDefinition
+	ArgumentIndex map[string]*InputValueDefinition
+	Locations     []*Name
+	LOC           *LOC
+}
+
+type SchemaDefinition struct {
+	Description    string
+	Directives     []*Directive
+	DirectiveIndex map[string]*Directive
+	OperationTypes []*OperationTypeDefinition
+	LOC            *LOC
+}
+
+// OperationTypeDefinition names the root type of an operation in the schema
+// definition, such as "query: Query".
+type OperationTypeDefinition struct {
+	Operation string
+	Type      *NamedType
+	LOC       *LOC
+}
+
+type SchemaExtensionDefinition struct {
 	Description string
-	Fields      []*InputValueDefinition
-	FieldIndex  map[string]*InputValueDefinition
+	Definition  *SchemaDefinition
 	LOC         *LOC
 }
 
 type TypeExtensionDefinition struct {
 	Description string
-	Definition  *ObjectTypeDefinition
-	LOC         *LOC
+	// Definition is the extended type definition, which can be any of the
+	// object, interface, union, scalar, enum or input object type definitions.
+	Definition ASTNode
+	LOC        *LOC
 }
@@ -0,0 +1,19 @@
+package language
+
+import "github.com/smartystreets/goconvey/convey"
+
+// The convey package cannot be dot-imported in this package as both declare
+// Print, so the tests use these names instead.
+var (
+	Convey                 = convey.Convey
+	So                     = convey.So
+	ShouldEqual            = convey.ShouldEqual
+	ShouldNotEqual         = convey.ShouldNotEqual
+	ShouldResemble         = convey.ShouldResemble
+	ShouldPointTo          = convey.ShouldPointTo
+	ShouldContain          = convey.ShouldContain
+	ShouldNotContain       = convey.ShouldNotContain
+	ShouldContainSubstring = convey.ShouldContainSubstring
+	ShouldHaveSameTypeAs   = convey.ShouldHaveSameTypeAs
+	ShouldPanicWith        = convey.ShouldPanicWith
+)
@@ -9,9 +9,12 @@ import (
 type GraphQLError struct {
 	Message string
 	Field   *Field
-	Source  string
-	Start   *Position
-	End     *Position
+	// Path is the path in the response of the field that caused the error
+	// during execution, made of response keys and list indices.
+	Path   []interface{}
+	Source string
+	Start  *Position
+	End    *Position
 }
 
 func (err *GraphQLError) Error() string {
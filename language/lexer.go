@@ -8,6 +8,9 @@ import (
 	"unicode/utf8"
 )
 
+// StateFn lexes the input from the current position of the lexer, emitting at
+// most one token, and returns the state to continue with or nil once the input
+// is exhausted or an illegal token was emitted.
 type StateFn func(*Lexer) StateFn
 
 type TokenType int
@@ -116,11 +119,14 @@ func (tokenType TokenType) String() string {
 	}
 }
 
+// Token is a lexical token of a document. Its value is a slice of the input,
+// except for strings which are unquoted, so that lexing punctuators and names
+// does not allocate.
 type Token struct {
 	Type  TokenType
 	Val   string
-	Start *Position
-	End   *Position
+	Start Position
+	End   Position
 }
 
 func (token Token) String() string {
@@ -138,24 +144,31 @@ func (token Token) String() string {
 	return fmt.Sprintf("%s", token.Val)
 }
 
+// Lexer is a pull lexer, running its state functions on demand until they
+// emit the next token.
 type Lexer struct {
-	Input        string
-	Line         int
-	Column       int
-	Start        int
-	Pos          int
-	Width        int
-	InitialState StateFn
-	Tokens       chan Token
+	Input   string
+	Line    int
+	Column  int
+	Start   int
+	Pos     int
+	Width   int
+	state   StateFn
+	token   Token
+	emitted bool
 }
 
-func (lexer *Lexer) Run() {
-	lexer.Line = 1
-	lexer.Column = 1
-	for state := lexer.InitialState; state != nil; {
-		state = state(lexer)
+// NextToken returns the next token of the input. Once the input is exhausted,
+// or an illegal token was returned, it keeps returning the last token.
+func (lexer *Lexer) NextToken() Token {
+	for !lexer.emitted {
+		if lexer.state == nil {
+			return lexer.token
+		}
+		lexer.state = lexer.state(lexer)
 	}
-	close(lexer.Tokens)
+	lexer.emitted = false
+	return lexer.token
 }
 
 func (lexer *Lexer) runeToString(rn rune) string {
@@ -171,33 +184,36 @@ func (lexer *Lexer) runeToString(rn rune) string {
 	return character
 }
 
-func (lexer *Lexer) Emit(tokenType TokenType) {
+func (lexer *Lexer) positions() (Position, Position) {
 	line := lexer.Line
 	column := lexer.Column - utf8.RuneCountInString(lexer.Input[lexer.Start:lexer.Pos])
 	start := lexer.Start
 	end := lexer.Pos
-	startPos := &Position{
+	startPos := Position{
 		Index:  start,
 		Line:   line,
 		Column: column,
 	}
-	endPos := &Position{
+	endPos := Position{
 		Index:  end,
 		Line:   line,
 		Column: column + end - start,
 	}
-	var value string
-	var err error
-	buffer := make([]byte, lexer.Pos-lexer.Start)
-	copy(buffer, []byte(lexer.Input[lexer.Start:lexer.Pos]))
-	value = string(buffer)
+	return startPos, endPos
+}
+
+func (lexer *Lexer) Emit(tokenType TokenType) {
+	startPos, endPos := lexer.positions()
+	value := lexer.Input[lexer.Start:lexer.Pos]
 	if tokenType == STRING {
+		var err error
 		value, err = strconv.Unquote(value)
 		if err != nil {
 			panic(err)
 		}
 	}
-	lexer.Tokens <- Token{tokenType, value, startPos, endPos}
+	lexer.token = Token{tokenType, value, startPos, endPos}
+	lexer.emitted = true
 	lexer.Start = lexer.Pos
 	lexer.Width = 0
 }
@@ -258,37 +274,30 @@ func (lexer *Lexer) AcceptString(valid string) bool {
 }
 
 func (lexer *Lexer) Errorf(format string, args ...interface{}) StateFn {
-	line := lexer.Line
-	column := lexer.Column - utf8.RuneCountInString(lexer.Input[lexer.Start:lexer.Pos])
-	start := lexer.Start
-	end := lexer.Pos
-	startPos := &Position{
-		Index:  start,
-		Line:   line,
-		Column: column,
-	}
-	endPos := &Position{
-		Index:  end,
-		Line:   line,
-		Column: column + end - start,
-	}
-	lexer.Tokens <- Token{
+	startPos, endPos := lexer.positions()
+	lexer.token = Token{
 		ILLEGAL,
 		fmt.Sprintf(format, args...),
 		startPos,
 		endPos,
 	}
+	lexer.emitted = true
 	return nil
 }
 
-func Lex(initialState StateFn, input string) chan Token {
-	lexer := &Lexer{
-		Input:        input,
-		Tokens:       make(chan Token),
-		InitialState: initialState,
+// Lex returns a lexer of the input starting in the given state.
+func Lex(initialState StateFn, input string) *Lexer {
+	return &Lexer{
+		Input:  input,
+		Line:   1,
+		Column: 1,
+		state:  initialState,
 	}
-	go lexer.Run()
-	return lexer.Tokens
+}
+
+// NewLexer returns a lexer of a GraphQL document.
+func NewLexer(input string) *Lexer {
+	return Lex(LexText, input)
 }
 
 func IsWhiteSpace(rn rune) bool {
@@ -325,28 +334,40 @@ func LexText(lexer *Lexer) StateFn {
 			lexer.Ignore()
 		case rn == '!':
 			lexer.Emit(BANG)
+			return LexText
 		case rn == '$':
 			lexer.Emit(DOLLAR)
+			return LexText
 		case rn == '(':
 			lexer.Emit(LPAREN)
+			return LexText
 		case rn == ')':
 			lexer.Emit(RPAREN)
+			return LexText
 		case rn == ':':
 			lexer.Emit(COLON)
+			return LexText
 		case rn == '=':
 			lexer.Emit(EQ)
+			return LexText
 		case rn == '@':
 			lexer.Emit(AT)
+			return LexText
 		case rn == '[':
 			lexer.Emit(LBRACK)
+			return LexText
 		case rn == ']':
 			lexer.Emit(RBRACK)
+			return LexText
 		case rn == '{':
 			lexer.Emit(LBRACE)
+			return LexText
 		case rn == '}':
 			lexer.Emit(RBRACE)
+			return LexText
 		case rn == '|':
 			lexer.Emit(PIPE)
+			return LexText
 		case rn == '#':
 			lexer.Backup()
 			return LexComment
@@ -356,6 +377,7 @@ func LexText(lexer *Lexer) StateFn {
 		case rn == '.':
 			if lexer.AcceptString("..") {
 				lexer.Emit(SPREAD)
+				return LexText
 			} else {
 				return lexer.Errorf(`GraphQL Syntax Error (%d:%d) Invalid character "%s" found in document`, lexer.Line, lexer.Column-1, lexer.runeToString(rn))
 			}
@@ -373,8 +395,6 @@ func LexText(lexer *Lexer) StateFn {
 			return lexer.Errorf(`GraphQL Syntax Error (%d:%d) Invalid character "%s" found in document`, lexer.Line, lexer.Column-1, lexer.runeToString(rn))
 		}
 	}
-	lexer.Emit(EOF)
-	return nil
 }
 
 func LexNumber(lexer *Lexer) StateFn {
@@ -435,7 +455,6 @@ func LexComment(lexer *Lexer) StateFn {
 			return LexText
 		}
 	}
-	return LexText
 }
 
 func LexQuote(lexer *Lexer) StateFn {
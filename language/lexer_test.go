@@ -1,7 +1,7 @@
 package language
 
 import (
-	. "github.com/smartystreets/goconvey/convey"
+	"runtime"
 	"strconv"
 	"strings"
 	"testing"
@@ -63,16 +63,16 @@ func verifyTokens(input string, result []Token, expectedResult []expectedToken)
 }
 
 func LexInput(initialState StateFn, input string) ([]Token, error) {
-	tokens := Lex(initialState, input)
+	lexer := Lex(initialState, input)
 	result := []Token{}
 	for {
-		token := <-tokens
+		token := lexer.NextToken()
 		if token.Type == ILLEGAL {
 			return nil, &GraphQLError{
 				Message: token.Val,
 				Source:  input,
-				Start:   token.Start,
-				End:     token.End,
+				Start:   &token.Start,
+				End:     &token.End,
 			}
 		}
 		result = append(result, token)
@@ -410,6 +410,42 @@ func TestLexer(t *testing.T) {
 			So(err.Error(), ShouldEqual, "GraphQL Syntax Error (1:5) Invalid number, expected digit but got: \"A\"\n\n1|1.0eA\n  ^^^^^")
 			So(result, ShouldEqual, nil)
 		})
+
+		Convey("lexes tokens on demand", func() {
+			lexer := NewLexer(`{ a }`)
+			So(lexer.NextToken().Type, ShouldEqual, LBRACE)
+			So(lexer.Pos, ShouldEqual, 1)
+			So(lexer.NextToken().Val, ShouldEqual, "a")
+			So(lexer.NextToken().Type, ShouldEqual, RBRACE)
+			So(lexer.NextToken().Type, ShouldEqual, EOF)
+			So(lexer.NextToken().Type, ShouldEqual, EOF)
+
+			lexer = NewLexer(`a ?`)
+			So(lexer.NextToken().Type, ShouldEqual, NAME)
+			So(lexer.NextToken().Type, ShouldEqual, ILLEGAL)
+			So(lexer.NextToken().Type, ShouldEqual, ILLEGAL)
+		})
+
+		Convey("lexes punctuators and names without allocating", func() {
+			input := `{ a(b: $c) @d { ...e } [f!] = g | h }`
+			allocs := testing.AllocsPerRun(100, func() {
+				lexer := Lex(LexText, input)
+				for lexer.NextToken().Type != EOF {
+				}
+			})
+			// The lexer itself
+			So(allocs, ShouldEqual, 1)
+		})
+
+		Convey("does not leave goroutines behind on malformed input", func() {
+			goroutines := runtime.NumGoroutine()
+			for i := 0; i < 100; i++ {
+				parser := &Parser{}
+				_, err := parser.Parse(&ParseParams{Source: `{ a b c ) d e f g }`})
+				So(err, ShouldNotEqual, nil)
+			}
+			So(runtime.NumGoroutine(), ShouldEqual, goroutines)
+		})
 	})
 
 }
@@ -7,12 +7,16 @@ import (
 )
 
 type Parser struct {
-	tokens    chan Token
-	lookahead *Token
-	prevEnd   *Position
-	source    string
-	ast       interface{}
-	noSource  bool
+	lexer     *Lexer
+	lookahead Token
+	// start and end are the positions of the lookahead token, allocated once
+	// per token and shared by the locations of the nodes it starts or ends
+	start    *Position
+	end      *Position
+	prevEnd  *Position
+	source   string
+	ast      interface{}
+	noSource bool
 }
 
 type ParseParams struct {
@@ -23,57 +27,44 @@ type ParseParams struct {
 func (parser *Parser) Parse(params *ParseParams) (*Document, error) {
 	parser.source = params.Source
 	parser.noSource = params.NoSource
-	parser.tokens = Lex(LexText, parser.source)
-	token := <-parser.tokens
+	parser.lexer = NewLexer(parser.source)
+	token := parser.lexer.NextToken()
 	if token.Type == ILLEGAL {
-		return nil, &GraphQLError{
-			Message: token.Val,
-			Source:  parser.source,
-			Start:   token.Start,
-			End:     token.End,
-		}
+		return nil, parser.tokenError(token, token.Val)
 	}
-	parser.lookahead = &token
+	parser.advance(token)
 	return parser.document()
 }
 
+// advance makes the token the lookahead token.
+func (parser *Parser) advance(token Token) {
+	positions := &[2]Position{token.Start, token.End}
+	parser.lookahead = token
+	parser.start = &positions[0]
+	parser.end = &positions[1]
+}
+
 func (parser *Parser) match(symbol TokenType) error {
 	if parser.lookahead.Type == symbol {
-		parser.prevEnd = parser.lookahead.End
-		token := <-parser.tokens
+		parser.prevEnd = parser.end
+		token := parser.lexer.NextToken()
 		if token.Type == ILLEGAL {
-			return &GraphQLError{
-				Message: token.Val,
-				Source:  parser.source,
-				Start:   token.Start,
-				End:     token.End,
-			}
+			return parser.tokenError(token, token.Val)
 		}
-		parser.lookahead = &token
+		parser.advance(token)
 		return nil
 	} else {
-		return &GraphQLError{
-			Message: fmt.Sprintf("GraphQL Syntax Error (%d:%d) Expected %s, found %s", parser.lookahead.Start.Line, parser.lookahead.Start.Column, symbol, parser.lookahead.String()),
-			Source:  parser.source,
-			Start:   parser.lookahead.Start,
-			End:     parser.lookahead.End,
-		}
+		return parser.tokenError(parser.lookahead, fmt.Sprintf("GraphQL Syntax Error (%d:%d) Expected %s, found %s", parser.lookahead.Start.Line, parser.lookahead.Start.Column, symbol, parser.lookahead.String()))
 	}
 }
 
 func (parser *Parser) matchName(value string) error {
 	if parser.lookahead.Type == NAME && parser.lookahead.Val == value {
-		parser.prevEnd = parser.lookahead.End
-		token := <-parser.tokens
-		parser.lookahead = &token
+		parser.prevEnd = parser.end
+		parser.advance(parser.lexer.NextToken())
 		return nil
 	} else {
-		return &GraphQLError{
-			Message: fmt.Sprintf("GraphQL Syntax Error (%d:%d) Expected \"%s\", found %s", parser.lookahead.Start.Line, parser.lookahead.Start.Column, value, parser.lookahead.String()),
-			Source:  parser.source,
-			Start:   parser.lookahead.Start,
-			End:     parser.lookahead.End,
-		}
+		return parser.tokenError(parser.lookahead, fmt.Sprintf("GraphQL Syntax Error (%d:%d) Expected \"%s\", found %s", parser.lookahead.Start.Line, parser.lookahead.Start.Column, value, parser.lookahead.String()))
 	}
 }
 
@@ -96,15 +87,26 @@ func (parser *Parser) loc(start *Position) *LOC {
 	}
 }
 
+// tokenError returns a syntax error located at the token.
+func (parser *Parser) tokenError(token Token, message string) *GraphQLError {
+	start, end := token.Start, token.End
+	return &GraphQLError{
+		Message: message,
+		Source:  parser.source,
+		Start:   &start,
+		End:     &end,
+	}
+}
+
 func (parser *Parser) name() (*Name, error) {
-	token := parser.lookahead
+	token, start := parser.lookahead, parser.start
 	err := parser.match(NAME)
 	if err != nil {
 		return nil, err
 	}
 	return &Name{
 		Value: token.Val,
-		LOC:   parser.loc(token.Start),
+		LOC:   parser.loc(start),
 	}, nil
 }
 
@@ -132,7 +134,7 @@ func (parser *Parser) description() (string, error) {
  * Document : Definition+
  */
 func (parser *Parser) document() (*Document, error) {
-	start := parser.lookahead.Start
+	start := parser.start
 	definitions := []ASTNode{}
 	fragmentIndex := map[string]*FragmentDefinition{}
 	objectTypeIndex := map[string]*ObjectTypeDefinition{}
@@ -144,6 +146,8 @@ func (parser *Parser) document() (*Document, error) {
 	typeIndex := map[string]ASTNode{}
 	typeExtensionIndex := map[string]*TypeExtensionDefinition{}
 	possibleTypesIndex := map[string][]*ObjectTypeDefinition{}
+	directiveIndex := map[string]*DirectiveDefinition{}
+	var schemaDefinition *SchemaDefinition
 	for {
 		definition, err := parser.definition()
 		if err != nil {
@@ -166,7 +170,7 @@ func (parser *Parser) document() (*Document, error) {
 				}
 			}
 		case *TypeExtensionDefinition:
-			typeExtensionIndex[item.Definition.Name.Value] = item
+			typeExtensionIndex[typeDefinitionName(item.Definition).Value] = item
 
 		case *InterfaceTypeDefinition:
 			interfaceTypeIndex[item.Name.Value] = item
@@ -183,6 +187,12 @@ func (parser *Parser) document() (*Document, error) {
 		case *ScalarTypeDefinition:
 			scalarTypeIndex[item.Name.Value] = item
 			typeIndex[item.Name.Value] = item
+		case *DirectiveDefinition:
+			directiveIndex[item.Name.Value] = item
+		case *SchemaDefinition:
+			if schemaDefinition == nil {
+				schemaDefinition = item
+			}
 		}
 		definitions = append(definitions, definition)
 		if parser.lookahead.Type == EOF {
@@ -231,6 +241,9 @@ func (parser *Parser) document() (*Document, error) {
 	if len(typeIndex) == 0 {
 		typeIndex = nil
 	}
+	if len(directiveIndex) == 0 {
+		directiveIndex = nil
+	}
 
 	return &Document{
 		Definitions:          definitions,
@@ -244,6 +257,8 @@ func (parser *Parser) document() (*Document, error) {
 		EnumTypeIndex:        enumTypeIndex,
 		PossibleTypesIndex:   possibleTypesIndex,
 		TypeIndex:            typeIndex,
+		DirectiveIndex:       directiveIndex,
+		SchemaDefinition:     schemaDefinition,
 		LOC:                  parser.loc(start),
 	}, nil
 }
@@ -253,7 +268,10 @@ func (parser *Parser) document() (*Document, error) {
  *   - OperationDefinition
  *   - FragmentDefinition
  *   - TypeDefinition
+ *   - SchemaDefinition
  *   - TypeExtensionDefinition
+ *   - SchemaExtensionDefinition
+ *   - DirectiveDefinition
  */
 func (parser *Parser) definition() (ASTNode, error) {
 	description, err := parser.description()
@@ -269,25 +287,19 @@ func (parser *Parser) definition() (ASTNode, error) {
 			return parser.operationDefinition()
 		case "type", "interface", "union", "scalar", "enum", "input":
 			return parser.typeDefinition(description)
+		case "schema":
+			return parser.schemaDefinition(description)
 		case "extend":
-			return parser.typeExtensionDefinition(description)
+			return parser.extensionDefinition(description)
+		case "directive":
+			return parser.directiveDefinition(description)
 		default:
-			return nil, &GraphQLError{
-				Message: fmt.Sprintf("GraphQL Syntax Error (%d:%d) Unexpected %s", parser.lookahead.Start.Line, parser.lookahead.Start.Column, parser.lookahead.String()),
-				Source:  parser.source,
-				Start:   parser.lookahead.Start,
-				End:     parser.lookahead.End,
-			}
+			return nil, parser.tokenError(parser.lookahead, fmt.Sprintf("GraphQL Syntax Error (%d:%d) Unexpected %s", parser.lookahead.Start.Line, parser.lookahead.Start.Column, parser.lookahead.String()))
 		}
 	case LBRACE:
 		return parser.operationDefinition()
 	default:
-		return nil, &GraphQLError{
-			Message: fmt.Sprintf("GraphQL Syntax Error (%d:%d) Unexpected %s", parser.lookahead.Start.Line, parser.lookahead.Start.Column, parser.lookahead.String()),
-			Source:  parser.source,
-			Start:   parser.lookahead.Start,
-			End:     parser.lookahead.End,
-		}
+		return nil, parser.tokenError(parser.lookahead, fmt.Sprintf("GraphQL Syntax Error (%d:%d) Unexpected %s", parser.lookahead.Start.Line, parser.lookahead.Start.Column, parser.lookahead.String()))
 	}
 }
 
@@ -299,7 +311,7 @@ func (parser *Parser) definition() (ASTNode, error) {
  * OperationType : one of query mutation subscription
  */
 func (parser *Parser) operationDefinition() (ASTNode, error) {
-	start := parser.lookahead.Start
+	start := parser.start
 	switch parser.lookahead.Type {
 	case LBRACE:
 		selectionSet, err := parser.selectionSet()
@@ -343,12 +355,7 @@ func (parser *Parser) operationDefinition() (ASTNode, error) {
 		node.LOC = parser.loc(start)
 		return node, nil
 	default:
-		return nil, &GraphQLError{
-			Message: fmt.Sprintf("GraphQL Syntax Error (%d:%d) Unexpected %s", parser.lookahead.Start.Line, parser.lookahead.Start.Column, parser.lookahead.String()),
-			Source:  parser.source,
-			Start:   parser.lookahead.Start,
-			End:     parser.lookahead.End,
-		}
+		return nil, parser.tokenError(parser.lookahead, fmt.Sprintf("GraphQL Syntax Error (%d:%d) Unexpected %s", parser.lookahead.Start.Line, parser.lookahead.Start.Column, parser.lookahead.String()))
 	}
 }
 
@@ -388,7 +395,7 @@ func (parser *Parser) variableDefinitions() ([]*VariableDefinition, map[string]*
  */
 func (parser *Parser) variableDefinition() (*VariableDefinition, error) {
 	var err error
-	start := parser.lookahead.Start
+	start := parser.start
 	node := &VariableDefinition{}
 	node.Variable, err = parser.variable()
 	if err != nil {
@@ -421,7 +428,7 @@ func (parser *Parser) variableDefinition() (*VariableDefinition, error) {
  */
 func (parser *Parser) variable() (*Variable, error) {
 	var err error
-	start := parser.lookahead.Start
+	start := parser.start
 	node := &Variable{}
 	err = parser.match(DOLLAR)
 	if err != nil {
@@ -439,7 +446,7 @@ func (parser *Parser) variable() (*Variable, error) {
  * SelectionSet : { Selection+ }
  */
 func (parser *Parser) selectionSet() (*SelectionSet, error) {
-	start := parser.lookahead.Start
+	start := parser.start
 	node := &SelectionSet{}
 	err := parser.match(LBRACE)
 	if err != nil {
@@ -475,12 +482,7 @@ func (parser *Parser) selection() (ASTNode, error) {
 	} else if parser.lookahead.Type == NAME {
 		return parser.field()
 	} else {
-		return nil, &GraphQLError{
-			Message: fmt.Sprintf(`GraphQL Syntax Error (%d:%d) Expected a selection or fragment spread, found %s`, parser.lookahead.Start.Line, parser.lookahead.Start.Column, parser.lookahead.String()),
-			Source:  parser.source,
-			Start:   parser.lookahead.Start,
-			End:     parser.lookahead.End,
-		}
+		return nil, parser.tokenError(parser.lookahead, fmt.Sprintf(`GraphQL Syntax Error (%d:%d) Expected a selection or fragment spread, found %s`, parser.lookahead.Start.Line, parser.lookahead.Start.Column, parser.lookahead.String()))
 	}
 }
 
@@ -491,7 +493,7 @@ func (parser *Parser) selection() (ASTNode, error) {
  */
 func (parser *Parser) field() (*Field, error) {
 	var err error
-	start := parser.lookahead.Start
+	start := parser.start
 	node := &Field{}
 	nameOrAlias, err := parser.name()
 	if err != nil {
@@ -565,7 +567,7 @@ func (parser *Parser) arguments() ([]*Argument, map[string]*Argument, error) {
  */
 func (parser *Parser) argument() (*Argument, error) {
 	var err error
-	start := parser.lookahead.Start
+	start := parser.start
 	node := &Argument{}
 	node.Name, err = parser.name()
 	if err != nil {
@@ -591,7 +593,7 @@ func (parser *Parser) argument() (*Argument, error) {
  * InlineFragment : ... TypeCondition? Directives? SelectionSet
  */
 func (parser *Parser) fragment() (ASTNode, error) {
-	start := parser.lookahead.Start
+	start := parser.start
 	err := parser.match(SPREAD)
 	if err != nil {
 		return nil, err
@@ -645,7 +647,7 @@ func (parser *Parser) fragment() (ASTNode, error) {
  * TypeCondition : NamedType
  */
 func (parser *Parser) fragmentDefinition() (*FragmentDefinition, error) {
-	start := parser.lookahead.Start
+	start := parser.start
 	err := parser.matchName("fragment")
 	if err != nil {
 		return nil, err
@@ -682,12 +684,7 @@ func (parser *Parser) fragmentDefinition() (*FragmentDefinition, error) {
  */
 func (parser *Parser) fragmentName() (*Name, error) {
 	if parser.lookahead.Val == "on" {
-		return nil, &GraphQLError{
-			Message: fmt.Sprintf("GraphQL Syntax Error (%d:%d) Fragment cannot be named \"on\"", parser.lookahead.Start.Line, parser.lookahead.Start.Column),
-			Source:  parser.source,
-			Start:   parser.lookahead.Start,
-			End:     parser.lookahead.End,
-		}
+		return nil, parser.tokenError(parser.lookahead, fmt.Sprintf("GraphQL Syntax Error (%d:%d) Fragment cannot be named \"on\"", parser.lookahead.Start.Line, parser.lookahead.Start.Column))
 	}
 	return parser.name()
 }
@@ -699,16 +696,19 @@ func (parser *Parser) fragmentName() (*Name, error) {
  *   - FloatValue
  *   - StringValue
  *   - BooleanValue
+ *   - NullValue
  *   - EnumValue
  *   - ListValue[?Const]
  *   - ObjectValue[?Const]
  *
  * BooleanValue : one of `true` `false`
  *
+ * NullValue : `null`
+ *
  * EnumValue : Name but not `true`, `false` or `null`
  */
 func (parser *Parser) valueLiteral(isConstant bool) (ASTNode, error) {
-	start := parser.lookahead.Start
+	start := parser.start
 	switch parser.lookahead.Type {
 	case LBRACK:
 		return parser.list(isConstant)
@@ -768,32 +768,22 @@ func (parser *Parser) valueLiteral(isConstant bool) (ASTNode, error) {
 				Value: false,
 				LOC:   parser.loc(start),
 			}, nil
-		}
-		if token.Val == "true" || token.Val == "false" || token.Val == "null" {
-			return nil, &GraphQLError{
-				Message: fmt.Sprintf("GraphQL Syntax Error (%d:%d) Unexpected %s", token.Start.Line, token.Start.Column, token.String()),
-				Source:  parser.source,
-				Start:   token.Start,
-				End:     token.End,
-			}
-		} else {
-			return &Enum{
-				Value: token.Val,
-				LOC:   parser.loc(start),
+		} else if token.Val == "null" {
+			return &Null{
+				LOC: parser.loc(start),
 			}, nil
 		}
+		return &Enum{
+			Value: token.Val,
+			LOC:   parser.loc(start),
+		}, nil
 	case DOLLAR:
 		if !isConstant {
 			return parser.variable()
 		}
 	}
 
-	return nil, &GraphQLError{
-		Message: fmt.Sprintf("GraphQL Syntax Error (%d:%d) Unexpected %s", parser.lookahead.Start.Line, parser.lookahead.Start.Column, parser.lookahead.String()),
-		Source:  parser.source,
-		Start:   parser.lookahead.Start,
-		End:     parser.lookahead.End,
-	}
+	return nil, parser.tokenError(parser.lookahead, fmt.Sprintf("GraphQL Syntax Error (%d:%d) Unexpected %s", parser.lookahead.Start.Line, parser.lookahead.Start.Column, parser.lookahead.String()))
 
 }
 
@@ -811,7 +801,7 @@ func (parser *Parser) valueValue() (ASTNode, error) {
  *   - [ Value[?Const]+ ]
  */
 func (parser *Parser) list(isConstant bool) (*List, error) {
-	start := parser.lookahead.Start
+	start := parser.start
 	node := &List{}
 	err := parser.match(LBRACK)
 	if err != nil {
@@ -846,7 +836,7 @@ func (parser *Parser) list(isConstant bool) (*List, error) {
  *   - { ObjectField[?Const]+ }
  */
 func (parser *Parser) object(isConstant bool) (*Object, error) {
-	start := parser.lookahead.Start
+	start := parser.start
 	err := parser.match(LBRACE)
 	if err != nil {
 		return nil, err
@@ -877,7 +867,7 @@ func (parser *Parser) object(isConstant bool) (*Object, error) {
  */
 func (parser *Parser) objectField(isConstant bool) (*ObjectField, error) {
 	var err error
-	start := parser.lookahead.Start
+	start := parser.start
 	node := &ObjectField{}
 	node.Name, err = parser.name()
 	if err != nil {
@@ -920,7 +910,7 @@ func (parser *Parser) directives() ([]*Directive, map[string]*Directive, error)
  * Directive : @ Name Arguments?
  */
 func (parser *Parser) directive() (*Directive, error) {
-	start := parser.lookahead.Start
+	start := parser.start
 	node := &Directive{}
 	err := parser.match(AT)
 	if err != nil {
@@ -940,6 +930,33 @@ func (parser *Parser) directive() (*Directive, error) {
 	return node, nil
 }
 
+// DEFAULT_DEPRECATION_REASON is the reason of a @deprecated directive without
+// a reason argument.
+const DEFAULT_DEPRECATION_REASON = "No longer supported"
+
+// deprecation returns whether the @deprecated directive is present and the
+// reason given for the deprecation.
+func (parser *Parser) deprecation(directiveIndex map[string]*Directive) (bool, string, error) {
+	directive, ok := directiveIndex["deprecated"]
+	if !ok {
+		return false, "", nil
+	}
+	argument, ok := directive.ArgumentIndex["reason"]
+	if !ok {
+		return true, DEFAULT_DEPRECATION_REASON, nil
+	}
+	reason, ok := argument.Value.(*String)
+	if !ok {
+		return false, "", &GraphQLError{
+			Message: fmt.Sprintf("GraphQL Syntax Error (%d:%d) Expected deprecation reason to be a string", argument.LOC.Start.Line, argument.LOC.Start.Column),
+			Source:  parser.source,
+			Start:   argument.LOC.Start,
+			End:     argument.LOC.End,
+		}
+	}
+	return true, reason.Value, nil
+}
+
 /**
  * Type :
  *   - NamedType
@@ -949,7 +966,7 @@ func (parser *Parser) directive() (*Directive, error) {
 func (parser *Parser) type_() (ASTNode, error) {
 	var node ASTNode
 	var err error
-	start := parser.lookahead.Start
+	start := parser.start
 	if parser.lookahead.Type == LBRACK {
 		err = parser.match(LBRACK)
 		if err != nil {
@@ -991,7 +1008,7 @@ func (parser *Parser) type_() (ASTNode, error) {
  */
 func (parser *Parser) namedType() (*NamedType, error) {
 	var err error
-	start := parser.lookahead.Start
+	start := parser.start
 	node := &NamedType{}
 	node.Name, err = parser.name()
 	if err != nil {
@@ -1025,21 +1042,16 @@ func (parser *Parser) typeDefinition(description string) (ASTNode, error) {
 	case "input":
 		return parser.inputObjectTypeDefinition(description)
 	default:
-		return nil, &GraphQLError{
-			Message: fmt.Sprintf("GraphQL Syntax Error (%d:%d) Unexpected %s", parser.lookahead.Start.Line, parser.lookahead.Start.Column, parser.lookahead.String()),
-			Source:  parser.source,
-			Start:   parser.lookahead.Start,
-			End:     parser.lookahead.End,
-		}
+		return nil, parser.tokenError(parser.lookahead, fmt.Sprintf("GraphQL Syntax Error (%d:%d) Unexpected %s", parser.lookahead.Start.Line, parser.lookahead.Start.Column, parser.lookahead.String()))
 	}
 }
 
 /**
- * ObjectTypeDefinition :  type Name ImplementsInterfaces? { FieldDefinition+ }
+ * ObjectTypeDefinition :  type Name ImplementsInterfaces? Directives? { FieldDefinition+ }
  */
 func (parser *Parser) objectTypeDefinition(description string) (*ObjectTypeDefinition, error) {
 	var err error
-	start := parser.lookahead.Start
+	start := parser.start
 	node := &ObjectTypeDefinition{
 		Description: description,
 	}
@@ -1058,6 +1070,12 @@ func (parser *Parser) objectTypeDefinition(description string) (*ObjectTypeDefin
 			return nil, err
 		}
 	}
+	if parser.lookahead.Type == AT {
+		node.Directives, node.DirectiveIndex, err = parser.directives()
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	err = parser.match(LBRACE)
 	if err != nil {
@@ -1100,7 +1118,7 @@ func (parser *Parser) implementsInterfaces() ([]*NamedType, error) {
 			return nil, err
 		}
 		types = append(types, namedType)
-		if parser.lookahead.Type == LBRACE {
+		if parser.lookahead.Type == LBRACE || parser.lookahead.Type == AT {
 			break
 		}
 	}
@@ -1108,12 +1126,12 @@ func (parser *Parser) implementsInterfaces() ([]*NamedType, error) {
 }
 
 /**
- * FieldDefinition : Description* Name ArgumentsDefinition? : Type
+ * FieldDefinition : Description* Name ArgumentsDefinition? : Type Directives?
  */
 func (parser *Parser) fieldDefinition() (*FieldDefinition, error) {
 	var err error
 	node := &FieldDefinition{}
-	start := parser.lookahead.Start
+	start := parser.start
 	node.Description, err = parser.description()
 	if err != nil {
 		return nil, err
@@ -1136,6 +1154,16 @@ func (parser *Parser) fieldDefinition() (*FieldDefinition, error) {
 	if err != nil {
 		return nil, err
 	}
+	if parser.lookahead.Type == AT {
+		node.Directives, node.DirectiveIndex, err = parser.directives()
+		if err != nil {
+			return nil, err
+		}
+		node.IsDeprecated, node.DeprecationReason, err = parser.deprecation(node.DirectiveIndex)
+		if err != nil {
+			return nil, err
+		}
+	}
 	node.LOC = parser.loc(start)
 	return node, nil
 }
@@ -1170,12 +1198,12 @@ func (parser *Parser) argumentDefs(node *FieldDefinition) ([]*InputValueDefiniti
 }
 
 /**
- * InputValueDefinition : Description* Name : Type DefaultValue?
+ * InputValueDefinition : Description* Name : Type DefaultValue? Directives?
  */
 func (parser *Parser) inputValueDef() (*InputValueDefinition, error) {
 	var err error
 	node := &InputValueDefinition{}
-	start := parser.lookahead.Start
+	start := parser.start
 
 	node.Description, err = parser.description()
 	if err != nil {
@@ -1203,19 +1231,29 @@ func (parser *Parser) inputValueDef() (*InputValueDefinition, error) {
 			return nil, err
 		}
 	}
+	if parser.lookahead.Type == AT {
+		node.Directives, node.DirectiveIndex, err = parser.directives()
+		if err != nil {
+			return nil, err
+		}
+		node.IsDeprecated, node.DeprecationReason, err = parser.deprecation(node.DirectiveIndex)
+		if err != nil {
+			return nil, err
+		}
+	}
 	node.LOC = parser.loc(start)
 	return node, nil
 }
 
 /**
- * InterfaceTypeDefinition : interface Name { FieldDefinition+ }
+ * InterfaceTypeDefinition : interface Name Directives? { FieldDefinition+ }
  */
 func (parser *Parser) interfaceTypeDefinition(description string) (*InterfaceTypeDefinition, error) {
 	var err error
 	node := &InterfaceTypeDefinition{
 		Description: description,
 	}
-	start := parser.lookahead.Start
+	start := parser.start
 	err = parser.matchName("interface")
 	if err != nil {
 		return nil, err
@@ -1224,6 +1262,12 @@ func (parser *Parser) interfaceTypeDefinition(description string) (*InterfaceTyp
 	if err != nil {
 		return nil, err
 	}
+	if parser.lookahead.Type == AT {
+		node.Directives, node.DirectiveIndex, err = parser.directives()
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	err = parser.match(LBRACE)
 	if err != nil {
@@ -1246,14 +1290,14 @@ func (parser *Parser) interfaceTypeDefinition(description string) (*InterfaceTyp
 }
 
 /**
- * UnionTypeDefinition : union Name = UnionMembers
+ * UnionTypeDefinition : union Name Directives? = UnionMembers
  */
 func (parser *Parser) unionTypeDefinition(description string) (*UnionTypeDefinition, error) {
 	var err error
 	node := &UnionTypeDefinition{
 		Description: description,
 	}
-	start := parser.lookahead.Start
+	start := parser.start
 	err = parser.matchName("union")
 	if err != nil {
 		return nil, err
@@ -1262,6 +1306,12 @@ func (parser *Parser) unionTypeDefinition(description string) (*UnionTypeDefinit
 	if err != nil {
 		return nil, err
 	}
+	if parser.lookahead.Type == AT {
+		node.Directives, node.DirectiveIndex, err = parser.directives()
+		if err != nil {
+			return nil, err
+		}
+	}
 	err = parser.match(EQ)
 	if err != nil {
 		return nil, err
@@ -1300,13 +1350,13 @@ func (parser *Parser) unionMembers() ([]*NamedType, error) {
 }
 
 /**
- * ScalarTypeDefinition : scalar Name
+ * ScalarTypeDefinition : scalar Name Directives?
  */
 func (parser *Parser) scalarTypeDefinition(description string) (*ScalarTypeDefinition, error) {
 	node := &ScalarTypeDefinition{
 		Description: description,
 	}
-	start := parser.lookahead.Start
+	start := parser.start
 	err := parser.matchName("scalar")
 	if err != nil {
 		return nil, err
@@ -1315,18 +1365,24 @@ func (parser *Parser) scalarTypeDefinition(description string) (*ScalarTypeDefin
 	if err != nil {
 		return nil, err
 	}
+	if parser.lookahead.Type == AT {
+		node.Directives, node.DirectiveIndex, err = parser.directives()
+		if err != nil {
+			return nil, err
+		}
+	}
 	node.LOC = parser.loc(start)
 	return node, nil
 }
 
 /**
- * EnumTypeDefinition : enum Name { EnumValueDefinition+ }
+ * EnumTypeDefinition : enum Name Directives? { EnumValueDefinition+ }
  */
 func (parser *Parser) enumTypeDefinition(description string) (*EnumTypeDefinition, error) {
 	node := &EnumTypeDefinition{
 		Description: description,
 	}
-	start := parser.lookahead.Start
+	start := parser.start
 	err := parser.matchName("enum")
 	if err != nil {
 		return nil, err
@@ -1335,6 +1391,12 @@ func (parser *Parser) enumTypeDefinition(description string) (*EnumTypeDefinitio
 	if err != nil {
 		return nil, err
 	}
+	if parser.lookahead.Type == AT {
+		node.Directives, node.DirectiveIndex, err = parser.directives()
+		if err != nil {
+			return nil, err
+		}
+	}
 	err = parser.match(LBRACE)
 	if err != nil {
 		return nil, err
@@ -1358,43 +1420,48 @@ func (parser *Parser) enumTypeDefinition(description string) (*EnumTypeDefinitio
 }
 
 /**
- * EnumValueDefinition : EnumValue
+ * EnumValueDefinition : Description* EnumValue Directives?
  *
  * EnumValue : Name
  */
 func (parser *Parser) enumValueDefinition() (*EnumValueDefinition, error) {
 	var err error
 	node := &EnumValueDefinition{}
-	start := parser.lookahead.Start
+	start := parser.start
 	node.Description, err = parser.description()
 	if err != nil {
 		return nil, err
 	}
 	if parser.lookahead.Val == "true" || parser.lookahead.Val == "false" || parser.lookahead.Val == "null" {
-		return nil, &GraphQLError{
-			Message: fmt.Sprintf("GraphQL Syntax Error (%d:%d) Enum value cannot be %q", parser.lookahead.Start.Line, parser.lookahead.Start.Column, parser.lookahead.Val),
-			Source:  parser.source,
-			Start:   parser.lookahead.Start,
-			End:     parser.lookahead.End,
-		}
+		return nil, parser.tokenError(parser.lookahead, fmt.Sprintf("GraphQL Syntax Error (%d:%d) Enum value cannot be %q", parser.lookahead.Start.Line, parser.lookahead.Start.Column, parser.lookahead.Val))
 	}
 	node.Name, err = parser.name()
 	if err != nil {
 		return nil, err
 	}
+	if parser.lookahead.Type == AT {
+		node.Directives, node.DirectiveIndex, err = parser.directives()
+		if err != nil {
+			return nil, err
+		}
+		node.IsDeprecated, node.DeprecationReason, err = parser.deprecation(node.DirectiveIndex)
+		if err != nil {
+			return nil, err
+		}
+	}
 	node.LOC = parser.loc(start)
 	return node, nil
 }
 
 /**
- * InputObjectTypeDefinition : input Name { InputValueDefinition+ }
+ * InputObjectTypeDefinition : input Name Directives? { InputValueDefinition+ }
  */
 func (parser *Parser) inputObjectTypeDefinition(description string) (*InputObjectTypeDefinition, error) {
 	node := &InputObjectTypeDefinition{
 		Description: description,
 	}
 	fieldIndex := map[string]*InputValueDefinition{}
-	start := parser.lookahead.Start
+	start := parser.start
 	err := parser.matchName("input")
 	if err != nil {
 		return nil, err
@@ -1403,6 +1470,12 @@ func (parser *Parser) inputObjectTypeDefinition(description string) (*InputObjec
 	if err != nil {
 		return nil, err
 	}
+	if parser.lookahead.Type == AT {
+		node.Directives, node.DirectiveIndex, err = parser.directives()
+		if err != nil {
+			return nil, err
+		}
+	}
 	err = parser.match(LBRACE)
 	if err != nil {
 		return nil, err
@@ -1427,21 +1500,203 @@ func (parser *Parser) inputObjectTypeDefinition(description string) (*InputObjec
 }
 
 /**
- * TypeExtensionDefinition : extend ObjectTypeDefinition
+ * SchemaDefinition : Description* schema Directives? { OperationTypeDefinition+ }
  */
-func (parser *Parser) typeExtensionDefinition(description string) (*TypeExtensionDefinition, error) {
-	node := &TypeExtensionDefinition{
+func (parser *Parser) schemaDefinition(description string) (*SchemaDefinition, error) {
+	node := &SchemaDefinition{
 		Description: description,
 	}
-	start := parser.lookahead.Start
+	start := parser.start
+	err := parser.matchName("schema")
+	if err != nil {
+		return nil, err
+	}
+	if parser.lookahead.Type == AT {
+		node.Directives, node.DirectiveIndex, err = parser.directives()
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = parser.match(LBRACE)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		operationType, err := parser.operationTypeDefinition()
+		if err != nil {
+			return nil, err
+		}
+		node.OperationTypes = append(node.OperationTypes, operationType)
+		if parser.lookahead.Type == RBRACE {
+			break
+		}
+	}
+	err = parser.match(RBRACE)
+	if err != nil {
+		return nil, err
+	}
+	node.LOC = parser.loc(start)
+	return node, nil
+}
+
+/**
+ * OperationTypeDefinition : OperationType : NamedType
+ *
+ * OperationType : one of query mutation subscription
+ */
+func (parser *Parser) operationTypeDefinition() (*OperationTypeDefinition, error) {
+	start := parser.start
+	node := &OperationTypeDefinition{}
+	switch parser.lookahead.Val {
+	case "query", "mutation", "subscription":
+		node.Operation = parser.lookahead.Val
+	default:
+		return nil, parser.tokenError(parser.lookahead, fmt.Sprintf("GraphQL Syntax Error (%d:%d) Unexpected %s", parser.lookahead.Start.Line, parser.lookahead.Start.Column, parser.lookahead.String()))
+	}
+	err := parser.matchName(node.Operation)
+	if err != nil {
+		return nil, err
+	}
+	err = parser.match(COLON)
+	if err != nil {
+		return nil, err
+	}
+	node.Type, err = parser.namedType()
+	if err != nil {
+		return nil, err
+	}
+	node.LOC = parser.loc(start)
+	return node, nil
+}
+
+/**
+ * TypeExtensionDefinition : extend TypeDefinition
+ *
+ * SchemaExtensionDefinition : extend SchemaDefinition
+ */
+func (parser *Parser) extensionDefinition(description string) (ASTNode, error) {
+	start := parser.start
 	err := parser.matchName("extend")
 	if err != nil {
 		return nil, err
 	}
-	node.Definition, err = parser.objectTypeDefinition(description)
+	if parser.lookahead.Type == NAME && parser.lookahead.Val == "schema" {
+		node := &SchemaExtensionDefinition{
+			Description: description,
+		}
+		node.Definition, err = parser.schemaDefinition("")
+		if err != nil {
+			return nil, err
+		}
+		node.LOC = parser.loc(start)
+		return node, nil
+	}
+	node := &TypeExtensionDefinition{
+		Description: description,
+	}
+	node.Definition, err = parser.typeDefinition("")
+	if err != nil {
+		return nil, err
+	}
+	node.LOC = parser.loc(start)
+	return node, nil
+}
+
+func typeDefinitionName(definition ASTNode) *Name {
+	switch definition := definition.(type) {
+	case *ObjectTypeDefinition:
+		return definition.Name
+	case *InterfaceTypeDefinition:
+		return definition.Name
+	case *UnionTypeDefinition:
+		return definition.Name
+	case *ScalarTypeDefinition:
+		return definition.Name
+	case *EnumTypeDefinition:
+		return definition.Name
+	case *InputObjectTypeDefinition:
+		return definition.Name
+	}
+	return nil
+}
+
+/**
+ * DirectiveDefinition : Description* directive @ Name ArgumentsDefinition? on DirectiveLocations
+ */
+func (parser *Parser) directiveDefinition(description string) (*DirectiveDefinition, error) {
+	node := &DirectiveDefinition{
+		Description: description,
+	}
+	start := parser.start
+	err := parser.matchName("directive")
+	if err != nil {
+		return nil, err
+	}
+	err = parser.match(AT)
+	if err != nil {
+		return nil, err
+	}
+	node.Name, err = parser.name()
+	if err != nil {
+		return nil, err
+	}
+	if parser.lookahead.Type == LPAREN {
+		node.Arguments, node.ArgumentIndex, err = parser.argumentDefs(nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = parser.matchName("on")
+	if err != nil {
+		return nil, err
+	}
+	node.Locations, err = parser.directiveLocations()
 	if err != nil {
 		return nil, err
 	}
 	node.LOC = parser.loc(start)
 	return node, nil
 }
+
+/**
+ * DirectiveLocations :
+ *   - `|`? DirectiveLocation
+ *   - DirectiveLocations | DirectiveLocation
+ */
+func (parser *Parser) directiveLocations() ([]*Name, error) {
+	if parser.lookahead.Type == PIPE {
+		err := parser.match(PIPE)
+		if err != nil {
+			return nil, err
+		}
+	}
+	locations := []*Name{}
+	for {
+		token := parser.lookahead
+		location, err := parser.name()
+		if err != nil {
+			return nil, err
+		}
+		if !isDirectiveLocation(location.Value) {
+			return nil, parser.tokenError(token, fmt.Sprintf("GraphQL Syntax Error (%d:%d) Unexpected directive location %q", token.Start.Line, token.Start.Column, location.Value))
+		}
+		locations = append(locations, location)
+		if parser.lookahead.Type != PIPE {
+			break
+		}
+		err = parser.match(PIPE)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return locations, nil
+}
+
+func isDirectiveLocation(location string) bool {
+	for _, directiveLocation := range DIRECTIVE_LOCATIONS {
+		if directiveLocation == location {
+			return true
+		}
+	}
+	return false
+}
@@ -3,7 +3,6 @@ package language
 import (
 	//"encoding/json"
 	"fmt"
-	. "github.com/smartystreets/goconvey/convey"
 	"strings"
 	"testing"
 	"unicode/utf8"
@@ -346,11 +345,21 @@ fragment MissingOn Type`,
 			So(err.Error(), ShouldEqual, "GraphQL Syntax Error (1:9) Expected Name, found }\n\n1|{ ...on }\n          ^")
 		})
 
-		Convey("does not allow null as value", func() {
-			_, err := parser.Parse(&ParseParams{
-				Source: `{ fieldWithNullableStringInput(input: null) }`,
+		Convey("parses null as a value", func() {
+			result, err := parser.Parse(&ParseParams{
+				Source: `{ fieldWithNullableStringInput(input: null, list: [null], object: { a: null }) }`,
+			})
+			So(err, ShouldEqual, nil)
+			field := result.Definitions[0].(*OperationDefinition).SelectionSet.Selections[0].(*Field)
+			So(field.ArgumentIndex["input"].Value, ShouldHaveSameTypeAs, &Null{})
+			So(field.ArgumentIndex["list"].Value.(*List).Values[0], ShouldHaveSameTypeAs, &Null{})
+			So(field.ArgumentIndex["object"].Value.(*Object).FieldIndex["a"].Value, ShouldHaveSameTypeAs, &Null{})
+			So(field.ArgumentIndex["input"].Value.(*Null).LOC.Start.Column, ShouldEqual, 39)
+
+			_, err = parser.Parse(&ParseParams{
+				Source: `query Foo($x: String = null) { field }`,
 			})
-			So(err.Error(), ShouldEqual, "GraphQL Syntax Error (1:39) Unexpected Name \"null\"\n\n1|{ fieldWithNullableStringInput(input: null) }\n                                        ^^^^")
+			So(err, ShouldEqual, nil)
 		})
 
 		// @TODO
@@ -446,6 +455,16 @@ fragment MissingOn Type`,
 			So(err, ShouldEqual, nil)
 		})
 
+		Convey("shares the positions of a token between the locations of the nodes it starts or ends", func() {
+			result, err = parser.Parse(&ParseParams{
+				Source: `{ field }`,
+			})
+			So(err, ShouldEqual, nil)
+			field := result.Definitions[0].(*OperationDefinition).SelectionSet.Selections[0].(*Field)
+			So(field.LOC.Start, ShouldPointTo, field.Name.LOC.Start)
+			So(field.LOC.End, ShouldPointTo, field.Name.LOC.End)
+		})
+
 		Convey("parse creates ast", func() {
 			result, err = parser.Parse(&ParseParams{
 				Source: `{
@@ -1367,6 +1386,117 @@ input Hello {
 			})
 			So(err, ShouldNotEqual, nil)
 		})
+
+		Convey("deprecated fields, arguments, input fields and enum values", func() {
+			result, err = parser.Parse(&ParseParams{
+				Source: `
+type Hello {
+  world(old: Int @deprecated(reason: "Use new"), new: Int): String @deprecated
+  planet: String @deprecated(reason: "Use world")
+}
+input World {
+  name: String = "earth" @deprecated
+}
+enum Color {
+  RED @deprecated(reason: "Too loud")
+  GREEN
+}`,
+			})
+			So(err, ShouldEqual, nil)
+			hello := result.ObjectTypeIndex["Hello"]
+			So(hello.FieldIndex["world"].IsDeprecated, ShouldEqual, true)
+			So(hello.FieldIndex["world"].DeprecationReason, ShouldEqual, DEFAULT_DEPRECATION_REASON)
+			So(hello.FieldIndex["world"].DirectiveIndex["deprecated"], ShouldNotEqual, nil)
+			So(hello.FieldIndex["world"].ArgumentIndex["old"].IsDeprecated, ShouldEqual, true)
+			So(hello.FieldIndex["world"].ArgumentIndex["old"].DeprecationReason, ShouldEqual, "Use new")
+			So(hello.FieldIndex["world"].ArgumentIndex["new"].IsDeprecated, ShouldEqual, false)
+			So(hello.FieldIndex["planet"].DeprecationReason, ShouldEqual, "Use world")
+			So(result.InputObjectTypeIndex["World"].FieldIndex["name"].IsDeprecated, ShouldEqual, true)
+			color := result.EnumTypeIndex["Color"]
+			So(color.Values[0].IsDeprecated, ShouldEqual, true)
+			So(color.Values[0].DeprecationReason, ShouldEqual, "Too loud")
+			So(color.Values[1].IsDeprecated, ShouldEqual, false)
+		})
+
+		Convey("deprecation reason must be a string", func() {
+			result, err = parser.Parse(&ParseParams{
+				Source: `
+type Hello {
+  world: String @deprecated(reason: 42)
+}`,
+			})
+			So(err.Error(), ShouldContainSubstring, "GraphQL Syntax Error (3:29) Expected deprecation reason to be a string")
+		})
+
+		Convey("directive definitions and directives on type definitions", func() {
+			result, err = parser.Parse(&ParseParams{
+				Source: `
+## Restricts access
+directive @auth(role: String = "admin") on
+  | FIELD_DEFINITION
+  | OBJECT
+type Hello @auth {
+  world: String @auth(role: "user") @upper
+}
+scalar Date @auth
+enum Color @auth { RED }`,
+			})
+			So(err, ShouldEqual, nil)
+			directive := result.DirectiveIndex["auth"]
+			So(directive.Name.Value, ShouldEqual, "auth")
+			So(directive.Description, ShouldEqual, "Restricts access\n")
+			So(directive.ArgumentIndex["role"].DefaultValue.(*String).Value, ShouldEqual, "admin")
+			So(len(directive.Locations), ShouldEqual, 2)
+			So(directive.Locations[0].Value, ShouldEqual, "FIELD_DEFINITION")
+			So(directive.Locations[1].Value, ShouldEqual, "OBJECT")
+			hello := result.ObjectTypeIndex["Hello"]
+			So(hello.DirectiveIndex["auth"], ShouldNotEqual, nil)
+			world := hello.FieldIndex["world"]
+			So(len(world.Directives), ShouldEqual, 2)
+			So(world.DirectiveIndex["auth"].ArgumentIndex["role"].Value.(*String).Value, ShouldEqual, "user")
+			So(world.DirectiveIndex["upper"], ShouldNotEqual, nil)
+			So(result.ScalarTypeIndex["Date"].DirectiveIndex["auth"], ShouldNotEqual, nil)
+			So(result.EnumTypeIndex["Color"].DirectiveIndex["auth"], ShouldNotEqual, nil)
+		})
+
+		Convey("schema definition and extension", func() {
+			result, err = parser.Parse(&ParseParams{
+				Source: `
+schema @live {
+  query: Root
+  mutation: Change
+}
+extend schema {
+  subscription: Updates
+}`,
+			})
+			So(err, ShouldEqual, nil)
+			schema := result.SchemaDefinition
+			So(schema, ShouldEqual, result.Definitions[0])
+			So(schema.DirectiveIndex["live"], ShouldNotEqual, nil)
+			So(len(schema.OperationTypes), ShouldEqual, 2)
+			So(schema.OperationTypes[0].Operation, ShouldEqual, "query")
+			So(schema.OperationTypes[0].Type.Name.Value, ShouldEqual, "Root")
+			So(schema.OperationTypes[1].Operation, ShouldEqual, "mutation")
+			So(schema.OperationTypes[1].Type.Name.Value, ShouldEqual, "Change")
+			extension := result.Definitions[1].(*SchemaExtensionDefinition)
+			So(extension.Definition.OperationTypes[0].Operation, ShouldEqual, "subscription")
+			So(extension.Definition.OperationTypes[0].Type.Name.Value, ShouldEqual, "Updates")
+		})
+
+		Convey("schema definitions only name operation types", func() {
+			result, err = parser.Parse(&ParseParams{
+				Source: `schema { query: Root, fragment: Frag }`,
+			})
+			So(err.Error(), ShouldContainSubstring, "GraphQL Syntax Error (1:23) Unexpected Name \"fragment\"")
+		})
+
+		Convey("directive definitions must use known locations", func() {
+			result, err = parser.Parse(&ParseParams{
+				Source: `directive @auth on FIELD | NOWHERE`,
+			})
+			So(err.Error(), ShouldContainSubstring, "GraphQL Syntax Error (1:28) Unexpected directive location \"NOWHERE\"")
+		})
 	})
 
 }
@@ -0,0 +1,351 @@
+package language
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Print converts an AST node back into GraphQL source text. Parsing the output
+// of Print yields a document that is structurally equal to the one printed,
+// although source locations and insignificant formatting are not preserved.
+func Print(node ASTNode) string {
+	switch node := node.(type) {
+	case *Document:
+		definitions := []string{}
+		for _, definition := range node.Definitions {
+			definitions = append(definitions, Print(definition))
+		}
+		return strings.Join(definitions, "\n\n") + "\n"
+
+	case *OperationDefinition:
+		if node.Operation == "query" && node.Name == nil && len(node.VariableDefinitions) == 0 && len(node.Directives) == 0 {
+			return Print(node.SelectionSet)
+		}
+		output := node.Operation
+		if node.Name != nil {
+			output += " " + node.Name.Value
+		}
+		if len(node.VariableDefinitions) > 0 {
+			variableDefinitions := []string{}
+			for _, variableDefinition := range node.VariableDefinitions {
+				variableDefinitions = append(variableDefinitions, Print(variableDefinition))
+			}
+			output += "(" + strings.Join(variableDefinitions, ", ") + ")"
+		}
+		return output + printDirectives(node.Directives) + printSelectionSet(node.SelectionSet)
+
+	case *VariableDefinition:
+		output := Print(node.Variable) + ": " + Print(node.Type)
+		if defaultValue, ok := node.DefaultValue.(ASTNode); ok && defaultValue != nil {
+			output += " = " + Print(defaultValue)
+		}
+		return output
+
+	case *Variable:
+		return "$" + node.Name.Value
+
+	case *SelectionSet:
+		if node == nil || len(node.Selections) == 0 {
+			return ""
+		}
+		selections := []string{}
+		for _, selection := range node.Selections {
+			selections = append(selections, Print(selection))
+		}
+		return printBlock(selections)
+
+	case *Field:
+		output := ""
+		if node.Alias != nil {
+			output += node.Alias.Value + ": "
+		}
+		return output + node.Name.Value + printArguments(node.Arguments) + printDirectives(node.Directives) + printSelectionSet(node.SelectionSet)
+
+	case *Argument:
+		return node.Name.Value + ": " + Print(node.Value)
+
+	case *FragmentSpread:
+		return "..." + node.Name.Value + printDirectives(node.Directives)
+
+	case *InlineFragment:
+		output := "..."
+		if node.TypeCondition != nil {
+			output += " on " + node.TypeCondition.Name.Value
+		}
+		return output + printDirectives(node.Directives) + printSelectionSet(node.SelectionSet)
+
+	case *FragmentDefinition:
+		return "fragment " + node.Name.Value + " on " + node.TypeCondition.Name.Value + printDirectives(node.Directives) + printSelectionSet(node.SelectionSet)
+
+	case *Int:
+		return strconv.FormatInt(int64(node.Value), 10)
+
+	case *Float:
+		output := strconv.FormatFloat(float64(node.Value), 'g', -1, 32)
+		if !strings.ContainsAny(output, ".e") {
+			// Keep the value a float when it is parsed again
+			output += ".0"
+		}
+		return output
+
+	case *String:
+		return printString(node.Value)
+
+	case *Boolean:
+		return strconv.FormatBool(node.Value)
+
+	case *Enum:
+		return node.Value
+
+	case *Null:
+		return "null"
+
+	case *Literal:
+		if value, ok := node.Value.(string); ok && node.Type == "String" {
+			return printString(value)
+		}
+		return fmt.Sprintf("%v", node.Value)
+
+	case *List:
+		values := []string{}
+		for _, value := range node.Values {
+			values = append(values, Print(value))
+		}
+		return "[" + strings.Join(values, ", ") + "]"
+
+	case *Object:
+		fields := []string{}
+		for _, field := range node.Fields {
+			fields = append(fields, Print(field))
+		}
+		return "{" + strings.Join(fields, ", ") + "}"
+
+	case *ObjectField:
+		return node.Name.Value + ": " + Print(node.Value)
+
+	case *Directive:
+		return "@" + node.Name.Value + printArguments(node.Arguments)
+
+	case *Name:
+		return node.Value
+
+	case *NamedType:
+		return node.Name.Value
+
+	case *ListType:
+		return "[" + Print(node.Type) + "]"
+
+	case *NonNullType:
+		return Print(node.Type) + "!"
+
+	case *ScalarTypeDefinition:
+		return printDescription(node.Description) + "scalar " + node.Name.Value + printDirectives(node.Directives)
+
+	case *ObjectTypeDefinition:
+		output := printDescription(node.Description) + "type " + node.Name.Value
+		if len(node.Interfaces) > 0 {
+			interfaces := []string{}
+			for _, namedType := range node.Interfaces {
+				interfaces = append(interfaces, namedType.Name.Value)
+			}
+			output += " implements " + strings.Join(interfaces, ", ")
+		}
+		return output + printDirectives(node.Directives) + printFieldDefinitions(node.Fields)
+
+	case *FieldDefinition:
+		output := printDescription(node.Description) + node.Name.Value + printInputValueDefinitions(node.Arguments) + ": " + Print(node.Type)
+		return output + printDefinitionDirectives(node.Directives, node.DirectiveIndex, node.IsDeprecated, node.DeprecationReason)
+
+	case *InputValueDefinition:
+		output := printDescription(node.Description) + node.Name.Value + ": " + Print(node.Type)
+		if node.DefaultValue != nil {
+			output += " = " + Print(node.DefaultValue)
+		}
+		return output + printDefinitionDirectives(node.Directives, node.DirectiveIndex, node.IsDeprecated, node.DeprecationReason)
+
+	case *InterfaceTypeDefinition:
+		return printDescription(node.Description) + "interface " + node.Name.Value + printDirectives(node.Directives) + printFieldDefinitions(node.Fields)
+
+	case *UnionTypeDefinition:
+		types := []string{}
+		for _, namedType := range node.Types {
+			types = append(types, namedType.Name.Value)
+		}
+		return printDescription(node.Description) + "union " + node.Name.Value + printDirectives(node.Directives) + " = " + strings.Join(types, " | ")
+
+	case *EnumTypeDefinition:
+		values := []string{}
+		for _, value := range node.Values {
+			values = append(values, Print(value))
+		}
+		return printDescription(node.Description) + "enum " + node.Name.Value + printDirectives(node.Directives) + " " + printBlock(values)
+
+	case *EnumValueDefinition:
+		return printDescription(node.Description) + node.Name.Value + printDefinitionDirectives(node.Directives, node.DirectiveIndex, node.IsDeprecated, node.DeprecationReason)
+
+	case *InputObjectTypeDefinition:
+		fields := []string{}
+		for _, field := range node.Fields {
+			fields = append(fields, Print(field))
+		}
+		return printDescription(node.Description) + "input " + node.Name.Value + printDirectives(node.Directives) + " " + printBlock(fields)
+
+	case *DirectiveDefinition:
+		locations := []string{}
+		for _, location := range node.Locations {
+			locations = append(locations, location.Value)
+		}
+		return printDescription(node.Description) + "directive @" + node.Name.Value + printInputValueDefinitions(node.Arguments) + " on " + strings.Join(locations, " | ")
+
+	case *SchemaDefinition:
+		operationTypes := []string{}
+		for _, operationType := range node.OperationTypes {
+			operationTypes = append(operationTypes, Print(operationType))
+		}
+		return printDescription(node.Description) + "schema" + printDirectives(node.Directives) + " " + printBlock(operationTypes)
+
+	case *OperationTypeDefinition:
+		return node.Operation + ": " + Print(node.Type)
+
+	case *TypeExtensionDefinition:
+		return printDescription(node.Description) + "extend " + Print(node.Definition)
+
+	case *SchemaExtensionDefinition:
+		return printDescription(node.Description) + "extend " + Print(node.Definition)
+	}
+	panic(fmt.Sprintf("Unexpected AST node type %T", node))
+}
+
+func printArguments(arguments []*Argument) string {
+	if len(arguments) == 0 {
+		return ""
+	}
+	values := []string{}
+	for _, argument := range arguments {
+		values = append(values, Print(argument))
+	}
+	return "(" + strings.Join(values, ", ") + ")"
+}
+
+// printSelectionSet prints the selection set following a field, fragment or
+// operation, which is left out when it is empty.
+func printSelectionSet(selectionSet *SelectionSet) string {
+	if selectionSet == nil || len(selectionSet.Selections) == 0 {
+		return ""
+	}
+	return " " + Print(selectionSet)
+}
+
+func printDirectives(directives []*Directive) string {
+	output := ""
+	for _, directive := range directives {
+		output += " " + Print(directive)
+	}
+	return output
+}
+
+func printFieldDefinitions(fieldDefinitions []*FieldDefinition) string {
+	fields := []string{}
+	for _, field := range fieldDefinitions {
+		fields = append(fields, Print(field))
+	}
+	return " " + printBlock(fields)
+}
+
+// printInputValueDefinitions prints the arguments of a field definition on a
+// single line unless one of them has a description, in which case every
+// argument is printed on its own line.
+func printInputValueDefinitions(inputValueDefinitions []*InputValueDefinition) string {
+	if len(inputValueDefinitions) == 0 {
+		return ""
+	}
+	values := []string{}
+	multiline := false
+	for _, inputValueDefinition := range inputValueDefinitions {
+		value := Print(inputValueDefinition)
+		if strings.Contains(value, "\n") {
+			multiline = true
+		}
+		values = append(values, value)
+	}
+	if multiline {
+		return "(\n" + indent(strings.Join(values, "\n")) + "\n)"
+	}
+	return "(" + strings.Join(values, ", ") + ")"
+}
+
+// printDefinitionDirectives prints the directives of a definition along with
+// its deprecation, which may have been set without the @deprecated directive.
+func printDefinitionDirectives(directives []*Directive, directiveIndex map[string]*Directive, isDeprecated bool, reason string) string {
+	output := printDirectives(directives)
+	if _, ok := directiveIndex["deprecated"]; !ok {
+		output += printDeprecated(isDeprecated, reason)
+	}
+	return output
+}
+
+func printDeprecated(isDeprecated bool, reason string) string {
+	if !isDeprecated {
+		return ""
+	}
+	if reason == "" || reason == DEFAULT_DEPRECATION_REASON {
+		return " @deprecated"
+	}
+	return " @deprecated(reason: " + printString(reason) + ")"
+}
+
+// printDescription prints the description as double hashed comments, which is
+// how descriptions are written in the schema language.
+func printDescription(description string) string {
+	description = strings.TrimSpace(description)
+	if description == "" {
+		return ""
+	}
+	output := ""
+	for _, line := range strings.Split(description, "\n") {
+		output += "## " + strings.TrimSpace(line) + "\n"
+	}
+	return output
+}
+
+func printBlock(lines []string) string {
+	if len(lines) == 0 {
+		return "{}"
+	}
+	return "{\n" + indent(strings.Join(lines, "\n")) + "\n}"
+}
+
+func indent(text string) string {
+	return "  " + strings.Replace(text, "\n", "\n  ", -1)
+}
+
+func printString(value string) string {
+	output := `"`
+	for _, rn := range value {
+		switch rn {
+		case '"':
+			output += `\"`
+		case '\\':
+			output += `\\`
+		case '\b':
+			output += `\b`
+		case '\f':
+			output += `\f`
+		case '\n':
+			output += `\n`
+		case '\r':
+			output += `\r`
+		case '\t':
+			output += `\t`
+		default:
+			if rn < 0x20 || rn == utf8.RuneError {
+				output += fmt.Sprintf(`\u%04x`, rn)
+			} else {
+				output += string(rn)
+			}
+		}
+	}
+	return output + `"`
+}
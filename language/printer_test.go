@@ -0,0 +1,266 @@
+package language
+
+import (
+	"reflect"
+	"testing"
+)
+
+func parse(source string) *Document {
+	parser := &Parser{}
+	document, err := parser.Parse(&ParseParams{
+		Source: source,
+	})
+	So(err, ShouldEqual, nil)
+	return document
+}
+
+// stripLocations removes the source locations from the AST so that documents
+// parsed from differently formatted sources can be compared.
+func stripLocations(value reflect.Value, visited map[uintptr]bool) {
+	switch value.Kind() {
+	case reflect.Ptr:
+		if value.IsNil() || visited[value.Pointer()] {
+			return
+		}
+		visited[value.Pointer()] = true
+		stripLocations(value.Elem(), visited)
+	case reflect.Interface:
+		if !value.IsNil() {
+			stripLocations(value.Elem(), visited)
+		}
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			if value.Type().Field(i).Name == "LOC" {
+				value.Field(i).Set(reflect.Zero(value.Field(i).Type()))
+			} else {
+				stripLocations(value.Field(i), visited)
+			}
+		}
+	case reflect.Slice:
+		for i := 0; i < value.Len(); i++ {
+			stripLocations(value.Index(i), visited)
+		}
+	case reflect.Map:
+		for _, key := range value.MapKeys() {
+			stripLocations(value.MapIndex(key), visited)
+		}
+	}
+}
+
+func shouldRoundTrip(source string) {
+	document := parse(source)
+	reparsed := parse(Print(document))
+	stripLocations(reflect.ValueOf(document), map[uintptr]bool{})
+	stripLocations(reflect.ValueOf(reparsed), map[uintptr]bool{})
+	So(reparsed, ShouldResemble, document)
+}
+
+func TestPrinter(t *testing.T) {
+
+	Convey("Print: Prints queries", t, func() {
+
+		Convey("prints the query shorthand", func() {
+			So(Print(parse(`{ id, name }`)), ShouldEqual, "{\n  id\n  name\n}\n")
+		})
+
+		Convey("prints operations with variables and directives", func() {
+			document := parse(`
+            query Q($id: ID! = "4", $ids: [Int!]) @live {
+                user: node(id: $id) @include(if: true) {
+                    ... on User { name }
+                    ...Friends @skip(if: false)
+                    ... @include(if: $flag) { id }
+                }
+            }
+            mutation { like(story: 123) }
+            subscription S { updates }
+            fragment Friends on User { friends(first: 10) { name } }
+            `)
+			So(Print(document), ShouldEqual, `query Q($id: ID! = "4", $ids: [Int!]) @live {
+  user: node(id: $id) @include(if: true) {
+    ... on User {
+      name
+    }
+    ...Friends @skip(if: false)
+    ... @include(if: $flag) {
+      id
+    }
+  }
+}
+
+mutation {
+  like(story: 123)
+}
+
+subscription S {
+  updates
+}
+
+fragment Friends on User {
+  friends(first: 10) {
+    name
+  }
+}
+`)
+		})
+
+		Convey("leaves out empty selection sets", func() {
+			field := &Field{Name: &Name{Value: "id"}, SelectionSet: &SelectionSet{}}
+			So(Print(field), ShouldEqual, "id")
+			So(Print(&SelectionSet{}), ShouldEqual, "")
+			So(Print((*SelectionSet)(nil)), ShouldEqual, "")
+			So(Print(&InlineFragment{TypeCondition: &NamedType{Name: &Name{Value: "User"}}}), ShouldEqual, "... on User")
+		})
+
+		Convey("prints literal values", func() {
+			document := parse(`{ field(int: -12, float: 3.5, whole: 2.0, exp: 1e3, string: "a \"quoted\"\n\tstring \\ here", bool: false, enum: RED, null: null, list: [1, [2], null], object: {a: {b: [C]}}) }`)
+			So(Print(document), ShouldEqual, "{\n  field(int: -12, float: 3.5, whole: 2.0, exp: 1000.0, string: \"a \\\"quoted\\\"\\n\\tstring \\\\ here\", bool: false, enum: RED, null: null, list: [1, [2], null], object: {a: {b: [C]}})\n}\n")
+		})
+
+		Convey("round trips queries", func() {
+			shouldRoundTrip(`
+            query queryName($foo: ComplexType, $site: Site = MOBILE) {
+                whoever123is: node(id: [123, 456]) {
+                    id ,
+                    ... on User @defer {
+                        field2 {
+                            id ,
+                            alias: field1(first:10, after:$foo,) @include(if: $foo) {
+                                id,
+                                ...frag
+                            }
+                        }
+                    }
+                }
+            }
+            mutation likeStory {
+                like(story: 123) @defer {
+                    story {
+                        id
+                    }
+                }
+            }
+            fragment frag on Friend {
+                foo(size: $size, bar: $b, obj: {key: "value", list: [1.5, "two", THREE]})
+            }
+            {
+                unnamed(truthy: true, falsey: false),
+                query
+            }
+            `)
+		})
+
+	})
+
+	Convey("Print: Prints the schema language", t, func() {
+
+		Convey("prints type definitions", func() {
+			document := parse(`
+            ## A thing
+            ## with two lines
+            interface Node { id: ID! }
+            type User implements Node, Entity {
+                id: ID!
+                friends(
+                    ## How many
+                    first: Int = 10
+                    after: String
+                ): [User]
+            }
+            union Result = User | Story
+            enum Color { RED GREEN }
+            input Filter { colors: [Color!] = [RED], limit: Int }
+            schema { query: User }
+            extend schema @live { mutation: User }
+            scalar Date
+            extend type User { age: Int }
+            ## Restricts access
+            directive @auth(role: String = "admin") on FIELD_DEFINITION | OBJECT
+            type Secret @auth { code: String @auth(role: "root") }
+            `)
+			So(Print(document), ShouldEqual, `## A thing
+## with two lines
+interface Node {
+  id: ID!
+}
+
+type User implements Node, Entity {
+  id: ID!
+  friends(
+    ## How many
+    first: Int = 10
+    after: String
+  ): [User]
+}
+
+union Result = User | Story
+
+enum Color {
+  RED
+  GREEN
+}
+
+input Filter {
+  colors: [Color!] = [RED]
+  limit: Int
+}
+
+schema {
+  query: User
+}
+
+extend schema @live {
+  mutation: User
+}
+
+scalar Date
+
+extend type User {
+  age: Int
+}
+
+## Restricts access
+directive @auth(role: String = "admin") on FIELD_DEFINITION | OBJECT
+
+type Secret @auth {
+  code: String @auth(role: "root")
+}
+`)
+		})
+
+		Convey("round trips type definitions", func() {
+			shouldRoundTrip(`
+            ## The root
+            type Query implements Node {
+                ## A field
+                node(id: ID!, filter: Filter = {name: "x", tags: ["a"]}): Node
+                nodes(ids: [ID!]!): [Node]!
+            }
+            interface Node { id: ID }
+            union Any = Query | Other
+            ## Colors
+            enum Color {
+                ## Warm
+                RED
+                BLUE
+            }
+            input Filter { name: String = "default", ratio: Float = 0.5 }
+            scalar Time
+            extend type Query { extra: Boolean }
+            extend interface Node @cost(value: 1) { name: String }
+            extend union Any = Third
+            extend enum Color { GREEN }
+            extend input Filter { limit: Int }
+            extend scalar Time @cost(value: 2)
+            ## The roots
+            schema @cost(value: 0) { query: Query, mutation: Mutation }
+            extend schema { subscription: Subscription }
+            directive @cost(value: Int!) on FIELD_DEFINITION | ARGUMENT_DEFINITION | INPUT_FIELD_DEFINITION
+            input Limits @cost(value: 1) { max: Int @cost(value: 2) }
+            union Many @cost(value: 3) = Query | Other
+            `)
+		})
+
+	})
+
+}
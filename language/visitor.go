@@ -0,0 +1,361 @@
+package language
+
+import (
+	"fmt"
+	"reflect"
+)
+
+type VisitAction int
+
+const (
+	// VISIT_CONTINUE continues the traversal as normal.
+	VISIT_CONTINUE VisitAction = iota
+	// VISIT_SKIP does not visit the children of the node when returned on
+	// enter. The leave function is not called for a skipped node.
+	VISIT_SKIP
+	// VISIT_BREAK stops the traversal. Edits made so far are kept.
+	VISIT_BREAK
+	// VISIT_REPLACE replaces the node with the returned node. A node replaced
+	// on enter has the children of the replacement visited instead.
+	VISIT_REPLACE
+	// VISIT_DELETE removes the node from its parent.
+	VISIT_DELETE
+)
+
+type VisitParams struct {
+	Node ASTNode
+	// Key is the name of the field of the parent holding the node, or the
+	// index of the node if the field is a list.
+	Key       interface{}
+	Parent    ASTNode
+	Path      []interface{}
+	Ancestors []ASTNode
+}
+
+// VisitFunc is called when a node is entered or left. The node it returns is
+// only used with VISIT_REPLACE.
+type VisitFunc func(params *VisitParams) (VisitAction, ASTNode)
+
+type VisitFuncs struct {
+	Enter VisitFunc
+	Leave VisitFunc
+}
+
+type ASTVisitor struct {
+	Enter VisitFunc
+	Leave VisitFunc
+	// Kinds holds the functions used for nodes of a particular kind instead
+	// of Enter and Leave. It is keyed by the name of the AST node type such as
+	// "Field" or "OperationDefinition".
+	Kinds map[string]*VisitFuncs
+}
+
+// VisitorKeys lists the fields of every node kind that are traversed, in the
+// order they are visited. Indexes and locations are not traversed.
+var VisitorKeys = map[string][]string{
+	"Document":                  {"Definitions"},
+	"OperationDefinition":       {"Name", "VariableDefinitions", "Directives", "SelectionSet"},
+	"VariableDefinition":        {"Variable", "Type", "DefaultValue"},
+	"Variable":                  {"Name"},
+	"SelectionSet":              {"Selections"},
+	"Field":                     {"Alias", "Name", "Arguments", "Directives", "SelectionSet"},
+	"Argument":                  {"Name", "Value"},
+	"FragmentSpread":            {"Name", "Directives"},
+	"InlineFragment":            {"TypeCondition", "Directives", "SelectionSet"},
+	"FragmentDefinition":        {"Name", "TypeCondition", "Directives", "SelectionSet"},
+	"Int":                       {},
+	"Float":                     {},
+	"String":                    {},
+	"Boolean":                   {},
+	"Enum":                      {},
+	"Null":                      {},
+	"Literal":                   {},
+	"List":                      {"Values"},
+	"Object":                    {"Fields"},
+	"ObjectField":               {"Name", "Value"},
+	"Directive":                 {"Name", "Arguments"},
+	"Name":                      {},
+	"NamedType":                 {"Name"},
+	"ListType":                  {"Type"},
+	"NonNullType":               {"Type"},
+	"ObjectTypeDefinition":      {"Name", "Interfaces", "Directives", "Fields"},
+	"FieldDefinition":           {"Name", "Arguments", "Type", "Directives"},
+	"InputValueDefinition":      {"Name", "Type", "DefaultValue", "Directives"},
+	"InterfaceTypeDefinition":   {"Name", "Directives", "Fields"},
+	"UnionTypeDefinition":       {"Name", "Directives", "Types"},
+	"ScalarTypeDefinition":      {"Name", "Directives"},
+	"EnumTypeDefinition":        {"Name", "Directives", "Values"},
+	"EnumValueDefinition":       {"Name", "Directives"},
+	"InputObjectTypeDefinition": {"Name", "Directives", "Fields"},
+	"SchemaDefinition":          {"Directives", "OperationTypes"},
+	"OperationTypeDefinition":   {"Type"},
+	"TypeExtensionDefinition":   {"Definition"},
+	"SchemaExtensionDefinition": {"Definition"},
+	"DirectiveDefinition":       {"Name", "Arguments", "Locations"},
+}
+
+// Kind returns the name of the AST node type, which is the key used by
+// ASTVisitor.Kinds and VisitorKeys.
+func Kind(node ASTNode) string {
+	value := reflect.TypeOf(node)
+	if value == nil {
+		return ""
+	}
+	if value.Kind() == reflect.Ptr {
+		value = value.Elem()
+	}
+	return value.Name()
+}
+
+// Visit traverses the AST depth first, calling the enter function of the
+// visitor before the children of a node are visited and the leave function
+// after. The AST passed in is never modified; when nodes are replaced or
+// deleted the edited nodes and their ancestors are copied and the new root is
+// returned. The indexes of copied nodes are not updated.
+func Visit(root ASTNode, visitor *ASTVisitor) ASTNode {
+	traversal := &traversal{
+		visitor: visitor,
+	}
+	node, deleted := traversal.visit(&VisitParams{
+		Node:      root,
+		Path:      []interface{}{},
+		Ancestors: []ASTNode{},
+	})
+	if deleted {
+		return nil
+	}
+	return node
+}
+
+type traversal struct {
+	visitor *ASTVisitor
+	stopped bool
+}
+
+func (visitor *ASTVisitor) visitFunc(node ASTNode, isLeaving bool) VisitFunc {
+	if funcs, ok := visitor.Kinds[Kind(node)]; ok && funcs != nil {
+		if isLeaving {
+			return funcs.Leave
+		}
+		return funcs.Enter
+	}
+	if isLeaving {
+		return visitor.Leave
+	}
+	return visitor.Enter
+}
+
+// visit visits the node and its children and returns the node that should
+// take its place in the parent, or true if it should be removed.
+func (traversal *traversal) visit(params *VisitParams) (ASTNode, bool) {
+	node := params.Node
+	if enter := traversal.visitor.visitFunc(node, false); enter != nil {
+		action, replacement := enter(params)
+		switch action {
+		case VISIT_SKIP:
+			return node, false
+		case VISIT_BREAK:
+			traversal.stopped = true
+			return node, false
+		case VISIT_DELETE:
+			return nil, true
+		case VISIT_REPLACE:
+			if replacement == nil {
+				return nil, true
+			}
+			node = replacement
+		}
+	}
+
+	node = traversal.visitChildren(node, params)
+	if traversal.stopped {
+		return node, false
+	}
+
+	if leave := traversal.visitor.visitFunc(node, true); leave != nil {
+		action, replacement := leave(&VisitParams{
+			Node:      node,
+			Key:       params.Key,
+			Parent:    params.Parent,
+			Path:      params.Path,
+			Ancestors: params.Ancestors,
+		})
+		switch action {
+		case VISIT_BREAK:
+			traversal.stopped = true
+		case VISIT_DELETE:
+			return nil, true
+		case VISIT_REPLACE:
+			if replacement == nil {
+				return nil, true
+			}
+			node = replacement
+		}
+	}
+	return node, false
+}
+
+// visitChildren visits the children of the node and returns the node, or a
+// copy of it if any of its children were edited.
+func (traversal *traversal) visitChildren(node ASTNode, params *VisitParams) ASTNode {
+	value := reflect.ValueOf(node)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return node
+	}
+	var edited reflect.Value
+	ancestors := append(append([]ASTNode{}, params.Ancestors...), node)
+	for _, key := range VisitorKeys[Kind(node)] {
+		if traversal.stopped {
+			break
+		}
+		field := value.Elem().FieldByName(key)
+		path := append(append([]interface{}{}, params.Path...), key)
+		var result reflect.Value
+		if field.Kind() == reflect.Slice {
+			items := reflect.MakeSlice(field.Type(), 0, field.Len())
+			changed := false
+			for index := 0; index < field.Len(); index++ {
+				item := field.Index(index)
+				if traversal.stopped || isNil(item) {
+					items = reflect.Append(items, item)
+					continue
+				}
+				child, deleted := traversal.visit(&VisitParams{
+					Node:      item.Interface(),
+					Key:       index,
+					Parent:    node,
+					Path:      append(append([]interface{}{}, path...), index),
+					Ancestors: ancestors,
+				})
+				if deleted {
+					changed = true
+					continue
+				}
+				if child != item.Interface() {
+					changed = true
+				}
+				items = reflect.Append(items, nodeValue(child, field.Type().Elem(), key))
+			}
+			if !changed {
+				continue
+			}
+			result = items
+		} else {
+			if isNil(field) {
+				continue
+			}
+			child, deleted := traversal.visit(&VisitParams{
+				Node:      field.Interface(),
+				Key:       key,
+				Parent:    node,
+				Path:      path,
+				Ancestors: ancestors,
+			})
+			if deleted {
+				result = reflect.Zero(field.Type())
+			} else if child != field.Interface() {
+				result = nodeValue(child, field.Type(), key)
+			} else {
+				continue
+			}
+		}
+		if !edited.IsValid() {
+			edited = reflect.New(value.Elem().Type())
+			edited.Elem().Set(value.Elem())
+		}
+		edited.Elem().FieldByName(key).Set(result)
+	}
+	if edited.IsValid() {
+		return edited.Interface()
+	}
+	return node
+}
+
+func isNil(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map:
+		return value.IsNil()
+	}
+	return false
+}
+
+func nodeValue(node ASTNode, ttype reflect.Type, key string) reflect.Value {
+	value := reflect.ValueOf(node)
+	if !value.Type().AssignableTo(ttype) {
+		panic(fmt.Sprintf("Visit can not replace %s with a %s", key, Kind(node)))
+	}
+	result := reflect.New(ttype).Elem()
+	result.Set(value)
+	return result
+}
+
+// ParallelVisitor returns a visitor that runs every given visitor in a single
+// traversal. Each visitor may skip subtrees or stop independently of the
+// others. Edits are applied in the order of the visitors: the visitors after
+// one that replaced a node see the replacement, and a deleted node is not seen
+// by the visitors after the one that deleted it.
+func ParallelVisitor(visitors ...*ASTVisitor) *ASTVisitor {
+	// skipping holds the depth of the node whose subtree a visitor is
+	// skipping, -1 when it is not skipping and -2 once it has stopped
+	skipping := make([]int, len(visitors))
+	for index := range skipping {
+		skipping[index] = -1
+	}
+	// unskip ends the skips of the node at the given depth, which is left or
+	// deleted
+	unskip := func(depth int) {
+		for index := range skipping {
+			if skipping[index] == depth {
+				skipping[index] = -1
+			}
+		}
+	}
+	visit := func(params *VisitParams, isLeaving bool) (VisitAction, ASTNode) {
+		depth := len(params.Ancestors)
+		result := VISIT_CONTINUE
+		for index, visitor := range visitors {
+			if skipping[index] != -1 {
+				if isLeaving && skipping[index] == depth {
+					skipping[index] = -1
+				}
+				continue
+			}
+			visitFunc := visitor.visitFunc(params.Node, isLeaving)
+			if visitFunc == nil {
+				continue
+			}
+			action, replacement := visitFunc(params)
+			if action == VISIT_REPLACE && replacement == nil {
+				action = VISIT_DELETE
+			}
+			switch action {
+			case VISIT_SKIP:
+				if !isLeaving {
+					skipping[index] = depth
+				}
+			case VISIT_BREAK:
+				skipping[index] = -2
+			case VISIT_REPLACE:
+				replaced := *params
+				replaced.Node = replacement
+				params = &replaced
+				result = VISIT_REPLACE
+			case VISIT_DELETE:
+				unskip(depth)
+				return VISIT_DELETE, nil
+			}
+		}
+		if result == VISIT_REPLACE {
+			return VISIT_REPLACE, params.Node
+		}
+		return VISIT_CONTINUE, nil
+	}
+	return &ASTVisitor{
+		Enter: func(params *VisitParams) (VisitAction, ASTNode) {
+			return visit(params, false)
+		},
+		Leave: func(params *VisitParams) (VisitAction, ASTNode) {
+			return visit(params, true)
+		},
+	}
+}
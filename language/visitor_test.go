@@ -0,0 +1,373 @@
+package language
+
+import (
+	"fmt"
+	"testing"
+)
+
+// describe returns a short description of the node used to record visits.
+func describe(node ASTNode) string {
+	switch node := node.(type) {
+	case *Name:
+		return "Name " + node.Value
+	case *Int:
+		return fmt.Sprintf("Int %d", node.Value)
+	}
+	return Kind(node)
+}
+
+func recorder(visited *[]string) *ASTVisitor {
+	return &ASTVisitor{
+		Enter: func(params *VisitParams) (VisitAction, ASTNode) {
+			*visited = append(*visited, "enter "+describe(params.Node))
+			return VISIT_CONTINUE, nil
+		},
+		Leave: func(params *VisitParams) (VisitAction, ASTNode) {
+			*visited = append(*visited, "leave "+describe(params.Node))
+			return VISIT_CONTINUE, nil
+		},
+	}
+}
+
+func TestVisitor(t *testing.T) {
+
+	Convey("Visit: Traverses the AST", t, func() {
+
+		Convey("visits nodes in order", func() {
+			visited := []string{}
+			Visit(parse(`{ a: b(x: 1) }`), recorder(&visited))
+			So(visited, ShouldResemble, []string{
+				"enter Document",
+				"enter OperationDefinition",
+				"enter SelectionSet",
+				"enter Field",
+				"enter Name a",
+				"leave Name a",
+				"enter Name b",
+				"leave Name b",
+				"enter Argument",
+				"enter Name x",
+				"leave Name x",
+				"enter Int 1",
+				"leave Int 1",
+				"leave Argument",
+				"leave Field",
+				"leave SelectionSet",
+				"leave OperationDefinition",
+				"leave Document",
+			})
+		})
+
+		Convey("provides the parent, key, path and ancestors", func() {
+			document := parse(`{ a, b }`)
+			var params *VisitParams
+			Visit(document, &ASTVisitor{
+				Kinds: map[string]*VisitFuncs{
+					"Field": {
+						Enter: func(p *VisitParams) (VisitAction, ASTNode) {
+							params = p
+							return VISIT_CONTINUE, nil
+						},
+					},
+				},
+			})
+			selectionSet := document.Definitions[0].(*OperationDefinition).SelectionSet
+			So(params.Node, ShouldEqual, selectionSet.Selections[1])
+			So(params.Key, ShouldEqual, 1)
+			So(params.Parent, ShouldEqual, selectionSet)
+			So(params.Path, ShouldResemble, []interface{}{"Definitions", 0, "SelectionSet", "Selections", 1})
+			So(len(params.Ancestors), ShouldEqual, 3)
+			So(params.Ancestors[0], ShouldEqual, document)
+		})
+
+		Convey("uses the functions registered for a kind", func() {
+			names := []string{}
+			Visit(parse(`{ a { b } }`), &ASTVisitor{
+				Enter: func(params *VisitParams) (VisitAction, ASTNode) {
+					names = append(names, "generic "+Kind(params.Node))
+					return VISIT_CONTINUE, nil
+				},
+				Kinds: map[string]*VisitFuncs{
+					"Name": {
+						Enter: func(params *VisitParams) (VisitAction, ASTNode) {
+							names = append(names, params.Node.(*Name).Value)
+							return VISIT_CONTINUE, nil
+						},
+					},
+					"SelectionSet": {},
+				},
+			})
+			So(names, ShouldResemble, []string{
+				"generic Document",
+				"generic OperationDefinition",
+				"generic Field",
+				"a",
+				"generic Field",
+				"b",
+			})
+		})
+
+		Convey("skips subtrees", func() {
+			visited := []string{}
+			visitor := recorder(&visited)
+			enter := visitor.Enter
+			visitor.Enter = func(params *VisitParams) (VisitAction, ASTNode) {
+				enter(params)
+				if _, ok := params.Node.(*Argument); ok {
+					return VISIT_SKIP, nil
+				}
+				return VISIT_CONTINUE, nil
+			}
+			Visit(parse(`{ a(x: 1) }`), visitor)
+			So(visited, ShouldContain, "enter Argument")
+			So(visited, ShouldNotContain, "enter Int 1")
+			So(visited, ShouldNotContain, "leave Argument")
+			So(visited, ShouldContain, "leave Field")
+		})
+
+		Convey("stops the traversal", func() {
+			visited := []string{}
+			visitor := recorder(&visited)
+			leave := visitor.Leave
+			visitor.Leave = func(params *VisitParams) (VisitAction, ASTNode) {
+				leave(params)
+				if name, ok := params.Node.(*Name); ok && name.Value == "b" {
+					return VISIT_BREAK, nil
+				}
+				return VISIT_CONTINUE, nil
+			}
+			Visit(parse(`{ a, b, c }`), visitor)
+			So(visited[len(visited)-1], ShouldEqual, "leave Name b")
+			So(visited, ShouldNotContain, "enter Name c")
+		})
+
+	})
+
+	Convey("Visit: Edits the AST", t, func() {
+
+		Convey("replaces nodes without modifying the original", func() {
+			document := parse(`{ a(x: 1, y: [2]) }`)
+			edited := Visit(document, &ASTVisitor{
+				Kinds: map[string]*VisitFuncs{
+					"Int": {
+						Leave: func(params *VisitParams) (VisitAction, ASTNode) {
+							return VISIT_REPLACE, &Int{Value: params.Node.(*Int).Value * 10}
+						},
+					},
+				},
+			})
+			So(Print(edited), ShouldEqual, "{\n  a(x: 10, y: [20])\n}\n")
+			So(Print(document), ShouldEqual, "{\n  a(x: 1, y: [2])\n}\n")
+		})
+
+		Convey("visits the children of nodes replaced on enter", func() {
+			visited := []string{}
+			edited := Visit(parse(`{ a }`), &ASTVisitor{
+				Enter: func(params *VisitParams) (VisitAction, ASTNode) {
+					if name, ok := params.Node.(*Name); ok {
+						visited = append(visited, name.Value)
+					}
+					if field, ok := params.Node.(*Field); ok && field.Name.Value == "a" {
+						return VISIT_REPLACE, &Field{
+							Name: &Name{Value: "b"},
+							SelectionSet: &SelectionSet{
+								Selections: []ASTNode{&Field{Name: &Name{Value: "c"}}},
+							},
+						}
+					}
+					return VISIT_CONTINUE, nil
+				},
+			})
+			So(visited, ShouldResemble, []string{"b", "c"})
+			So(Print(edited), ShouldEqual, "{\n  b {\n    c\n  }\n}\n")
+		})
+
+		Convey("deletes nodes", func() {
+			document := parse(`{ a @skip(if: true), b, c @skip(if: true) { d } }`)
+			edited := Visit(document, &ASTVisitor{
+				Kinds: map[string]*VisitFuncs{
+					"Field": {
+						Enter: func(params *VisitParams) (VisitAction, ASTNode) {
+							if len(params.Node.(*Field).Directives) > 0 {
+								return VISIT_DELETE, nil
+							}
+							return VISIT_CONTINUE, nil
+						},
+					},
+				},
+			})
+			So(Print(edited), ShouldEqual, "{\n  b\n}\n")
+			So(len(document.Definitions[0].(*OperationDefinition).SelectionSet.Selections), ShouldEqual, 3)
+		})
+
+		Convey("keeps edits made before stopping", func() {
+			edited := Visit(parse(`{ a, b }`), &ASTVisitor{
+				Kinds: map[string]*VisitFuncs{
+					"Name": {
+						Enter: func(params *VisitParams) (VisitAction, ASTNode) {
+							if params.Node.(*Name).Value == "a" {
+								return VISIT_REPLACE, &Name{Value: "z"}
+							}
+							return VISIT_BREAK, nil
+						},
+					},
+				},
+			})
+			So(Print(edited), ShouldEqual, "{\n  z\n  b\n}\n")
+		})
+
+		Convey("panics on replacements of the wrong type", func() {
+			So(func() {
+				Visit(parse(`{ a(x: 1) }`), &ASTVisitor{
+					Kinds: map[string]*VisitFuncs{
+						"Argument": {
+							Enter: func(params *VisitParams) (VisitAction, ASTNode) {
+								return VISIT_REPLACE, &Int{Value: 1}
+							},
+						},
+					},
+				})
+			}, ShouldPanicWith, "Visit can not replace Arguments with a Int")
+		})
+
+	})
+
+	Convey("ParallelVisitor: Runs visitors in a single traversal", t, func() {
+
+		Convey("skips and stops visitors independently", func() {
+			skipped := []string{}
+			stopped := []string{}
+			all := []string{}
+			Visit(parse(`{ a { b }, c, d }`), ParallelVisitor(
+				&ASTVisitor{
+					Kinds: map[string]*VisitFuncs{
+						"Field": {
+							Enter: func(params *VisitParams) (VisitAction, ASTNode) {
+								skipped = append(skipped, "enter "+params.Node.(*Field).Name.Value)
+								return VISIT_SKIP, nil
+							},
+							Leave: func(params *VisitParams) (VisitAction, ASTNode) {
+								skipped = append(skipped, "leave "+params.Node.(*Field).Name.Value)
+								return VISIT_CONTINUE, nil
+							},
+						},
+					},
+				},
+				&ASTVisitor{
+					Kinds: map[string]*VisitFuncs{
+						"Field": {
+							Enter: func(params *VisitParams) (VisitAction, ASTNode) {
+								stopped = append(stopped, params.Node.(*Field).Name.Value)
+								if params.Node.(*Field).Name.Value == "c" {
+									return VISIT_BREAK, nil
+								}
+								return VISIT_CONTINUE, nil
+							},
+						},
+					},
+				},
+				&ASTVisitor{
+					Kinds: map[string]*VisitFuncs{
+						"Field": {
+							Enter: func(params *VisitParams) (VisitAction, ASTNode) {
+								all = append(all, params.Node.(*Field).Name.Value)
+								return VISIT_CONTINUE, nil
+							},
+						},
+					},
+				},
+			))
+			So(skipped, ShouldResemble, []string{"enter a", "enter c", "enter d"})
+			So(stopped, ShouldResemble, []string{"a", "b", "c"})
+			So(all, ShouldResemble, []string{"a", "b", "c", "d"})
+		})
+
+		Convey("applies edits", func() {
+			edited := Visit(parse(`{ a, b }`), ParallelVisitor(
+				&ASTVisitor{},
+				&ASTVisitor{
+					Kinds: map[string]*VisitFuncs{
+						"Field": {
+							Leave: func(params *VisitParams) (VisitAction, ASTNode) {
+								if params.Node.(*Field).Name.Value == "a" {
+									return VISIT_DELETE, nil
+								}
+								return VISIT_CONTINUE, nil
+							},
+						},
+					},
+				},
+			))
+			So(Print(edited), ShouldEqual, "{\n  b\n}\n")
+		})
+
+		Convey("ends the skips of a node deleted by another visitor", func() {
+			seen := []string{}
+			edited := Visit(parse(`{ a { x }, b { y }, c }`), ParallelVisitor(
+				&ASTVisitor{
+					Kinds: map[string]*VisitFuncs{
+						"Field": {
+							Enter: func(params *VisitParams) (VisitAction, ASTNode) {
+								seen = append(seen, params.Node.(*Field).Name.Value)
+								return VISIT_SKIP, nil
+							},
+						},
+					},
+				},
+				&ASTVisitor{
+					Kinds: map[string]*VisitFuncs{
+						"Field": {
+							Enter: func(params *VisitParams) (VisitAction, ASTNode) {
+								if params.Node.(*Field).Name.Value == "a" {
+									return VISIT_DELETE, nil
+								}
+								return VISIT_CONTINUE, nil
+							},
+						},
+					},
+				},
+			))
+			So(seen, ShouldResemble, []string{"a", "b", "c"})
+			So(Print(edited), ShouldEqual, "{\n  b {\n    y\n  }\n  c\n}\n")
+		})
+
+		Convey("shows the replacement of a node to the visitors after the one replacing it", func() {
+			seen := []string{}
+			edited := Visit(parse(`{ a, b }`), ParallelVisitor(
+				&ASTVisitor{
+					Kinds: map[string]*VisitFuncs{
+						"Field": {
+							Enter: func(params *VisitParams) (VisitAction, ASTNode) {
+								field := params.Node.(*Field)
+								if field.Name.Value != "a" {
+									return VISIT_CONTINUE, nil
+								}
+								replacement := *field
+								replacement.Name = &Name{Value: "renamed"}
+								return VISIT_REPLACE, &replacement
+							},
+						},
+					},
+				},
+				&ASTVisitor{
+					Kinds: map[string]*VisitFuncs{
+						"Field": {
+							Enter: func(params *VisitParams) (VisitAction, ASTNode) {
+								seen = append(seen, "enter "+params.Node.(*Field).Name.Value)
+								return VISIT_CONTINUE, nil
+							},
+							Leave: func(params *VisitParams) (VisitAction, ASTNode) {
+								seen = append(seen, "leave "+params.Node.(*Field).Name.Value)
+								return VISIT_CONTINUE, nil
+							},
+						},
+					},
+				},
+			))
+			So(seen, ShouldResemble, []string{"enter renamed", "leave renamed", "enter b", "leave b"})
+			So(Print(edited), ShouldEqual, "{\n  renamed\n  b\n}\n")
+		})
+
+	})
+
+}
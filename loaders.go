@@ -0,0 +1,166 @@
+package graphql
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/playlyfe/go-graphql/dataloader"
+	. "github.com/playlyfe/go-graphql/language"
+)
+
+// loaders returns the loaders of a new request, or nil if the executor has no
+// batch functions.
+func (executor *Executor) loaders(ctx context.Context) *dataloader.Registry {
+	if len(executor.Loaders) == 0 {
+		return nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return dataloader.NewRegistry(ctx, executor.Loaders)
+}
+
+// resolveThunk calls the thunk returned by a resolver to get the value of the
+// field. A failing or panicking thunk is reported as an error of the field,
+// like a failing resolver, and completes to null.
+func (executor *Executor) resolveThunk(reqCtx *RequestContext, path *ResponsePath, field *Field, result interface{}) interface{} {
+	var thunk func() (interface{}, error)
+	switch value := result.(type) {
+	case dataloader.Thunk:
+		thunk = value
+	case func() (interface{}, error):
+		thunk = value
+	default:
+		return result
+	}
+	defer executor.recoverPanic(reqCtx, field, path, nil)
+	value, err := thunk()
+	if panicErr, ok := err.(*dataloader.PanicError); ok {
+		// The batch function of a loader panicked while loading the value
+		if executor.CrashOnPanic {
+			panic(panicErr.Value)
+		}
+		err = executor.panicError(field, path, panicErr.Value, panicErr.Stack)
+	}
+	if err != nil {
+		reqCtx.ErrorList.Add(&Error{
+			Error: err,
+			Field: field,
+			Path:  path.AsArray(),
+		})
+		return nil
+	}
+	return value
+}
+
+// preparedObject is an object whose fields have been resolved but not yet
+// completed.
+type preparedObject struct {
+	value      interface{}
+	objectType *ObjectTypeDefinition
+	entries    []*fieldEntry
+}
+
+// prepareFieldEntries runs the resolvers of the fields selected on the values
+// of the entries, one level of the tree at a time. The loaders are dispatched
+// once the resolvers of a level have run, so that the keys loaded by a level
+// are loaded in a single batch, and the values of the entries are replaced by
+// prepared objects that are completed without running their resolvers again.
+func (executor *Executor) prepareFieldEntries(reqCtx *RequestContext, entries []*fieldEntry) {
+	for len(entries) > 0 {
+		// The loaders recover the panics of their batch functions, which are
+		// reported by resolveThunk on the fields waiting for the batch
+		reqCtx.Loaders.Dispatch()
+		nextEntries := make([][]*fieldEntry, len(entries))
+		batch := executor.Scheduler.batch(reqCtx)
+		for index, entry := range entries {
+			if entry.err != nil || entry.fieldType == nil {
+				continue
+			}
+			index, entry := index, entry
+			batch.do(false, func() {
+				entry.value, nextEntries[index] = executor.prepareValue(reqCtx, entry.path, entry.fieldType, entry.fields[0], entry.value, executor.mergeSelectionSets(entry.fields))
+			})
+		}
+		batch.wait()
+		entries = nil
+		for _, next := range nextEntries {
+			entries = append(entries, next...)
+		}
+	}
+}
+
+// prepareValue resolves the thunks of the value and runs the resolvers of the
+// fields selected on its objects, returning the value with its objects
+// replaced by prepared objects along with the entries of their fields. Values
+// that do not match their type are left for completeValue to report.
+func (executor *Executor) prepareValue(reqCtx *RequestContext, path *ResponsePath, fieldType ASTNode, field *Field, value interface{}, subSelectionSet *SelectionSet) (interface{}, []*fieldEntry) {
+	value = executor.resolveThunk(reqCtx, path, field, value)
+	if value == nil {
+		return nil, nil
+	}
+	switch fieldType := fieldType.(type) {
+	case *NonNullType:
+		return executor.prepareValue(reqCtx, path, fieldType.Type, field, value, subSelectionSet)
+	case *ListType:
+		if executor.inlineItems(fieldType.Type) {
+			return value, nil
+		}
+		list := reflect.ValueOf(value)
+		if list.Kind() != reflect.Slice {
+			return value, nil
+		}
+		items := make([]interface{}, list.Len())
+		itemEntries := make([][]*fieldEntry, list.Len())
+		batch := executor.Scheduler.batch(reqCtx)
+		for index := range items {
+			index := index
+			batch.do(false, func() {
+				items[index], itemEntries[index] = executor.prepareValue(reqCtx, path.WithKey(index), fieldType.Type, field, list.Index(index).Interface(), subSelectionSet)
+			})
+		}
+		batch.wait()
+		entries := []*fieldEntry{}
+		for _, itemEntry := range itemEntries {
+			entries = append(entries, itemEntry...)
+		}
+		return items, entries
+	case *NamedType:
+		objectType := executor.possibleObjectType(fieldType, value)
+		if objectType == nil {
+			return value, nil
+		}
+		groupedFields, _, err := executor.groupedFields(reqCtx, objectType, subSelectionSet)
+		if err != nil {
+			return value, nil
+		}
+		entries := executor.resolveFieldEntries(reqCtx, path, objectType, value, groupedFields)
+		return &preparedObject{
+			value:      value,
+			objectType: objectType,
+			entries:    entries,
+		}, entries
+	}
+	return value, nil
+}
+
+// possibleObjectType returns the object type of a value of the given type, or
+// nil if it is not a composite type or the object type cannot be determined.
+func (executor *Executor) possibleObjectType(namedType *NamedType, value interface{}) *ObjectTypeDefinition {
+	schema := executor.Schema.Document
+	if objectType, ok := schema.ObjectTypeIndex[namedType.Name.Value]; ok {
+		return objectType
+	}
+	_, isInterface := schema.InterfaceTypeIndex[namedType.Name.Value]
+	_, isUnion := schema.UnionTypeIndex[namedType.Name.Value]
+	if !isInterface && !isUnion || executor.ResolveType == nil {
+		return nil
+	}
+	typeName := executor.ResolveType(value)
+	for _, possibleType := range schema.PossibleTypesIndex[namedType.Name.Value] {
+		if possibleType.Name.Value == typeName {
+			return possibleType
+		}
+	}
+	return nil
+}
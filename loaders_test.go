@@ -0,0 +1,161 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/playlyfe/go-graphql/dataloader"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestLoaders(t *testing.T) {
+
+	Convey("Execute: Batches the keys loaded by resolvers", t, func() {
+		schema := `
+        type User {
+            id: Int
+            name: String
+            bestFriend: User
+            friends: [User]
+        }
+
+        type Query {
+            users: [User]
+            user(id: Int): User
+        }
+        `
+		users := map[int]map[string]interface{}{}
+		for id := 1; id <= 9; id++ {
+			users[id] = map[string]interface{}{
+				"id":         id,
+				"name":       fmt.Sprintf("user %d", id),
+				"bestFriend": id + 3,
+			}
+		}
+		resolvers := map[string]interface{}{}
+		resolvers["Query/users"] = func(params *ResolveParams) (interface{}, error) {
+			return params.Loaders.Loader("user").LoadMany([]interface{}{1, 2, 3}), nil
+		}
+		resolvers["Query/user"] = func(params *ResolveParams) (interface{}, error) {
+			return params.Loaders.Load("user", int(params.Args["id"].(int32))), nil
+		}
+		resolvers["User/bestFriend"] = func(params *ResolveParams) (interface{}, error) {
+			return params.Loaders.Load("user", params.Source.(map[string]interface{})["bestFriend"]), nil
+		}
+		resolvers["User/friends"] = func(params *ResolveParams) (interface{}, error) {
+			return func() (interface{}, error) {
+				return nil, errors.New("Friends are private")
+			}, nil
+		}
+		executor, err := NewExecutor(schema, "Query", "", resolvers)
+		So(err, ShouldEqual, nil)
+
+		mutex := sync.Mutex{}
+		batches := [][]int{}
+		executor.Loaders["user"] = func(ctx context.Context, keys []interface{}) []*dataloader.Result {
+			batch := []int{}
+			results := []*dataloader.Result{}
+			for _, key := range keys {
+				batch = append(batch, key.(int))
+				if user, ok := users[key.(int)]; ok {
+					results = append(results, &dataloader.Result{Value: user})
+				} else {
+					results = append(results, &dataloader.Result{Error: fmt.Errorf("No user %d", key)})
+				}
+			}
+			sort.Ints(batch)
+			mutex.Lock()
+			batches = append(batches, batch)
+			mutex.Unlock()
+			return results
+		}
+
+		Convey("loads the keys of every level of the tree in a single batch", func() {
+			result, err := executor.Execute(nil, `{
+                users {
+                    name
+                    bestFriend {
+                        name
+                        bestFriend { id }
+                    }
+                }
+            }`, map[string]interface{}{}, "")
+			So(err, ShouldEqual, nil)
+			So(result, ShouldResemble, map[string]interface{}{
+				"data": map[string]interface{}{
+					"users": []interface{}{
+						map[string]interface{}{
+							"name":       "user 1",
+							"bestFriend": map[string]interface{}{"name": "user 4", "bestFriend": map[string]interface{}{"id": int32(7)}},
+						},
+						map[string]interface{}{
+							"name":       "user 2",
+							"bestFriend": map[string]interface{}{"name": "user 5", "bestFriend": map[string]interface{}{"id": int32(8)}},
+						},
+						map[string]interface{}{
+							"name":       "user 3",
+							"bestFriend": map[string]interface{}{"name": "user 6", "bestFriend": map[string]interface{}{"id": int32(9)}},
+						},
+					},
+				},
+			})
+			So(batches, ShouldResemble, [][]int{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}})
+		})
+
+		Convey("shares the cache of the loaders between the fields of a request", func() {
+			result, err := executor.Execute(nil, `{
+                a: user(id: 1) { name }
+                b: user(id: 1) { name }
+                c: user(id: 4) { bestFriend { name } }
+            }`, map[string]interface{}{}, "")
+			So(err, ShouldEqual, nil)
+			So(result["data"], ShouldResemble, map[string]interface{}{
+				"a": map[string]interface{}{"name": "user 1"},
+				"b": map[string]interface{}{"name": "user 1"},
+				"c": map[string]interface{}{"bestFriend": map[string]interface{}{"name": "user 7"}},
+			})
+			So(batches, ShouldResemble, [][]int{{1, 4}, {7}})
+
+			_, err = executor.Execute(nil, `{ user(id: 1) { name } }`, map[string]interface{}{}, "")
+			So(err, ShouldEqual, nil)
+			So(len(batches), ShouldEqual, 3)
+		})
+
+		Convey("reports the errors of thunks as field errors", func() {
+			result, err := executor.Execute(nil, `{
+                user(id: 7) {
+                    bestFriend { name }
+                    friends { name }
+                }
+            }`, map[string]interface{}{}, "")
+			So(err, ShouldEqual, nil)
+			So(result["data"], ShouldResemble, map[string]interface{}{
+				"user": map[string]interface{}{
+					"bestFriend": nil,
+					"friends":    nil,
+				},
+			})
+			errs := result["errors"].([]map[string]interface{})
+			messages := []string{}
+			for _, err := range errs {
+				messages = append(messages, err["message"].(string))
+			}
+			sort.Strings(messages)
+			So(messages, ShouldResemble, []string{"Friends are private", "No user 10"})
+		})
+
+		Convey("resolves thunks in serial mode", func() {
+			executor.Scheduler = NewScheduler(0, 0)
+			executor.Scheduler.Serial = true
+			result, err := executor.Execute(nil, `{ users { bestFriend { name } } }`, map[string]interface{}{}, "")
+			So(err, ShouldEqual, nil)
+			So(result["errors"], ShouldEqual, nil)
+			So(batches, ShouldResemble, [][]int{{1, 2, 3}, {4, 5, 6}})
+		})
+	})
+
+}
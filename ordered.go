@@ -0,0 +1,110 @@
+package graphql
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// OrderedMap is an object of a result whose fields keep the order they were
+// selected in, as required by the specification for serialized results.
+type OrderedMap struct {
+	Keys   []string
+	Values map[string]interface{}
+}
+
+// NewOrderedMap returns an empty object.
+func NewOrderedMap() *OrderedMap {
+	return &OrderedMap{
+		Keys:   []string{},
+		Values: map[string]interface{}{},
+	}
+}
+
+// Get returns the value of the field with the given key.
+func (object *OrderedMap) Get(key string) (interface{}, bool) {
+	value, ok := object.Values[key]
+	return value, ok
+}
+
+// Set sets the value of the field with the given key, adding the field after
+// the existing ones if it is not part of the object yet.
+func (object *OrderedMap) Set(key string, value interface{}) {
+	if _, ok := object.Values[key]; !ok {
+		object.Keys = append(object.Keys, key)
+	}
+	object.Values[key] = value
+}
+
+// Len returns the number of fields of the object.
+func (object *OrderedMap) Len() int {
+	return len(object.Keys)
+}
+
+// Map returns the fields of the object as a map, converting the objects nested
+// in its values as well.
+func (object *OrderedMap) Map() map[string]interface{} {
+	if object == nil {
+		return nil
+	}
+	result := make(map[string]interface{}, len(object.Keys))
+	for _, key := range object.Keys {
+		result[key] = unorderedValue(object.Values[key])
+	}
+	return result
+}
+
+func unorderedValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case *OrderedMap:
+		return value.Map()
+	case []interface{}:
+		items := make([]interface{}, len(value))
+		for index, item := range value {
+			items[index] = unorderedValue(item)
+		}
+		return items
+	}
+	return value
+}
+
+// MarshalJSON marshals the object with its fields in order.
+func (object *OrderedMap) MarshalJSON() ([]byte, error) {
+	if object == nil {
+		return []byte("null"), nil
+	}
+	buffer := &bytes.Buffer{}
+	buffer.WriteByte('{')
+	for index, key := range object.Keys {
+		if index > 0 {
+			buffer.WriteByte(',')
+		}
+		encodedKey, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buffer.Write(encodedKey)
+		buffer.WriteByte(':')
+		encodedValue, err := json.Marshal(object.Values[key])
+		if err != nil {
+			return nil, err
+		}
+		buffer.Write(encodedValue)
+	}
+	buffer.WriteByte('}')
+	return buffer.Bytes(), nil
+}
+
+// orderedObject returns the fields of the result in the order of the grouped
+// fields they were resolved from.
+func orderedObject(groupedFields []*GroupedField, result map[string]interface{}) *OrderedMap {
+	object := &OrderedMap{
+		Keys:   make([]string, 0, len(result)),
+		Values: result,
+	}
+	for _, groupedField := range groupedFields {
+		if _, ok := result[groupedField.ResponseKey]; ok {
+			object.Keys = append(object.Keys, groupedField.ResponseKey)
+		}
+	}
+	return object
+}
@@ -0,0 +1,92 @@
+package graphql
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestOrderedResults(t *testing.T) {
+
+	Convey("Execute: Preserves the order of the selected fields", t, func() {
+		schema := `
+        type Item {
+            id: Int
+            name: String
+            tags: [String]
+        }
+
+        type Query {
+            zeta: String
+            alpha: String
+            item: Item
+            items: [Item]
+        }
+        `
+		resolvers := map[string]interface{}{}
+		resolvers["Query/zeta"] = func(params *ResolveParams) (interface{}, error) {
+			return "z", nil
+		}
+		resolvers["Query/alpha"] = func(params *ResolveParams) (interface{}, error) {
+			return "a", nil
+		}
+		resolvers["Query/item"] = func(params *ResolveParams) (interface{}, error) {
+			return map[string]interface{}{"id": 1, "name": "one", "tags": []interface{}{"x", "y"}}, nil
+		}
+		resolvers["Query/items"] = func(params *ResolveParams) (interface{}, error) {
+			return []interface{}{
+				map[string]interface{}{"id": 1, "name": "one"},
+				map[string]interface{}{"id": 2, "name": "two"},
+			}, nil
+		}
+		executor, err := NewExecutor(schema, "Query", "", resolvers)
+		So(err, ShouldEqual, nil)
+		executor.OrderedResults = true
+
+		marshal := func(request string) string {
+			result, err := executor.Execute(nil, request, map[string]interface{}{}, "")
+			So(err, ShouldEqual, nil)
+			output, err := json.Marshal(result)
+			So(err, ShouldEqual, nil)
+			return string(output)
+		}
+
+		Convey("when marshalling results to JSON", func() {
+			So(marshal(`{ zeta alpha item { tags name id } }`), ShouldEqual, `{"data":{"zeta":"z","alpha":"a","item":{"tags":["x","y"],"name":"one","id":1}}}`)
+			So(marshal(`{ items { name id } alpha }`), ShouldEqual, `{"data":{"items":[{"name":"one","id":1},{"name":"two","id":2}],"alpha":"a"}}`)
+		})
+
+		Convey("including the fields of fragments and aliases", func() {
+			So(marshal(`{ zeta ...f last: alpha } fragment f on Query { item { id } zeta }`), ShouldEqual, `{"data":{"zeta":"z","item":{"id":1},"last":"a"}}`)
+		})
+
+		Convey("while still providing the fields as maps", func() {
+			request := `{ zeta item { name } items { id } }`
+			result, err := executor.Execute(nil, request, map[string]interface{}{}, "")
+			So(err, ShouldEqual, nil)
+			data := result["data"].(*OrderedMap)
+			So(data.Keys, ShouldResemble, []string{"zeta", "item", "items"})
+			value, ok := data.Get("zeta")
+			So(ok, ShouldBeTrue)
+			So(value, ShouldEqual, "z")
+
+			executor.OrderedResults = false
+			unordered, err := executor.Execute(nil, request, map[string]interface{}{}, "")
+			So(err, ShouldEqual, nil)
+			So(data.Map(), ShouldResemble, unordered["data"])
+		})
+	})
+
+	Convey("OrderedMap: Marshals its fields in order", t, func() {
+		object := NewOrderedMap()
+		object.Set("b", 1)
+		object.Set("a", []interface{}{NewOrderedMap()})
+		object.Set("b", "2")
+		So(object.Len(), ShouldEqual, 2)
+		output, err := json.Marshal(object)
+		So(err, ShouldEqual, nil)
+		So(string(output), ShouldEqual, `{"b":"2","a":[{}]}`)
+	})
+
+}
@@ -0,0 +1,73 @@
+package graphql
+
+import (
+	"fmt"
+	"runtime/debug"
+
+	. "github.com/playlyfe/go-graphql/language"
+)
+
+// Logger receives the messages logged by the executor, such as the panics
+// recovered while resolving fields. A *log.Logger is a Logger.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// taskPanic is a panic raised by a task of a batch run in its own goroutine,
+// which panics again in the goroutine waiting for the batch along with the
+// stack of the goroutine it was raised in.
+type taskPanic struct {
+	value interface{}
+	stack []byte
+}
+
+func (p *taskPanic) Error() string {
+	return fmt.Sprintf("%v\n\n%s", p.value, p.stack)
+}
+
+// recoverPanic recovers a panic raised while resolving or completing the field
+// at the given path and reports it with panicError. The error replaces the
+// error pointed to by err, or is added to the errors of the request when err
+// is nil. Panics are not recovered when CrashOnPanic is set. recoverPanic must
+// be deferred.
+func (executor *Executor) recoverPanic(reqCtx *RequestContext, field *Field, path *ResponsePath, err *error) {
+	if executor.CrashOnPanic {
+		return
+	}
+	r := recover()
+	if r == nil {
+		return
+	}
+	value, stack := r, []byte(nil)
+	if p, ok := r.(*taskPanic); ok {
+		value, stack = p.value, p.stack
+	} else {
+		stack = debug.Stack()
+	}
+	panicErr := executor.panicError(field, path, value, stack)
+	if err != nil {
+		*err = panicErr
+		return
+	}
+	reqCtx.ErrorList.Add(&Error{
+		Error: panicErr,
+		Field: field,
+		Path:  path.AsArray(),
+	})
+}
+
+// panicError logs a panic raised while resolving the field at the given path
+// along with its stack, and returns the located error reporting it.
+func (executor *Executor) panicError(field *Field, path *ResponsePath, value interface{}, stack []byte) *GraphQLError {
+	if executor.Logger != nil {
+		executor.Logger.Printf("graphql: panic resolving %v: %v\n%s", path.AsArray(), value, stack)
+	}
+	return &GraphQLError{
+		Message: fmt.Sprintf("GraphQL Runtime Error (%d:%d) Internal error resolving field %s", field.Name.LOC.Start.Line, field.Name.LOC.Start.Column, field.Name.Value),
+		Source:  field.Name.LOC.Source,
+		Start:   field.Name.LOC.Start,
+		End:     field.Name.LOC.End,
+		Field:   field,
+		Path:    path.AsArray(),
+	}
+}
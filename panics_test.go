@@ -0,0 +1,194 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/playlyfe/go-graphql/dataloader"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type testLogger struct {
+	messages []string
+	sync.Mutex
+}
+
+func (logger *testLogger) Printf(format string, v ...interface{}) {
+	logger.Lock()
+	logger.messages = append(logger.messages, fmt.Sprintf(format, v...))
+	logger.Unlock()
+}
+
+func TestPanics(t *testing.T) {
+
+	Convey("Execute: Recovers the panics raised while resolving fields", t, func() {
+		schema := `
+        scalar Fragile
+
+        type Item {
+            id: Int!
+            name: String
+        }
+
+        type Query {
+            sibling: String
+            panics: String
+            item: Item
+            items: [Item]
+            values: [Fragile]
+            loaded(id: Int): String
+        }
+        `
+		resolvers := map[string]interface{}{}
+		resolvers["Query/sibling"] = func(params *ResolveParams) (interface{}, error) {
+			return "sibling", nil
+		}
+		resolvers["Query/panics"] = func(params *ResolveParams) (interface{}, error) {
+			panic("resolver panicked")
+		}
+		resolvers["Query/item"] = func(params *ResolveParams) (interface{}, error) {
+			return map[string]interface{}{"name": "one"}, nil
+		}
+		resolvers["Item/id"] = func(params *ResolveParams) (interface{}, error) {
+			var item map[string]int
+			return item["id"] + 1/len(item), nil
+		}
+		resolvers["Query/items"] = func(params *ResolveParams) (interface{}, error) {
+			return []interface{}{
+				map[string]interface{}{"name": "one"},
+				map[string]interface{}{"name": "two"},
+			}, nil
+		}
+		resolvers["Query/values"] = func(params *ResolveParams) (interface{}, error) {
+			return []interface{}{"a", "panic", "c"}, nil
+		}
+		resolvers["Query/loaded"] = func(params *ResolveParams) (interface{}, error) {
+			return params.Loaders.Load("loaded", params.Args["id"]), nil
+		}
+		executor, err := NewExecutor(schema, "Query", "", resolvers)
+		So(err, ShouldEqual, nil)
+		executor.Scalars["Fragile"] = &Scalar{
+			Serialize: func(context interface{}, value interface{}) (interface{}, error) {
+				if value == "panic" {
+					panic("serialize panicked")
+				}
+				return value, nil
+			},
+		}
+		executor.Scheduler = NewScheduler(1, 1)
+		logger := &testLogger{}
+		executor.Logger = logger
+
+		execute := func(request string) map[string]interface{} {
+			result, err := executor.Execute(nil, request, map[string]interface{}{}, "")
+			So(err, ShouldEqual, nil)
+			return result
+		}
+		// The messages of the errors are followed by the location of the field
+		// in the request
+		panicErrors := func(result map[string]interface{}) []map[string]interface{} {
+			errors := []map[string]interface{}{}
+			for _, err := range result["errors"].([]map[string]interface{}) {
+				message := err["message"].(string)
+				errors = append(errors, map[string]interface{}{
+					"message":   message[:strings.Index(message, "\n")],
+					"path":      err["path"],
+					"locations": err["locations"],
+				})
+			}
+			return errors
+		}
+		panicError := func(line int, column int, field string, path ...interface{}) map[string]interface{} {
+			return map[string]interface{}{
+				"message": fmt.Sprintf("GraphQL Runtime Error (%d:%d) Internal error resolving field %s", line, column, field),
+				"path":    path,
+				"locations": []map[string]interface{}{
+					{"line": line, "column": column},
+				},
+			}
+		}
+
+		Convey("as errors of the fields that panicked", func() {
+			result := execute(`{ panics sibling }`)
+			So(result["data"], ShouldResemble, map[string]interface{}{
+				"panics":  nil,
+				"sibling": "sibling",
+			})
+			So(panicErrors(result), ShouldResemble, []map[string]interface{}{
+				panicError(1, 3, "panics", "panics"),
+			})
+		})
+
+		Convey("nulling the nearest nullable value when a non-null field panics", func() {
+			result := execute(`{ item { name id } items { id } sibling }`)
+			So(result["data"], ShouldResemble, map[string]interface{}{
+				"item":    nil,
+				"items":   []interface{}{nil, nil},
+				"sibling": "sibling",
+			})
+			errors := panicErrors(result)
+			So(len(errors), ShouldEqual, 3)
+			So(errors, ShouldContain, panicError(1, 15, "id", "item", "id"))
+			So(errors, ShouldContain, panicError(1, 28, "id", "items", 0, "id"))
+			So(errors, ShouldContain, panicError(1, 28, "id", "items", 1, "id"))
+		})
+
+		Convey("including the panics raised while completing values in other goroutines", func() {
+			executor.Scheduler = NewScheduler(0, 0)
+			executor.Scheduler.InlineLookups = false
+			result := execute(`{ values }`)
+			So(result["data"], ShouldResemble, map[string]interface{}{
+				"values": []interface{}{"a", nil, "c"},
+			})
+			So(panicErrors(result), ShouldResemble, []map[string]interface{}{
+				panicError(1, 3, "values", "values", 1),
+			})
+		})
+
+		Convey("including the panics of batch functions, on the fields waiting for the batch", func() {
+			executor.Loaders["loaded"] = func(ctx context.Context, keys []interface{}) []*dataloader.Result {
+				panic("batch panicked")
+			}
+			result := execute(`{ first: loaded(id: 1) second: loaded(id: 2) sibling }`)
+			So(result["data"], ShouldResemble, map[string]interface{}{
+				"first":   nil,
+				"second":  nil,
+				"sibling": "sibling",
+			})
+			errors := panicErrors(result)
+			So(len(errors), ShouldEqual, 2)
+			So(errors, ShouldContain, panicError(1, 10, "loaded", "first"))
+			So(errors, ShouldContain, panicError(1, 32, "loaded", "second"))
+			So(len(logger.messages), ShouldEqual, 2)
+			So(logger.messages[0], ShouldContainSubstring, "batch panicked\n")
+			So(logger.messages[0], ShouldContainSubstring, "panics_test.go")
+		})
+
+		Convey("and logs them with their stack", func() {
+			execute(`{ panics }`)
+			So(len(logger.messages), ShouldEqual, 1)
+			So(logger.messages[0], ShouldStartWith, "graphql: panic resolving [panics]: resolver panicked\n")
+			So(logger.messages[0], ShouldContainSubstring, "goroutine")
+			So(logger.messages[0], ShouldContainSubstring, "panics_test.go")
+
+			executor.Scheduler = NewScheduler(0, 0)
+			executor.Scheduler.InlineLookups = false
+			execute(`{ values }`)
+			So(len(logger.messages), ShouldEqual, 2)
+			So(logger.messages[1], ShouldStartWith, "graphql: panic resolving [values 1]: serialize panicked\n")
+			So(strings.Contains(logger.messages[1], "panics_test.go"), ShouldBeTrue)
+		})
+
+		Convey("unless CrashOnPanic is set", func() {
+			executor.CrashOnPanic = true
+			So(func() {
+				executor.Execute(nil, `{ panics }`, map[string]interface{}{}, "")
+			}, ShouldPanicWith, "resolver panicked")
+			So(logger.messages, ShouldBeEmpty)
+		})
+	})
+
+}
@@ -0,0 +1,63 @@
+package graphql
+
+import (
+	"github.com/playlyfe/go-graphql/persisted"
+)
+
+func persistedQueryError(message string, code string) error {
+	return &ExtendedError{
+		Message: message,
+		Extensions: map[string]interface{}{
+			"code": code,
+		},
+	}
+}
+
+// ResolvePersistedQuery returns the query of a request referring to a query of
+// PersistedQueries by its hash, as done by Automatic Persisted Queries.
+// Requests sending the query along with its hash register it, unless
+// OnlyPersistedQueries is set. Queries that cannot be registered, for instance
+// because the store is full, are still returned and the error is logged. The
+// errors returned carry the code of the
+// protocol in their extensions and are meant to be sent back to the client.
+func (executor *Executor) ResolvePersistedQuery(query string, hash string) (string, error) {
+	store := executor.PersistedQueries
+	if store == nil {
+		return "", persistedQueryError("PersistedQueryNotSupported", "PERSISTED_QUERY_NOT_SUPPORTED")
+	}
+	if query == "" {
+		if persistedQuery, ok := store.Get(hash); ok {
+			return persistedQuery, nil
+		}
+		return "", persistedQueryError("PersistedQueryNotFound", "PERSISTED_QUERY_NOT_FOUND")
+	}
+	if persisted.Hash(query) != hash {
+		return "", persistedQueryError("provided sha does not match query", "INVALID_PERSISTED_QUERY_HASH")
+	}
+	if !executor.OnlyPersistedQueries {
+		// Registering is best effort, queries that cannot be stored are still
+		// executed and those that do not parse are reported by the executor
+		if err := store.Put(hash, query); err != nil && executor.Logger != nil {
+			executor.Logger.Printf("graphql: cannot register persisted query %s: %v", hash, err)
+		}
+	}
+	return query, nil
+}
+
+// persistedQueryErrors returns the errors of a request whose query may not be
+// executed because it is not one of the persisted queries.
+func (executor *Executor) persistedQueryErrors(request string) []map[string]interface{} {
+	if !executor.OnlyPersistedQueries {
+		return nil
+	}
+	if executor.PersistedQueries != nil {
+		if _, ok := executor.PersistedQueries.Get(persisted.Hash(request)); ok {
+			return nil
+		}
+	}
+	return []map[string]interface{}{
+		executor.ErrorHandler(&Error{
+			Error: persistedQueryError("GraphQL Runtime Error: Only persisted queries may be executed", "PERSISTED_QUERY_REQUIRED"),
+		}),
+	}
+}
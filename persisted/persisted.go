@@ -0,0 +1,177 @@
+package persisted
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	. "github.com/playlyfe/go-graphql/language"
+)
+
+// Store holds persisted queries keyed by the hash of their text, as computed
+// by Hash.
+type Store interface {
+	// Get returns the query with the given hash, if any.
+	Get(hash string) (string, bool)
+	// Put registers the query under the given hash, which must be the Hash of
+	// the query.
+	Put(hash string, query string) error
+}
+
+// DEFAULT_MAX_SIZE is the number of queries the stores accept to register
+// unless their MaxSize is changed.
+const DEFAULT_MAX_SIZE = 10000
+
+// ErrStoreFull is returned when registering a query in a store holding its
+// maximum number of queries.
+var ErrStoreFull = errors.New("persisted: the store is full")
+
+// ErrHashMismatch is returned when registering a query under a hash that is
+// not its Hash.
+var ErrHashMismatch = errors.New("persisted: the hash does not match the query")
+
+// Hash returns the hex encoded SHA-256 hash of the query, which is the key of
+// the query in a store.
+func Hash(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(sum[:])
+}
+
+// MemoryStore is a store keeping its queries in memory.
+type MemoryStore struct {
+	// MaxSize is the number of queries above which Put fails with
+	// ErrStoreFull. Zero means no limit.
+	MaxSize int
+	queries map[string]string
+	sync.RWMutex
+}
+
+// NewMemoryStore returns an empty store kept in memory, holding at most
+// DEFAULT_MAX_SIZE queries.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		MaxSize: DEFAULT_MAX_SIZE,
+		queries: map[string]string{},
+	}
+}
+
+func (store *MemoryStore) Get(hash string) (string, bool) {
+	store.RLock()
+	defer store.RUnlock()
+	query, ok := store.queries[hash]
+	return query, ok
+}
+
+func (store *MemoryStore) Put(hash string, query string) error {
+	if hash != Hash(query) {
+		return ErrHashMismatch
+	}
+	store.Lock()
+	defer store.Unlock()
+	if _, ok := store.queries[hash]; !ok && store.MaxSize > 0 && len(store.queries) >= store.MaxSize {
+		return ErrStoreFull
+	}
+	store.queries[hash] = query
+	return nil
+}
+
+func (store *MemoryStore) size() int {
+	store.RLock()
+	defer store.RUnlock()
+	return len(store.queries)
+}
+
+// FileStore is a store backed by a directory of .graphql files, each holding
+// a query. The queries are loaded when the store is created and the queries
+// put in the store are written to the directory as <hash>.graphql.
+type FileStore struct {
+	Dir string
+	// MaxSize is the number of queries, including the ones loaded from Dir,
+	// above which Put fails with ErrStoreFull instead of writing a file. Zero
+	// means no limit.
+	MaxSize int
+	memory  *MemoryStore
+	mutex   sync.Mutex
+}
+
+// NewFileStore loads the queries of the .graphql files of dir, which must all
+// be valid GraphQL documents. The store registers at most DEFAULT_MAX_SIZE
+// queries.
+func NewFileStore(dir string) (*FileStore, error) {
+	store := &FileStore{
+		Dir:     dir,
+		MaxSize: DEFAULT_MAX_SIZE,
+		memory:  &MemoryStore{queries: map[string]string{}},
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.graphql"))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		query := string(content)
+		if err := parse(query); err != nil {
+			return nil, fmt.Errorf("%s: %s", file, err.Error())
+		}
+		store.memory.Put(Hash(query), query)
+	}
+	return store, nil
+}
+
+func (store *FileStore) Get(hash string) (string, bool) {
+	return store.memory.Get(hash)
+}
+
+// Put writes the query to the directory of the store, unless it is not a valid
+// GraphQL document or the store is full. As the hash names the file, it must be
+// the Hash of the query.
+func (store *FileStore) Put(hash string, query string) error {
+	if hash != Hash(query) {
+		return ErrHashMismatch
+	}
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	if _, ok := store.memory.Get(hash); ok {
+		return nil
+	}
+	if store.MaxSize > 0 && store.memory.size() >= store.MaxSize {
+		return ErrStoreFull
+	}
+	if err := parse(query); err != nil {
+		return err
+	}
+	// Write to a temporary file first so that a partially written query is
+	// never loaded
+	file, err := ioutil.TempFile(store.Dir, hash)
+	if err != nil {
+		return err
+	}
+	_, err = file.WriteString(query)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), filepath.Join(store.Dir, hash+".graphql"))
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+	return store.memory.Put(hash, query)
+}
+
+func parse(query string) error {
+	parser := &Parser{}
+	_, err := parser.Parse(&ParseParams{
+		Source: query,
+	})
+	return err
+}
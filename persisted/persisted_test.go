@@ -0,0 +1,89 @@
+package persisted
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPersisted(t *testing.T) {
+
+	Convey("Persisted: Stores queries by their hash", t, func() {
+		query := `{ hello }`
+		So(Hash(query), ShouldEqual, "001c3174e099bd72b729d0c0a529ba9f5a740c446e2a6e1d71b283cb84ec3065")
+
+		Convey("in memory", func() {
+			store := NewMemoryStore()
+			_, ok := store.Get(Hash(query))
+			So(ok, ShouldBeFalse)
+			So(store.Put(Hash(query), query), ShouldEqual, nil)
+			stored, ok := store.Get(Hash(query))
+			So(ok, ShouldBeTrue)
+			So(stored, ShouldEqual, query)
+
+			store.MaxSize = 1
+			So(store.Put(Hash(query), query), ShouldEqual, nil)
+			So(store.Put(Hash(`{ world }`), `{ world }`), ShouldEqual, ErrStoreFull)
+			So(store.Put(Hash(query), `{ world }`), ShouldEqual, ErrHashMismatch)
+			stored, _ = store.Get(Hash(query))
+			So(stored, ShouldEqual, query)
+			_, ok = store.Get(Hash(`{ world }`))
+			So(ok, ShouldBeFalse)
+		})
+
+		Convey("in a directory of .graphql files", func() {
+			dir, err := ioutil.TempDir("", "persisted")
+			So(err, ShouldEqual, nil)
+			defer os.RemoveAll(dir)
+			So(ioutil.WriteFile(filepath.Join(dir, "hello.graphql"), []byte(query), 0644), ShouldEqual, nil)
+			So(ioutil.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not a query {"), 0644), ShouldEqual, nil)
+
+			store, err := NewFileStore(dir)
+			So(err, ShouldEqual, nil)
+			stored, ok := store.Get(Hash(query))
+			So(ok, ShouldBeTrue)
+			So(stored, ShouldEqual, query)
+
+			other := `query Other { world }`
+			So(store.Put(Hash(other), other), ShouldEqual, nil)
+			content, err := ioutil.ReadFile(filepath.Join(dir, Hash(other)+".graphql"))
+			So(err, ShouldEqual, nil)
+			So(string(content), ShouldEqual, other)
+
+			reloaded, err := NewFileStore(dir)
+			So(err, ShouldEqual, nil)
+			_, ok = reloaded.Get(Hash(other))
+			So(ok, ShouldBeTrue)
+
+			So(store.Put(Hash("{ world"), "{ world"), ShouldNotEqual, nil)
+			_, err = os.Stat(filepath.Join(dir, Hash("{ world")+".graphql"))
+			So(os.IsNotExist(err), ShouldBeTrue)
+
+			store.MaxSize = 2
+			So(store.Put(Hash(`{ full }`), `{ full }`), ShouldEqual, ErrStoreFull)
+
+			store.MaxSize = 0
+			So(store.Put("../outside", `{ outside }`), ShouldEqual, ErrHashMismatch)
+			_, err = os.Stat(filepath.Join(dir, "..", "outside.graphql"))
+			So(os.IsNotExist(err), ShouldBeTrue)
+			_, err = os.Stat(filepath.Join(dir, Hash(`{ full }`)+".graphql"))
+			So(os.IsNotExist(err), ShouldBeTrue)
+		})
+
+		Convey("but rejects directories holding invalid queries", func() {
+			dir, err := ioutil.TempDir("", "persisted")
+			So(err, ShouldEqual, nil)
+			defer os.RemoveAll(dir)
+			file := filepath.Join(dir, "broken.graphql")
+			So(ioutil.WriteFile(file, []byte("{ hello"), 0644), ShouldEqual, nil)
+			_, err = NewFileStore(dir)
+			So(err, ShouldNotEqual, nil)
+			So(strings.HasPrefix(err.Error(), file+": "), ShouldBeTrue)
+		})
+	})
+
+}
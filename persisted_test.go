@@ -0,0 +1,110 @@
+package graphql
+
+import (
+	"testing"
+
+	"github.com/playlyfe/go-graphql/persisted"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPersistedQueries(t *testing.T) {
+
+	Convey("Execute: Resolves persisted queries", t, func() {
+		schema := `
+        type Query {
+            hello: String
+        }
+        `
+		resolvers := map[string]interface{}{}
+		resolvers["Query/hello"] = func(params *ResolveParams) (interface{}, error) {
+			return "world", nil
+		}
+		executor, err := NewExecutor(schema, "Query", "", resolvers)
+		So(err, ShouldEqual, nil)
+		query := `{ hello }`
+		hash := persisted.Hash(query)
+
+		Convey("unless no store is configured", func() {
+			_, err := executor.ResolvePersistedQuery("", hash)
+			So(err, ShouldResemble, &ExtendedError{
+				Message:    "PersistedQueryNotSupported",
+				Extensions: map[string]interface{}{"code": "PERSISTED_QUERY_NOT_SUPPORTED"},
+			})
+		})
+
+		Convey("registering the queries sent along with their hash", func() {
+			executor.PersistedQueries = persisted.NewMemoryStore()
+			_, err := executor.ResolvePersistedQuery("", hash)
+			So(err, ShouldResemble, &ExtendedError{
+				Message:    "PersistedQueryNotFound",
+				Extensions: map[string]interface{}{"code": "PERSISTED_QUERY_NOT_FOUND"},
+			})
+
+			resolved, err := executor.ResolvePersistedQuery(query, hash)
+			So(err, ShouldEqual, nil)
+			So(resolved, ShouldEqual, query)
+
+			resolved, err = executor.ResolvePersistedQuery("", hash)
+			So(err, ShouldEqual, nil)
+			So(resolved, ShouldEqual, query)
+
+			_, err = executor.ResolvePersistedQuery(`{ hello hello }`, hash)
+			So(err, ShouldResemble, &ExtendedError{
+				Message:    "provided sha does not match query",
+				Extensions: map[string]interface{}{"code": "INVALID_PERSISTED_QUERY_HASH"},
+			})
+		})
+
+		Convey("logging the queries that cannot be registered", func() {
+			store := persisted.NewMemoryStore()
+			store.MaxSize = 1
+			store.Put(hash, query)
+			executor.PersistedQueries = store
+			logger := &testLogger{}
+			executor.Logger = logger
+
+			other := `query Other { hello }`
+			resolved, err := executor.ResolvePersistedQuery(other, persisted.Hash(other))
+			So(err, ShouldEqual, nil)
+			So(resolved, ShouldEqual, other)
+			_, ok := store.Get(persisted.Hash(other))
+			So(ok, ShouldBeFalse)
+			So(logger.messages, ShouldResemble, []string{
+				"graphql: cannot register persisted query " + persisted.Hash(other) + ": persisted: the store is full",
+			})
+		})
+
+		Convey("and only executes the persisted queries in strict mode", func() {
+			store := persisted.NewMemoryStore()
+			store.Put(hash, query)
+			executor.PersistedQueries = store
+			executor.OnlyPersistedQueries = true
+
+			result, err := executor.Execute(nil, query, map[string]interface{}{}, "")
+			So(err, ShouldEqual, nil)
+			So(result, ShouldResemble, map[string]interface{}{
+				"data": map[string]interface{}{
+					"hello": "world",
+				},
+			})
+
+			other := `query Other { hello }`
+			_, err = executor.ResolvePersistedQuery(other, persisted.Hash(other))
+			So(err, ShouldEqual, nil)
+			_, ok := store.Get(persisted.Hash(other))
+			So(ok, ShouldBeFalse)
+
+			result, err = executor.Execute(nil, other, map[string]interface{}{}, "")
+			So(err, ShouldEqual, nil)
+			So(result, ShouldResemble, map[string]interface{}{
+				"errors": []map[string]interface{}{
+					map[string]interface{}{
+						"message":    "GraphQL Runtime Error: Only persisted queries may be executed",
+						"extensions": map[string]interface{}{"code": "PERSISTED_QUERY_REQUIRED"},
+					},
+				},
+			})
+		})
+	})
+
+}
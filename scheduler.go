@@ -0,0 +1,183 @@
+package graphql
+
+import (
+	"runtime/debug"
+	"sync"
+
+	. "github.com/playlyfe/go-graphql/language"
+)
+
+// DEFAULT_MAX_CONCURRENCY is a reasonable number of goroutines a request may
+// use to resolve fields and complete list items, as in
+// NewScheduler(DEFAULT_MAX_CONCURRENCY, 0).
+const DEFAULT_MAX_CONCURRENCY = 64
+
+// Scheduler decides which fields and list items are resolved concurrently. It
+// bounds the number of goroutines used by a request and by all the requests
+// sharing the scheduler. Work that cannot get a goroutine is run in the
+// goroutine that scheduled it, so the limits never block execution and the
+// order of the results is not affected.
+type Scheduler struct {
+	// MaxConcurrency is the maximum number of goroutines used by a single
+	// request. Zero means no limit.
+	MaxConcurrency int
+	// Serial resolves every field and list item in the goroutine executing
+	// the request.
+	Serial bool
+	// InlineLookups resolves the fields without a resolver, which are looked
+	// up on the source map or struct, and the items of lists of scalars and
+	// enums in the goroutine that scheduled them.
+	InlineLookups bool
+	global        chan struct{}
+}
+
+// NewScheduler returns a scheduler using at most maxConcurrency goroutines per
+// request and maxGlobalConcurrency goroutines across all the requests it
+// schedules. Zero means no limit.
+func NewScheduler(maxConcurrency int, maxGlobalConcurrency int) *Scheduler {
+	scheduler := &Scheduler{
+		MaxConcurrency: maxConcurrency,
+		InlineLookups:  true,
+	}
+	if maxGlobalConcurrency > 0 {
+		scheduler.global = make(chan struct{}, maxGlobalConcurrency)
+	}
+	return scheduler
+}
+
+// workers returns the semaphore bounding the goroutines of a request, or nil
+// if the number of goroutines is not limited.
+func (scheduler *Scheduler) workers() chan struct{} {
+	if scheduler == nil || scheduler.MaxConcurrency <= 0 {
+		return nil
+	}
+	return make(chan struct{}, scheduler.MaxConcurrency)
+}
+
+// acquire reserves a goroutine for the request without waiting, reporting
+// whether one was available.
+func (scheduler *Scheduler) acquire(workers chan struct{}) bool {
+	if workers != nil {
+		select {
+		case workers <- struct{}{}:
+		default:
+			return false
+		}
+	}
+	if scheduler != nil && scheduler.global != nil {
+		select {
+		case scheduler.global <- struct{}{}:
+		default:
+			if workers != nil {
+				<-workers
+			}
+			return false
+		}
+	}
+	return true
+}
+
+func (scheduler *Scheduler) release(workers chan struct{}) {
+	if scheduler != nil && scheduler.global != nil {
+		<-scheduler.global
+	}
+	if workers != nil {
+		<-workers
+	}
+}
+
+// batch is a group of tasks scheduled together, such as the fields of an
+// object or the items of a list. Tasks write their result to their own slot
+// so that the results keep the order in which the tasks were scheduled.
+type batch struct {
+	scheduler *Scheduler
+	workers   chan struct{}
+	wg        sync.WaitGroup
+	mutex     sync.Mutex
+	panics    []interface{}
+}
+
+func (scheduler *Scheduler) batch(reqCtx *RequestContext) *batch {
+	return &batch{
+		scheduler: scheduler,
+		workers:   reqCtx.workers,
+	}
+}
+
+// do runs the task in a new goroutine if one is available, or in the calling
+// goroutine if the task is to be run inline or the limits have been reached.
+func (b *batch) do(inline bool, task func()) {
+	if inline || (b.scheduler != nil && b.scheduler.Serial) || !b.scheduler.acquire(b.workers) {
+		task()
+		return
+	}
+	b.wg.Add(1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				if _, ok := r.(*taskPanic); !ok {
+					r = &taskPanic{value: r, stack: debug.Stack()}
+				}
+				b.mutex.Lock()
+				b.panics = append(b.panics, r)
+				b.mutex.Unlock()
+			}
+			b.scheduler.release(b.workers)
+			b.wg.Done()
+		}()
+		task()
+	}()
+}
+
+// wait blocks until every task of the batch has completed, and panics again in
+// the calling goroutine if one of the tasks panicked, with the stack of the
+// goroutine the task panicked in.
+func (b *batch) wait() {
+	b.wg.Wait()
+	if len(b.panics) > 0 {
+		panic(b.panics[0])
+	}
+}
+
+// inlineField reports whether the field is cheap enough to be resolved in the
+// goroutine that scheduled it, which is the case when it is looked up on the
+// source instead of being resolved by a resolver or a directive.
+func (executor *Executor) inlineField(objectType *ObjectTypeDefinition, source interface{}, field *Field) bool {
+	if executor.Scheduler == nil || !executor.Scheduler.InlineLookups {
+		return false
+	}
+	if _, ok := source.(func() (interface{}, error)); ok {
+		return false
+	}
+	if field.Name.Value == "__typename" {
+		return true
+	}
+	if _, ok := executor.Resolvers[objectType.Name.Value+"/"+field.Name.Value]; ok {
+		return false
+	}
+	fieldDefinition := objectType.FieldIndex[field.Name.Value]
+	if fieldDefinition == nil {
+		return true
+	}
+	for _, directive := range fieldDefinition.Directives {
+		if _, ok := executor.Directives[directive.Name.Value]; ok {
+			return false
+		}
+	}
+	return true
+}
+
+// inlineItems reports whether the items of a list of the given type are cheap
+// enough to be completed in the goroutine completing the list, which is the
+// case for scalars and enums.
+func (executor *Executor) inlineItems(itemType ASTNode) bool {
+	if executor.Scheduler == nil || !executor.Scheduler.InlineLookups {
+		return false
+	}
+	namedType := executor.resolveNamedType(itemType)
+	switch executor.Schema.Document.TypeIndex[namedType.Name.Value].(type) {
+	case *ScalarTypeDefinition, *EnumTypeDefinition:
+		return true
+	}
+	return false
+}
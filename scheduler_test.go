@@ -0,0 +1,109 @@
+package graphql
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestScheduler(t *testing.T) {
+
+	Convey("Execute: Schedules fields and list items", t, func() {
+		schema := `
+        type Item {
+            id: Int
+            name: String
+            slow: String
+        }
+
+        type Query {
+            items: [Item]
+            ids: [Int]
+        }
+        `
+		mutex := sync.Mutex{}
+		running := 0
+		peak := 0
+		resolvers := map[string]interface{}{}
+		resolvers["Query/items"] = func(params *ResolveParams) (interface{}, error) {
+			items := []interface{}{}
+			for i := 0; i < 50; i++ {
+				items = append(items, map[string]interface{}{"id": i, "name": "item"})
+			}
+			return items, nil
+		}
+		resolvers["Query/ids"] = func(params *ResolveParams) (interface{}, error) {
+			return []interface{}{1, 2, 3}, nil
+		}
+		resolvers["Item/slow"] = func(params *ResolveParams) (interface{}, error) {
+			mutex.Lock()
+			running++
+			if running > peak {
+				peak = running
+			}
+			mutex.Unlock()
+			time.Sleep(time.Millisecond)
+			mutex.Lock()
+			running--
+			mutex.Unlock()
+			return "slow", nil
+		}
+		executor, err := NewExecutor(schema, "Query", "", resolvers)
+		So(err, ShouldEqual, nil)
+
+		execute := func() map[string]interface{} {
+			result, err := executor.Execute(nil, `{ ids, items { id, name, slow } }`, map[string]interface{}{}, "")
+			So(err, ShouldEqual, nil)
+			So(result["errors"], ShouldEqual, nil)
+			return result
+		}
+		expectOrdered := func(result map[string]interface{}) {
+			data := result["data"].(map[string]interface{})
+			So(data["ids"], ShouldResemble, []interface{}{int32(1), int32(2), int32(3)})
+			items := data["items"].([]interface{})
+			So(len(items), ShouldEqual, 50)
+			for i, item := range items {
+				So(item, ShouldResemble, map[string]interface{}{"id": int32(i), "name": "item", "slow": "slow"})
+			}
+		}
+
+		Convey("bounds the goroutines used by a request", func() {
+			executor.Scheduler = NewScheduler(2, 0)
+			expectOrdered(execute())
+			// The goroutine executing the request runs the work the workers
+			// cannot take
+			So(peak, ShouldBeLessThanOrEqualTo, 3)
+			So(peak, ShouldBeGreaterThan, 1)
+		})
+
+		Convey("bounds the goroutines used by all requests", func() {
+			executor.Scheduler = NewScheduler(0, 2)
+			wg := sync.WaitGroup{}
+			for i := 0; i < 3; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					executor.Execute(nil, `{ items { slow } }`, map[string]interface{}{}, "")
+				}()
+			}
+			wg.Wait()
+			So(peak, ShouldBeLessThanOrEqualTo, 2+3)
+		})
+
+		Convey("resolves everything in the request goroutine in serial mode", func() {
+			executor.Scheduler = NewScheduler(0, 0)
+			executor.Scheduler.Serial = true
+			expectOrdered(execute())
+			So(peak, ShouldEqual, 1)
+		})
+
+		Convey("uses a goroutine per field and item without a scheduler", func() {
+			executor.Scheduler = nil
+			expectOrdered(execute())
+			So(peak, ShouldBeGreaterThan, 3)
+		})
+	})
+
+}
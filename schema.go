@@ -3,14 +3,28 @@ package graphql
 import (
 	"fmt"
 	"sort"
+	"strings"
 
 	. "github.com/playlyfe/go-graphql/language"
+	"github.com/playlyfe/go-graphql/validation"
 )
 
 type Schema struct {
-	Document     *Document
-	QueryRoot    *ObjectTypeDefinition
-	MutationRoot *ObjectTypeDefinition
+	Document         *Document
+	QueryRoot        *ObjectTypeDefinition
+	MutationRoot     *ObjectTypeDefinition
+	SubscriptionRoot *ObjectTypeDefinition
+}
+
+// SchemaErrors holds every error found in a schema definition by NewSchema.
+type SchemaErrors []*GraphQLError
+
+func (errs SchemaErrors) Error() string {
+	messages := []string{}
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "\n")
 }
 
 const INTROSPECTION_SCHEMA = `
@@ -24,6 +38,7 @@ type __Schema {
   types: [__Type!]!
   queryType: __Type!
   mutationType: __Type
+  subscriptionType: __Type
   directives: [__Directive!]!
 }
 
@@ -45,7 +60,7 @@ type __Type {
   enumValues(includeDeprecated: Boolean = false): [__EnumValue!]
 
   # INPUT_OBJECT only
-  inputFields: [__InputValue!]
+  inputFields(includeDeprecated: Boolean = false): [__InputValue!]
 
   # NON_NULL and LIST only
   ofType: __Type
@@ -54,7 +69,7 @@ type __Type {
 type __Field {
   name: String!
   description: String
-  args: [__InputValue!]!
+  args(includeDeprecated: Boolean = false): [__InputValue!]!
   type: __Type!
   isDeprecated: Boolean!
   deprecationReason: String
@@ -65,6 +80,8 @@ type __InputValue {
   description: String
   type: __Type!
   defaultValue: String
+  isDeprecated: Boolean!
+  deprecationReason: String
 }
 
 type __EnumValue {
@@ -88,13 +105,121 @@ enum __TypeKind {
 type __Directive {
   name: String!
   description: String
+  locations: [__DirectiveLocation!]!
   args: [__InputValue!]!
   onOperation: Boolean!
   onFragment: Boolean!
   onField: Boolean!
 }
+
+enum __DirectiveLocation {
+  QUERY
+  MUTATION
+  SUBSCRIPTION
+  FIELD
+  FRAGMENT_DEFINITION
+  FRAGMENT_SPREAD
+  INLINE_FRAGMENT
+  VARIABLE_DEFINITION
+  SCHEMA
+  SCALAR
+  OBJECT
+  FIELD_DEFINITION
+  ARGUMENT_DEFINITION
+  INTERFACE
+  UNION
+  ENUM
+  ENUM_VALUE
+  INPUT_OBJECT
+  INPUT_FIELD_DEFINITION
+}
 `
 
+func (executor *Executor) introspectInputValues(params *ResolveParams, inputValueDefinitions []*InputValueDefinition, d ...int) ([]map[string]interface{}, error) {
+	inputValues := []map[string]interface{}{}
+	for _, inputValueDefinition := range inputValueDefinitions {
+		defaultValue, err := executor.valueFromAST(params.Context, inputValueDefinition.DefaultValue, executor.resolveNamedType(inputValueDefinition.Type), nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		inputValues = append(inputValues, map[string]interface{}{
+			"name":              inputValueDefinition.Name.Value,
+			"description":       inputValueDefinition.Description,
+			"type":              executor.introspectType(params, inputValueDefinition.Type, d...),
+			"defaultValue":      defaultValue,
+			"isDeprecated":      inputValueDefinition.IsDeprecated,
+			"deprecationReason": inputValueDefinition.DeprecationReason,
+		})
+	}
+	return inputValues, nil
+}
+
+// introspectDirectives returns the specified directives followed by the
+// directives defined by the schema in the order they were defined. The schema
+// cannot redefine the specified directives, so no directive is listed twice.
+func (executor *Executor) introspectDirectives(params *ResolveParams) ([]map[string]interface{}, error) {
+	directiveDefinitions := append([]*DirectiveDefinition{}, validation.SpecifiedDirectives...)
+	for _, definition := range executor.Schema.Document.Definitions {
+		if directiveDefinition, ok := definition.(*DirectiveDefinition); ok {
+			directiveDefinitions = append(directiveDefinitions, directiveDefinition)
+		}
+	}
+	directives := []map[string]interface{}{}
+	for _, directiveDefinition := range directiveDefinitions {
+		args, err := executor.introspectInputValues(params, directiveDefinition.Arguments)
+		if err != nil {
+			return nil, err
+		}
+		locations := []string{}
+		onOperation, onFragment, onField := false, false, false
+		for _, location := range directiveDefinition.Locations {
+			locations = append(locations, location.Value)
+			switch location.Value {
+			case "QUERY", "MUTATION", "SUBSCRIPTION":
+				onOperation = true
+			case "FRAGMENT_DEFINITION", "FRAGMENT_SPREAD", "INLINE_FRAGMENT":
+				onFragment = true
+			case "FIELD":
+				onField = true
+			}
+		}
+		directives = append(directives, map[string]interface{}{
+			"name":        directiveDefinition.Name.Value,
+			"description": directiveDefinition.Description,
+			"locations":   locations,
+			"args":        args,
+			"onOperation": onOperation,
+			"onFragment":  onFragment,
+			"onField":     onField,
+		})
+	}
+	return directives, nil
+}
+
+// introspectedInputValues returns the input values introspected under the key
+// of the source, leaving out the deprecated values unless includeDeprecated is
+// set.
+func introspectedInputValues(params *ResolveParams, key string) interface{} {
+	source, ok := params.Source.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	inputValues, ok := source[key].([]map[string]interface{})
+	if !ok {
+		return nil
+	}
+	if includeDeprecated, _ := params.Args["includeDeprecated"].(bool); includeDeprecated {
+		return inputValues
+	}
+	result := []map[string]interface{}{}
+	for _, inputValue := range inputValues {
+		if isDeprecated, _ := inputValue["isDeprecated"].(bool); !isDeprecated {
+			result = append(result, inputValue)
+		}
+	}
+	return result
+}
+
 func (executor *Executor) introspectType(params *ResolveParams, typeValue interface{}, d ...int) map[string]interface{} {
 	depth := 0
 	if len(d) > 0 {
@@ -135,18 +260,9 @@ func (executor *Executor) introspectType(params *ResolveParams, typeValue interf
 		case *InputObjectTypeDefinition:
 			typeInfo["kind"] = "INPUT_OBJECT"
 			typeInfo["description"] = __type.Description
-			inputFields := []map[string]interface{}{}
-			for _, inputValueDefinition := range __type.Fields {
-				defaultValue, err := executor.valueFromAST(params.Context, inputValueDefinition.DefaultValue, executor.resolveNamedType(inputValueDefinition.Type), nil, nil)
-				if err != nil {
-					panic(err)
-				}
-				inputFields = append(inputFields, map[string]interface{}{
-					"name":         inputValueDefinition.Name.Value,
-					"description":  inputValueDefinition.Description,
-					"type":         executor.introspectType(params, inputValueDefinition.Type, depth),
-					"defaultValue": defaultValue,
-				})
+			inputFields, err := executor.introspectInputValues(params, __type.Fields, depth)
+			if err != nil {
+				panic(err)
 			}
 			typeInfo["inputFields"] = inputFields
 		case *InterfaceTypeDefinition:
@@ -183,6 +299,41 @@ func typenameResolver(typename string) func(params *ResolveParams) (interface{},
 	}
 }
 
+// SetSubscriptionRoot sets the object type whose fields are the entry points
+// for subscription operations.
+func (schema *Schema) SetSubscriptionRoot(subscriptionRoot string) error {
+	objectType, ok := schema.Document.ObjectTypeIndex[subscriptionRoot]
+	if !ok {
+		return &GraphQLError{
+			Message: "The SubscriptionRoot could not be found",
+		}
+	}
+	schema.SubscriptionRoot = objectType
+	return nil
+}
+
+// operationRootTypeNames returns the names of the root types declared by the
+// schema definition of the document, or the conventional Query, Mutation and
+// Subscription names if the document has no schema definition.
+func operationRootTypeNames(document *Document) map[string]string {
+	if document.SchemaDefinition == nil {
+		return map[string]string{
+			"query":        "Query",
+			"mutation":     "Mutation",
+			"subscription": "Subscription",
+		}
+	}
+	rootTypeNames := map[string]string{}
+	for _, operationType := range document.SchemaDefinition.OperationTypes {
+		rootTypeNames[operationType.Operation] = operationType.Type.Name.Value
+	}
+	return rootTypeNames
+}
+
+// NewSchema builds the schema from its definition in the schema language. The
+// root types are named by the schema definition or use the conventional
+// Query, Mutation and Subscription names; a non empty queryRoot or
+// mutationRoot takes precedence over both.
 func NewSchema(schemaDefinition string, queryRoot string, mutationRoot string) (*Schema, map[string]interface{}, error) {
 	parser := &Parser{}
 	schema := &Schema{}
@@ -193,22 +344,36 @@ func NewSchema(schemaDefinition string, queryRoot string, mutationRoot string) (
 	if err != nil {
 		return nil, nil, err
 	}
+	errs := extendTypes(ast)
+	errs = append(errs, validation.ValidateSchema(ast)...)
+	if len(errs) > 0 {
+		return nil, nil, SchemaErrors(errs)
+	}
 	for _, definition := range ast.Definitions {
 		switch operationDefinition := definition.(type) {
 		case *ObjectTypeDefinition:
-			if operationDefinition.Name.Value == queryRoot {
-				schema.QueryRoot = operationDefinition
-			} else if operationDefinition.Name.Value == mutationRoot {
-				schema.MutationRoot = operationDefinition
-			}
 			resolvers[operationDefinition.Name.Value+"/__typename"] = typenameResolver(operationDefinition.Name.Value)
 		}
 	}
+	rootTypeNames := operationRootTypeNames(ast)
+	if queryRoot != "" {
+		rootTypeNames["query"] = queryRoot
+	}
+	if mutationRoot != "" {
+		rootTypeNames["mutation"] = mutationRoot
+	}
+	schema.QueryRoot = ast.ObjectTypeIndex[rootTypeNames["query"]]
+	schema.MutationRoot = ast.ObjectTypeIndex[rootTypeNames["mutation"]]
+	schema.SubscriptionRoot = ast.ObjectTypeIndex[rootTypeNames["subscription"]]
 	if schema.QueryRoot == nil {
 		return nil, nil, &GraphQLError{
 			Message: "The QueryRoot could not be found",
 		}
 	}
+	queryRoot = schema.QueryRoot.Name.Value
+	if schema.MutationRoot != nil {
+		mutationRoot = schema.MutationRoot.Name.Value
+	}
 
 	// Add implict fields to query root
 	schemaField := &FieldDefinition{
@@ -265,56 +430,20 @@ func NewSchema(schemaDefinition string, queryRoot string, mutationRoot string) (
 
 		result := map[string]interface{}{
 			"queryType": executor.introspectType(params, queryRoot),
-			"directives": []map[string]interface{}{
-				{
-					"name":        "skip",
-					"description": "Conditionally exclude a field or fragment during execution",
-					"args": []map[string]interface{}{
-						{
-							"name":        "if",
-							"description": "The condition value",
-							"type": map[string]interface{}{
-								"kind": "NON_NULL",
-								"ofType": map[string]interface{}{
-									"kind":        "SCALAR",
-									"name":        "Boolean",
-									"description": "A boolean value representing `true` or `false`",
-								},
-							},
-						},
-					},
-					"onOperation": false,
-					"onField":     true,
-					"onFragment":  true,
-				},
-				{
-					"name":        "include",
-					"description": "Conditionally include a field or fragment during execution",
-					"args": []map[string]interface{}{
-						{
-							"name":        "if",
-							"description": "The condition value",
-							"type": map[string]interface{}{
-								"kind": "NON_NULL",
-								"ofType": map[string]interface{}{
-									"kind":        "SCALAR",
-									"name":        "Boolean",
-									"description": "A boolean value representing `true` or `false`",
-								},
-							},
-						},
-					},
-					"onOperation": false,
-					"onField":     true,
-					"onFragment":  true,
-				},
-			},
 		}
+		directives, err := executor.introspectDirectives(params)
+		if err != nil {
+			return nil, err
+		}
+		result["directives"] = directives
 
 		//TODO: better handling for empty mutationRoot
 		if schema.MutationRoot != nil {
 			result["mutationType"] = executor.introspectType(params, mutationRoot)
 		}
+		if schema.SubscriptionRoot != nil {
+			result["subscriptionType"] = executor.introspectType(params, schema.SubscriptionRoot.Name.Value)
+		}
 
 		return result, nil
 	}
@@ -326,7 +455,7 @@ func NewSchema(schemaDefinition string, queryRoot string, mutationRoot string) (
 		}
 		sort.Strings(typeNames)
 		for _, typeName := range typeNames {
-			if typeName == "__Schema" || typeName == "__Type" || typeName == "__Field" || typeName == "__InputValue" || typeName == "__EnumValue" || typeName == "__TypeKind" || typeName == "__Directive" {
+			if typeName == "__Schema" || typeName == "__Type" || typeName == "__Field" || typeName == "__InputValue" || typeName == "__EnumValue" || typeName == "__TypeKind" || typeName == "__Directive" || typeName == "__DirectiveLocation" {
 				continue
 			}
 			types = append(types, params.Executor.introspectType(params, typeName))
@@ -354,18 +483,9 @@ func NewSchema(schemaDefinition string, queryRoot string, mutationRoot string) (
 							continue
 						}
 					}
-					args := []map[string]interface{}{}
-					for _, inputValueDefinition := range fieldDefinition.Arguments {
-						defaultValue, err := executor.valueFromAST(params.Context, inputValueDefinition.DefaultValue, executor.resolveNamedType(inputValueDefinition.Type), nil, nil)
-						if err != nil {
-							return nil, err
-						}
-						args = append(args, map[string]interface{}{
-							"name":         inputValueDefinition.Name.Value,
-							"description":  inputValueDefinition.Description,
-							"type":         executor.introspectType(params, inputValueDefinition.Type),
-							"defaultValue": defaultValue,
-						})
+					args, err := executor.introspectInputValues(params, fieldDefinition.Arguments)
+					if err != nil {
+						return nil, err
 					}
 					fields = append(fields, map[string]interface{}{
 						"name":              fieldDefinition.Name.Value,
@@ -385,18 +505,9 @@ func NewSchema(schemaDefinition string, queryRoot string, mutationRoot string) (
 					if !includeDeprecated && fieldDefinition.IsDeprecated {
 						continue
 					}
-					args := []map[string]interface{}{}
-					for _, inputValueDefinition := range fieldDefinition.Arguments {
-						defaultValue, err := executor.valueFromAST(params.Context, inputValueDefinition.DefaultValue, executor.resolveNamedType(inputValueDefinition.Type), nil, nil)
-						if err != nil {
-							return nil, err
-						}
-						args = append(args, map[string]interface{}{
-							"name":         inputValueDefinition.Name.Value,
-							"description":  inputValueDefinition.Description,
-							"type":         executor.introspectType(params, inputValueDefinition.Type),
-							"defaultValue": defaultValue,
-						})
+					args, err := executor.introspectInputValues(params, fieldDefinition.Arguments)
+					if err != nil {
+						return nil, err
 					}
 					fields = append(fields, map[string]interface{}{
 						"name":              fieldDefinition.Name.Value,
@@ -429,6 +540,12 @@ func NewSchema(schemaDefinition string, queryRoot string, mutationRoot string) (
 		}
 		return nil, nil
 	}
+	resolvers["__Type/inputFields"] = func(params *ResolveParams) (interface{}, error) {
+		return introspectedInputValues(params, "inputFields"), nil
+	}
+	resolvers["__Field/args"] = func(params *ResolveParams) (interface{}, error) {
+		return introspectedInputValues(params, "args"), nil
+	}
 	resolvers["__Type/enumValues"] = func(params *ResolveParams) (interface{}, error) {
 		if typeInfo, ok := params.Source.(map[string]interface{}); ok {
 			typeName := typeInfo["name"].(string)
@@ -0,0 +1,255 @@
+package graphql
+
+import (
+	. "github.com/playlyfe/go-graphql/language"
+	"github.com/playlyfe/go-graphql/validation"
+)
+
+// extendTypes merges the type and schema extensions of the document into the
+// definitions they extend, in the order the extensions are defined. The
+// extension definitions are left in the document while the extended types gain
+// their fields, enum values, interfaces, union members and directives. An
+// extension is applied up to its first conflict and every conflicting
+// extension is reported.
+func extendTypes(document *Document) []*GraphQLError {
+	errs := []*GraphQLError{}
+	for _, definition := range document.Definitions {
+		var err *GraphQLError
+		switch extension := definition.(type) {
+		case *TypeExtensionDefinition:
+			err = extendType(document, extension)
+		case *SchemaExtensionDefinition:
+			err = extendSchema(document, extension.Definition)
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+func extendType(document *Document, extension *TypeExtensionDefinition) *GraphQLError {
+	switch definition := extension.Definition.(type) {
+	case *ObjectTypeDefinition:
+		return extendObjectType(document, definition)
+	case *InterfaceTypeDefinition:
+		return extendInterfaceType(document, definition)
+	case *UnionTypeDefinition:
+		return extendUnionType(document, definition)
+	case *ScalarTypeDefinition:
+		return extendScalarType(document, definition)
+	case *EnumTypeDefinition:
+		return extendEnumType(document, definition)
+	case *InputObjectTypeDefinition:
+		return extendInputObjectType(document, definition)
+	}
+	return nil
+}
+
+// extendSchema adds the root types and directives of the extension to the
+// schema definition. A schema without a definition is extended from the root
+// types it has by convention.
+func extendSchema(document *Document, extension *SchemaDefinition) *GraphQLError {
+	schemaDefinition := document.SchemaDefinition
+	if schemaDefinition == nil {
+		schemaDefinition = &SchemaDefinition{}
+		rootTypeNames := operationRootTypeNames(document)
+		for _, operation := range []string{"query", "mutation", "subscription"} {
+			if objectType, ok := document.ObjectTypeIndex[rootTypeNames[operation]]; ok {
+				schemaDefinition.OperationTypes = append(schemaDefinition.OperationTypes, &OperationTypeDefinition{
+					Operation: operation,
+					Type:      &NamedType{Name: objectType.Name},
+				})
+			}
+		}
+		document.SchemaDefinition = schemaDefinition
+	}
+	for _, operationType := range extension.OperationTypes {
+		for _, existingOperationType := range schemaDefinition.OperationTypes {
+			if existingOperationType.Operation == operationType.Operation {
+				return validation.NewError("Schema", operationType.LOC, "Type for %s already defined in the schema. It cannot be redefined", operationType.Operation)
+			}
+		}
+		schemaDefinition.OperationTypes = append(schemaDefinition.OperationTypes, operationType)
+	}
+	var err *GraphQLError
+	schemaDefinition.Directives, schemaDefinition.DirectiveIndex, err = extendDirectives(&Name{Value: "schema"}, schemaDefinition.Directives, schemaDefinition.DirectiveIndex, extension.Directives)
+	return err
+}
+
+// extendedType returns the definition of the type being extended, or an error
+// if it does not exist.
+func extendedType(document *Document, keyword string, name *Name) (ASTNode, *GraphQLError) {
+	definition, ok := document.TypeIndex[name.Value]
+	if !ok {
+		return nil, validation.NewError("Schema", name.LOC, "Cannot extend %s %q because it is not defined", keyword, name.Value)
+	}
+	if _, ok := definition.(*FragmentDefinition); ok {
+		return nil, validation.NewError("Schema", name.LOC, "Cannot extend %s %q because it is not defined", keyword, name.Value)
+	}
+	return definition, nil
+}
+
+func extendObjectType(document *Document, extension *ObjectTypeDefinition) *GraphQLError {
+	definition, err := extendedType(document, "type", extension.Name)
+	if err != nil {
+		return err
+	}
+	objectType, ok := definition.(*ObjectTypeDefinition)
+	if !ok {
+		return validation.NewError("Schema", extension.Name.LOC, "Cannot extend type %q because it is not an object type", extension.Name.Value)
+	}
+	for _, namedType := range extension.Interfaces {
+		for _, implementedInterface := range objectType.Interfaces {
+			if implementedInterface.Name.Value == namedType.Name.Value {
+				return validation.NewError("Schema", namedType.LOC, "Type %q already implements %q. It cannot also be implemented in this type extension", objectType.Name.Value, namedType.Name.Value)
+			}
+		}
+		objectType.Interfaces = append(objectType.Interfaces, namedType)
+		if document.PossibleTypesIndex == nil {
+			document.PossibleTypesIndex = map[string][]*ObjectTypeDefinition{}
+		}
+		document.PossibleTypesIndex[namedType.Name.Value] = append(document.PossibleTypesIndex[namedType.Name.Value], objectType)
+	}
+	objectType.Fields, objectType.FieldIndex, err = extendFields(objectType.Name, objectType.Fields, objectType.FieldIndex, extension.Fields)
+	if err != nil {
+		return err
+	}
+	objectType.Directives, objectType.DirectiveIndex, err = extendDirectives(objectType.Name, objectType.Directives, objectType.DirectiveIndex, extension.Directives)
+	return err
+}
+
+func extendInterfaceType(document *Document, extension *InterfaceTypeDefinition) *GraphQLError {
+	definition, err := extendedType(document, "interface", extension.Name)
+	if err != nil {
+		return err
+	}
+	interfaceType, ok := definition.(*InterfaceTypeDefinition)
+	if !ok {
+		return validation.NewError("Schema", extension.Name.LOC, "Cannot extend interface %q because it is not an interface", extension.Name.Value)
+	}
+	for _, field := range extension.Fields {
+		for _, existingField := range interfaceType.Fields {
+			if existingField.Name.Value == field.Name.Value {
+				return validation.NewError("Schema", field.Name.LOC, "Field \"%s.%s\" already exists in the schema. It cannot also be defined in this type extension", interfaceType.Name.Value, field.Name.Value)
+			}
+		}
+		interfaceType.Fields = append(interfaceType.Fields, field)
+	}
+	interfaceType.Directives, interfaceType.DirectiveIndex, err = extendDirectives(interfaceType.Name, interfaceType.Directives, interfaceType.DirectiveIndex, extension.Directives)
+	return err
+}
+
+func extendUnionType(document *Document, extension *UnionTypeDefinition) *GraphQLError {
+	definition, err := extendedType(document, "union", extension.Name)
+	if err != nil {
+		return err
+	}
+	unionType, ok := definition.(*UnionTypeDefinition)
+	if !ok {
+		return validation.NewError("Schema", extension.Name.LOC, "Cannot extend union %q because it is not a union", extension.Name.Value)
+	}
+	for _, namedType := range extension.Types {
+		for _, possibleType := range unionType.Types {
+			if possibleType.Name.Value == namedType.Name.Value {
+				return validation.NewError("Schema", namedType.LOC, "Union %q already includes %q. It cannot also be included in this type extension", unionType.Name.Value, namedType.Name.Value)
+			}
+		}
+		objectType, ok := document.ObjectTypeIndex[namedType.Name.Value]
+		if !ok {
+			return validation.NewError("Schema", namedType.LOC, "Union %q can only include Object types, it cannot include %q", unionType.Name.Value, namedType.Name.Value)
+		}
+		unionType.Types = append(unionType.Types, namedType)
+		if document.PossibleTypesIndex == nil {
+			document.PossibleTypesIndex = map[string][]*ObjectTypeDefinition{}
+		}
+		document.PossibleTypesIndex[unionType.Name.Value] = append(document.PossibleTypesIndex[unionType.Name.Value], objectType)
+	}
+	unionType.Directives, unionType.DirectiveIndex, err = extendDirectives(unionType.Name, unionType.Directives, unionType.DirectiveIndex, extension.Directives)
+	return err
+}
+
+func extendScalarType(document *Document, extension *ScalarTypeDefinition) *GraphQLError {
+	definition, err := extendedType(document, "scalar", extension.Name)
+	if err != nil {
+		return err
+	}
+	scalarType, ok := definition.(*ScalarTypeDefinition)
+	if !ok {
+		return validation.NewError("Schema", extension.Name.LOC, "Cannot extend scalar %q because it is not a scalar", extension.Name.Value)
+	}
+	scalarType.Directives, scalarType.DirectiveIndex, err = extendDirectives(scalarType.Name, scalarType.Directives, scalarType.DirectiveIndex, extension.Directives)
+	return err
+}
+
+func extendEnumType(document *Document, extension *EnumTypeDefinition) *GraphQLError {
+	definition, err := extendedType(document, "enum", extension.Name)
+	if err != nil {
+		return err
+	}
+	enumType, ok := definition.(*EnumTypeDefinition)
+	if !ok {
+		return validation.NewError("Schema", extension.Name.LOC, "Cannot extend enum %q because it is not an enum", extension.Name.Value)
+	}
+	for _, value := range extension.Values {
+		for _, existingValue := range enumType.Values {
+			if existingValue.Name.Value == value.Name.Value {
+				return validation.NewError("Schema", value.Name.LOC, "Enum value \"%s.%s\" already exists in the schema. It cannot also be defined in this type extension", enumType.Name.Value, value.Name.Value)
+			}
+		}
+		enumType.Values = append(enumType.Values, value)
+	}
+	enumType.Directives, enumType.DirectiveIndex, err = extendDirectives(enumType.Name, enumType.Directives, enumType.DirectiveIndex, extension.Directives)
+	return err
+}
+
+func extendInputObjectType(document *Document, extension *InputObjectTypeDefinition) *GraphQLError {
+	definition, err := extendedType(document, "input", extension.Name)
+	if err != nil {
+		return err
+	}
+	inputObjectType, ok := definition.(*InputObjectTypeDefinition)
+	if !ok {
+		return validation.NewError("Schema", extension.Name.LOC, "Cannot extend input %q because it is not an input object", extension.Name.Value)
+	}
+	for _, field := range extension.Fields {
+		if _, ok := inputObjectType.FieldIndex[field.Name.Value]; ok {
+			return validation.NewError("Schema", field.Name.LOC, "Field \"%s.%s\" already exists in the schema. It cannot also be defined in this type extension", inputObjectType.Name.Value, field.Name.Value)
+		}
+		if inputObjectType.FieldIndex == nil {
+			inputObjectType.FieldIndex = map[string]*InputValueDefinition{}
+		}
+		inputObjectType.Fields = append(inputObjectType.Fields, field)
+		inputObjectType.FieldIndex[field.Name.Value] = field
+	}
+	inputObjectType.Directives, inputObjectType.DirectiveIndex, err = extendDirectives(inputObjectType.Name, inputObjectType.Directives, inputObjectType.DirectiveIndex, extension.Directives)
+	return err
+}
+
+func extendFields(typeName *Name, fields []*FieldDefinition, fieldIndex map[string]*FieldDefinition, extensionFields []*FieldDefinition) ([]*FieldDefinition, map[string]*FieldDefinition, *GraphQLError) {
+	for _, field := range extensionFields {
+		if _, ok := fieldIndex[field.Name.Value]; ok {
+			return fields, fieldIndex, validation.NewError("Schema", field.Name.LOC, "Field \"%s.%s\" already exists in the schema. It cannot also be defined in this type extension", typeName.Value, field.Name.Value)
+		}
+		if fieldIndex == nil {
+			fieldIndex = map[string]*FieldDefinition{}
+		}
+		fields = append(fields, field)
+		fieldIndex[field.Name.Value] = field
+	}
+	return fields, fieldIndex, nil
+}
+
+func extendDirectives(typeName *Name, directives []*Directive, directiveIndex map[string]*Directive, extensionDirectives []*Directive) ([]*Directive, map[string]*Directive, *GraphQLError) {
+	for _, directive := range extensionDirectives {
+		if _, ok := directiveIndex[directive.Name.Value]; ok {
+			return directives, directiveIndex, validation.NewError("Schema", directive.LOC, "Directive %q is already applied to %q. It cannot also be applied in this type extension", directive.Name.Value, typeName.Value)
+		}
+		if directiveIndex == nil {
+			directiveIndex = map[string]*Directive{}
+		}
+		directives = append(directives, directive)
+		directiveIndex[directive.Name.Value] = directive
+	}
+	return directives, directiveIndex, nil
+}
@@ -0,0 +1,248 @@
+package graphql
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSchemaExtension(t *testing.T) {
+
+	Convey("NewSchema: Applies type extensions", t, func() {
+		schema := `
+        interface Named {
+            name: String
+        }
+
+        type Dog {
+            name: String
+        }
+
+        type Cat {
+            name: String
+        }
+
+        union Pet = Dog
+
+        enum Size { SMALL }
+
+        input Filter {
+            size: Size
+        }
+
+        scalar Date
+
+        directive @tag on INTERFACE | SCALAR
+
+        type QueryRoot {
+            pets(filter: Filter): [Pet]
+        }
+
+        extend type Dog implements Named {
+            nickname: String
+            barks: Boolean
+        }
+
+        extend interface Named @tag {
+            nickname: String
+        }
+
+        extend union Pet = Cat
+
+        extend enum Size { LARGE }
+
+        extend input Filter {
+            barks: Boolean
+        }
+
+        extend scalar Date @tag
+
+        extend type QueryRoot {
+            named: [Named]
+        }
+
+        extend type Cat implements Named {
+            nickname: String
+        }
+        `
+		pets := []interface{}{
+			map[string]interface{}{"__typename": "Dog", "name": "Odie", "barks": true, "size": "LARGE"},
+			map[string]interface{}{"__typename": "Cat", "name": "Garfield", "nickname": "Fat cat", "size": "LARGE"},
+		}
+		resolvers := map[string]interface{}{
+			"QueryRoot/pets": func(params *ResolveParams) (interface{}, error) {
+				filter, _ := params.Args["filter"].(map[string]interface{})
+				result := []interface{}{}
+				for _, pet := range pets {
+					if filter["size"] != nil && pet.(map[string]interface{})["size"] != filter["size"] {
+						continue
+					}
+					if barks, ok := filter["barks"]; ok && pet.(map[string]interface{})["barks"] != barks {
+						continue
+					}
+					result = append(result, pet)
+				}
+				return result, nil
+			},
+			"QueryRoot/named": func(params *ResolveParams) (interface{}, error) {
+				return pets, nil
+			},
+		}
+		executor, err := NewExecutor(schema, "QueryRoot", "", resolvers)
+		So(err, ShouldEqual, nil)
+		executor.ResolveType = func(value interface{}) string {
+			return value.(map[string]interface{})["__typename"].(string)
+		}
+
+		Convey("resolves fields added by extensions", func() {
+			result, err := executor.Execute(nil, `{
+                pets(filter: { size: LARGE, barks: true }) {
+                    ... on Dog { name, barks }
+                }
+                named {
+                    __typename
+                    name
+                    ... on Cat { nickname }
+                }
+            }`, map[string]interface{}{}, "")
+			So(err, ShouldEqual, nil)
+			So(result, ShouldResemble, map[string]interface{}{
+				"data": map[string]interface{}{
+					"pets": []interface{}{
+						map[string]interface{}{"name": "Odie", "barks": true},
+					},
+					"named": []interface{}{
+						map[string]interface{}{"__typename": "Dog", "name": "Odie"},
+						map[string]interface{}{"__typename": "Cat", "name": "Garfield", "nickname": "Fat cat"},
+					},
+				},
+			})
+		})
+
+		Convey("introspects the extended types", func() {
+			result, err := executor.Execute(nil, `{
+                pet: __type(name: "Pet") { possibleTypes { name } }
+                named: __type(name: "Named") { fields { name }, possibleTypes { name } }
+                size: __type(name: "Size") { enumValues { name } }
+                filter: __type(name: "Filter") { inputFields { name } }
+            }`, map[string]interface{}{}, "")
+			So(err, ShouldEqual, nil)
+			So(result, ShouldResemble, map[string]interface{}{
+				"data": map[string]interface{}{
+					"pet": map[string]interface{}{
+						"possibleTypes": []interface{}{
+							map[string]interface{}{"name": "Dog"},
+							map[string]interface{}{"name": "Cat"},
+						},
+					},
+					"named": map[string]interface{}{
+						"fields": []interface{}{
+							map[string]interface{}{"name": "name"},
+							map[string]interface{}{"name": "nickname"},
+						},
+						"possibleTypes": []interface{}{
+							map[string]interface{}{"name": "Dog"},
+							map[string]interface{}{"name": "Cat"},
+						},
+					},
+					"size": map[string]interface{}{
+						"enumValues": []interface{}{
+							map[string]interface{}{"name": "SMALL"},
+							map[string]interface{}{"name": "LARGE"},
+						},
+					},
+					"filter": map[string]interface{}{
+						"inputFields": []interface{}{
+							map[string]interface{}{"name": "size"},
+							map[string]interface{}{"name": "barks"},
+						},
+					},
+				},
+			})
+		})
+
+		Convey("prints the extended types", func() {
+			output := executor.PrintSchema()
+			So(output, ShouldContainSubstring, "type Dog implements Named {\n  name: String\n  nickname: String\n  barks: Boolean\n}\n")
+			So(output, ShouldContainSubstring, "interface Named @tag {\n  name: String\n  nickname: String\n}\n")
+			So(output, ShouldContainSubstring, "union Pet = Dog | Cat\n")
+			So(output, ShouldContainSubstring, "scalar Date @tag\n")
+			So(output, ShouldNotContainSubstring, "extend")
+		})
+	})
+
+	Convey("NewSchema: Rejects invalid type extensions", t, func() {
+
+		newSchemaError := func(extension string) string {
+			_, _, err := NewSchema(`
+type QueryRoot {
+  name: String
+}
+interface Named {
+  name: String
+}
+union Result = QueryRoot
+enum Size { SMALL }
+input Filter { size: Size }
+scalar Date @tag
+directive @tag on SCALAR
+`+extension, "QueryRoot", "")
+			So(err, ShouldNotEqual, nil)
+			errs := err.(SchemaErrors)
+			So(len(errs), ShouldEqual, 1)
+			return errs[0].Message
+		}
+
+		Convey("extensions of unknown types", func() {
+			So(newSchemaError(`extend type Query { other: String }`), ShouldEqual, `GraphQL Schema Error (13:13) Cannot extend type "Query" because it is not defined`)
+		})
+
+		Convey("extensions of a different kind of type", func() {
+			So(newSchemaError(`extend input Size { other: String }`), ShouldEqual, `GraphQL Schema Error (13:14) Cannot extend input "Size" because it is not an input object`)
+			So(newSchemaError(`extend type Named { other: String }`), ShouldEqual, `GraphQL Schema Error (13:13) Cannot extend type "Named" because it is not an object type`)
+		})
+
+		Convey("union members that are not object types", func() {
+			So(newSchemaError(`extend union Result = Size`), ShouldEqual, `GraphQL Schema Error (13:23) Union "Result" can only include Object types, it cannot include "Size"`)
+			So(newSchemaError(`extend union Result = Missing`), ShouldEqual, `GraphQL Schema Error (13:23) Union "Result" can only include Object types, it cannot include "Missing"`)
+		})
+
+		Convey("fields that already exist", func() {
+			So(newSchemaError(`extend type QueryRoot { name: String }`), ShouldEqual, `GraphQL Schema Error (13:25) Field "QueryRoot.name" already exists in the schema. It cannot also be defined in this type extension`)
+			So(newSchemaError(`extend interface Named { name: String }`), ShouldEqual, `GraphQL Schema Error (13:26) Field "Named.name" already exists in the schema. It cannot also be defined in this type extension`)
+			So(newSchemaError(`extend input Filter { size: Size }`), ShouldEqual, `GraphQL Schema Error (13:23) Field "Filter.size" already exists in the schema. It cannot also be defined in this type extension`)
+		})
+
+		Convey("fields defined by several extensions", func() {
+			So(newSchemaError("extend type QueryRoot { other: String }\nextend type QueryRoot { other: Int }"), ShouldEqual, `GraphQL Schema Error (14:25) Field "QueryRoot.other" already exists in the schema. It cannot also be defined in this type extension`)
+		})
+
+		Convey("enum values, union members, interfaces and directives that already exist", func() {
+			So(newSchemaError(`extend enum Size { SMALL }`), ShouldEqual, `GraphQL Schema Error (13:20) Enum value "Size.SMALL" already exists in the schema. It cannot also be defined in this type extension`)
+			So(newSchemaError(`extend union Result = QueryRoot`), ShouldEqual, `GraphQL Schema Error (13:23) Union "Result" already includes "QueryRoot". It cannot also be included in this type extension`)
+			So(newSchemaError("extend type QueryRoot implements Named { other: String }\nextend type QueryRoot implements Named { another: String }"), ShouldEqual, `GraphQL Schema Error (14:34) Type "QueryRoot" already implements "Named". It cannot also be implemented in this type extension`)
+			So(newSchemaError(`extend scalar Date @tag`), ShouldEqual, `GraphQL Schema Error (13:20) Directive "tag" is already applied to "Date". It cannot also be applied in this type extension`)
+		})
+	})
+
+	Convey("NewSchema: Reports every schema error together", t, func() {
+		_, _, err := NewSchema(`
+type QueryRoot implements Node {
+  pets: [Pet]
+}
+interface Node {
+  id: ID!
+}
+extend type Pet {
+  name: String
+}
+`, "QueryRoot", "")
+		errs := err.(SchemaErrors)
+		So(len(errs), ShouldEqual, 3)
+		So(errs[0].Message, ShouldEqual, `GraphQL Schema Error (8:13) Cannot extend type "Pet" because it is not defined`)
+		So(errs[1].Message, ShouldEqual, `GraphQL Schema Error (3:10) Unknown type "Pet"`)
+		So(errs[2].Message, ShouldEqual, `GraphQL Schema Error (2:27) Interface field "Node.id" expected but "QueryRoot" does not provide it`)
+		So(err.Error(), ShouldStartWith, errs[0].Error()+"\n"+errs[1].Error()+"\n")
+	})
+
+}
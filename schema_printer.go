@@ -0,0 +1,132 @@
+package graphql
+
+import (
+	"sort"
+	"strings"
+
+	. "github.com/playlyfe/go-graphql/language"
+)
+
+type PrintSchemaParams struct {
+	// IncludeIntrospectionTypes also prints the built-in scalars and the
+	// introspection types appended to every schema by NewSchema.
+	IncludeIntrospectionTypes bool
+}
+
+// Print returns the schema in the GraphQL schema language. The schema
+// definition is printed first when the root types do not use the conventional
+// names, then directive definitions and types. Directives and types are sorted
+// by name while fields, arguments and enum values keep their definition order.
+func (schema *Schema) Print(params *PrintSchemaParams) string {
+	if params == nil {
+		params = &PrintSchemaParams{}
+	}
+	typeNames := []string{}
+	for typeName := range schema.Document.TypeIndex {
+		if !params.IncludeIntrospectionTypes && isIntrospectionType(typeName) {
+			continue
+		}
+		typeNames = append(typeNames, typeName)
+	}
+	sort.Strings(typeNames)
+
+	directiveNames := []string{}
+	for directiveName := range schema.Document.DirectiveIndex {
+		directiveNames = append(directiveNames, directiveName)
+	}
+	sort.Strings(directiveNames)
+
+	definitions := []string{}
+	if schemaDefinition := schema.printableSchemaDefinition(); schemaDefinition != nil {
+		definitions = append(definitions, Print(schemaDefinition))
+	}
+	for _, directiveName := range directiveNames {
+		definitions = append(definitions, Print(schema.Document.DirectiveIndex[directiveName]))
+	}
+	for _, typeName := range typeNames {
+		definitions = append(definitions, Print(printableTypeDefinition(schema.Document.TypeIndex[typeName], params)))
+	}
+	return strings.Join(definitions, "\n\n") + "\n"
+}
+
+// printableSchemaDefinition returns the schema definition naming the root
+// types, or nil if parsing the printed types alone would yield the same roots.
+func (schema *Schema) printableSchemaDefinition() *SchemaDefinition {
+	schemaDefinition := &SchemaDefinition{}
+	if schema.Document.SchemaDefinition != nil {
+		schemaDefinition.Description = schema.Document.SchemaDefinition.Description
+		schemaDefinition.Directives = schema.Document.SchemaDefinition.Directives
+	}
+	isConventional := len(schemaDefinition.Directives) == 0
+	conventionalNames := map[string]string{
+		"query":        "Query",
+		"mutation":     "Mutation",
+		"subscription": "Subscription",
+	}
+	for _, operation := range []string{"query", "mutation", "subscription"} {
+		var root *ObjectTypeDefinition
+		switch operation {
+		case "query":
+			root = schema.QueryRoot
+		case "mutation":
+			root = schema.MutationRoot
+		case "subscription":
+			root = schema.SubscriptionRoot
+		}
+		if root == nil {
+			if _, ok := schema.Document.ObjectTypeIndex[conventionalNames[operation]]; ok {
+				isConventional = false
+			}
+			continue
+		}
+		if root.Name.Value != conventionalNames[operation] {
+			isConventional = false
+		}
+		schemaDefinition.OperationTypes = append(schemaDefinition.OperationTypes, &OperationTypeDefinition{
+			Operation: operation,
+			Type:      &NamedType{Name: root.Name},
+		})
+	}
+	if isConventional {
+		return nil
+	}
+	return schemaDefinition
+}
+
+func isIntrospectionType(typeName string) bool {
+	switch typeName {
+	case "String", "Boolean", "Int", "Float", "ID":
+		return true
+	}
+	return strings.HasPrefix(typeName, "__")
+}
+
+// printableTypeDefinition returns a copy of object and interface definitions
+// without the introspection fields added to the query root, unless they are
+// requested.
+func printableTypeDefinition(definition ASTNode, params *PrintSchemaParams) ASTNode {
+	if params.IncludeIntrospectionTypes {
+		return definition
+	}
+	switch definition := definition.(type) {
+	case *ObjectTypeDefinition:
+		printable := *definition
+		printable.Fields = printableFields(definition.Fields)
+		return &printable
+	case *InterfaceTypeDefinition:
+		printable := *definition
+		printable.Fields = printableFields(definition.Fields)
+		return &printable
+	}
+	return definition
+}
+
+func printableFields(fieldDefinitions []*FieldDefinition) []*FieldDefinition {
+	fields := []*FieldDefinition{}
+	for _, field := range fieldDefinitions {
+		if !strings.HasPrefix(field.Name.Value, "__") {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
@@ -0,0 +1,139 @@
+package graphql
+
+import (
+	"testing"
+
+	"github.com/playlyfe/go-graphql/language"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSchemaPrinter(t *testing.T) {
+
+	Convey("Print: Prints the schema language", t, func() {
+		schema := `
+        ## A pet
+        interface Pet {
+            name: String
+        }
+
+        union Animal = Dog | Cat
+
+        ## The known commands
+        enum Command {
+            SIT
+            ## Only for good dogs
+            HEEL
+        }
+
+        input Filter {
+            name: String = "Odie"
+            commands: [Command!] = [SIT, HEEL]
+            limit: Int = 10
+        }
+
+        type Dog implements Pet {
+            ## The name of the dog
+            name: String
+            knows(command: Command!, loudly: Boolean = false): Boolean
+            search(
+                ## Narrows the results
+                filter: Filter
+                ratio: Float = 0.5
+            ): [Dog!]!
+        }
+
+        type Cat implements Pet {
+            name: String
+        }
+
+        scalar Date
+
+        type QueryRoot {
+            pets: [Pet]
+            animal(id: ID!): Animal
+            born: Date
+        }
+        `
+		executor, err := NewExecutor(schema, "QueryRoot", "", map[string]interface{}{})
+		So(err, ShouldEqual, nil)
+		expected := `schema {
+  query: QueryRoot
+}
+
+union Animal = Dog | Cat
+
+type Cat implements Pet {
+  name: String
+}
+
+## The known commands
+enum Command {
+  SIT
+  ## Only for good dogs
+  HEEL
+}
+
+scalar Date
+
+type Dog implements Pet {
+  ## The name of the dog
+  name: String
+  knows(command: Command!, loudly: Boolean = false): Boolean
+  search(
+    ## Narrows the results
+    filter: Filter
+    ratio: Float = 0.5
+  ): [Dog!]!
+}
+
+input Filter {
+  name: String = "Odie"
+  commands: [Command!] = [SIT, HEEL]
+  limit: Int = 10
+}
+
+## A pet
+interface Pet {
+  name: String
+}
+
+type QueryRoot {
+  pets: [Pet]
+  animal(id: ID!): Animal
+  born: Date
+}
+`
+
+		Convey("prints sorted types without introspection types", func() {
+			So(executor.PrintSchema(), ShouldEqual, expected)
+		})
+
+		Convey("prints a schema that can be parsed again", func() {
+			reparsed, err := NewExecutor(executor.PrintSchema(), "", "", map[string]interface{}{})
+			So(err, ShouldEqual, nil)
+			So(reparsed.PrintSchema(), ShouldEqual, expected)
+		})
+
+		Convey("prints introspection types when requested", func() {
+			output := executor.Schema.Print(&PrintSchemaParams{
+				IncludeIntrospectionTypes: true,
+			})
+			So(output, ShouldContainSubstring, "scalar String\n")
+			So(output, ShouldContainSubstring, "type __Schema {\n")
+			So(output, ShouldContainSubstring, "  ## The GraphQL schema\n  __schema: __Schema!\n")
+			So(output, ShouldContainSubstring, "scalar Boolean\n")
+		})
+
+		Convey("prints deprecations", func() {
+			dog := executor.Schema.Document.ObjectTypeIndex["Dog"]
+			dog.FieldIndex["knows"].IsDeprecated = true
+			dog.FieldIndex["knows"].DeprecationReason = language.DEFAULT_DEPRECATION_REASON
+			dog.FieldIndex["name"].IsDeprecated = true
+			dog.FieldIndex["name"].DeprecationReason = "Use \"nickname\""
+			output := executor.PrintSchema()
+			So(output, ShouldContainSubstring, "  knows(command: Command!, loudly: Boolean = false): Boolean @deprecated\n")
+			So(output, ShouldContainSubstring, "  name: String @deprecated(reason: \"Use \\\"nickname\\\"\")\n")
+		})
+	})
+
+}
@@ -0,0 +1,100 @@
+package graphql
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSchemaDefinition(t *testing.T) {
+
+	resolvers := map[string]interface{}{
+		"Query/name": func(params *ResolveParams) (interface{}, error) {
+			return "query", nil
+		},
+		"Root/name": func(params *ResolveParams) (interface{}, error) {
+			return "root", nil
+		},
+		"Mutation/name": func(params *ResolveParams) (interface{}, error) {
+			return "mutation", nil
+		},
+	}
+
+	Convey("NewSchema: Uses the conventional root type names", t, func() {
+		schema, _, err := NewSchema(`
+type Query { name: String }
+type Mutation { name: String }
+type Subscription { name: String }
+`, "", "")
+		So(err, ShouldEqual, nil)
+		So(schema.QueryRoot.Name.Value, ShouldEqual, "Query")
+		So(schema.MutationRoot.Name.Value, ShouldEqual, "Mutation")
+		So(schema.SubscriptionRoot.Name.Value, ShouldEqual, "Subscription")
+		So(schema.Print(&PrintSchemaParams{}), ShouldStartWith, "type Mutation {")
+	})
+
+	Convey("NewSchema: Uses the root types named by the schema definition", t, func() {
+		executor, err := NewExecutor(`
+schema {
+  query: Root
+}
+type Query { name: String }
+type Root { name: String }
+type Mutation { name: String }
+extend schema {
+  mutation: Mutation
+}
+`, "", "", resolvers)
+		So(err, ShouldEqual, nil)
+		So(executor.Schema.QueryRoot.Name.Value, ShouldEqual, "Root")
+		So(executor.Schema.MutationRoot.Name.Value, ShouldEqual, "Mutation")
+		So(executor.Schema.SubscriptionRoot, ShouldBeNil)
+
+		result, err := executor.Execute(nil, `{ name, __schema { queryType { name }, mutationType { name } } }`, map[string]interface{}{}, "")
+		So(err, ShouldEqual, nil)
+		So(result, ShouldResemble, map[string]interface{}{
+			"data": map[string]interface{}{
+				"name": "root",
+				"__schema": map[string]interface{}{
+					"queryType":    map[string]interface{}{"name": "Root"},
+					"mutationType": map[string]interface{}{"name": "Mutation"},
+				},
+			},
+		})
+		So(executor.PrintSchema(), ShouldStartWith, "schema {\n  query: Root\n  mutation: Mutation\n}\n\n")
+	})
+
+	Convey("NewSchema: Explicit root types take precedence over the schema definition", t, func() {
+		schema, _, err := NewSchema(`
+schema { query: Root }
+type Query { name: String }
+type Root { name: String }
+`, "Query", "")
+		So(err, ShouldEqual, nil)
+		So(schema.QueryRoot.Name.Value, ShouldEqual, "Query")
+	})
+
+	Convey("NewSchema: Rejects invalid schema definitions", t, func() {
+		_, _, err := NewSchema(`
+schema { query: Query }
+schema { query: Query }
+type Query { name: String }
+extend schema { query: Query }
+`, "", "")
+		So(err, ShouldNotEqual, nil)
+		errs := err.(SchemaErrors)
+		So(len(errs), ShouldEqual, 2)
+		So(errs[0].Message, ShouldEqual, `GraphQL Schema Error (5:17) Type for query already defined in the schema. It cannot be redefined`)
+		So(errs[1].Message, ShouldEqual, `GraphQL Schema Error (3:1) Must provide only one schema definition`)
+
+		_, _, err = NewSchema(`
+schema { query: Named }
+interface Named { name: String }
+`, "", "")
+		So(err, ShouldNotEqual, nil)
+		errs = err.(SchemaErrors)
+		So(len(errs), ShouldEqual, 1)
+		So(errs[0].Message, ShouldEqual, `GraphQL Schema Error (2:17) The query root type must be an Object type, it cannot be "Named"`)
+	})
+
+}
@@ -0,0 +1,276 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	. "github.com/playlyfe/go-graphql/language"
+	"github.com/playlyfe/go-graphql/utils"
+)
+
+// Subscription is a running subscription operation. A result is sent on
+// Results for every event produced by the subscription's source stream, and
+// Results is closed once the source stream ends or the subscription is closed.
+type Subscription struct {
+	Results <-chan map[string]interface{}
+	results chan map[string]interface{}
+	done    chan struct{}
+	// ctx is cancelled by Close or once the context of the subscription is
+	// cancelled
+	ctx    context.Context
+	cancel context.CancelFunc
+	once   sync.Once
+}
+
+// Close stops the subscription. The source stream is no longer read from, and
+// the Done channel and the Ctx passed to the subscription resolver are closed
+// so that the producer can release its resources.
+func (subscription *Subscription) Close() {
+	subscription.once.Do(func() {
+		close(subscription.done)
+		subscription.cancel()
+	})
+}
+
+// finish delivers a single result and ends the subscription. It is used when
+// the subscription fails before its source stream could be created.
+func (subscription *Subscription) finish(result map[string]interface{}) *Subscription {
+	go func() {
+		defer close(subscription.results)
+		defer subscription.Close()
+		select {
+		case subscription.results <- result:
+		case <-subscription.ctx.Done():
+		}
+	}()
+	return subscription
+}
+
+// Subscribe executes a subscription operation. The resolver of the selected
+// subscription root field must return a `<-chan interface{}` (or a
+// `chan interface{}`) of source events. Each event is used as the value of the
+// field and completed against the field's selection set, producing one result
+// per event. Callers must call Close once they stop reading from Results.
+func (executor *Executor) Subscribe(appContext interface{}, request string, variables map[string]interface{}, operationName string) (*Subscription, error) {
+	return executor.SubscribeContext(context.Background(), appContext, request, variables, operationName)
+}
+
+// SubscribeContext executes a subscription operation like Subscribe but closes
+// the subscription once ctx is cancelled or its deadline is exceeded. The Ctx
+// passed to the resolvers is derived from ctx.
+func (executor *Executor) SubscribeContext(ctx context.Context, appContext interface{}, request string, variables map[string]interface{}, operationName string) (*Subscription, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	results := make(chan map[string]interface{})
+	subscription := &Subscription{
+		Results: results,
+		results: results,
+		done:    make(chan struct{}),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+
+	result := map[string]interface{}{}
+	if errors := executor.persistedQueryErrors(request); errors != nil {
+		result["errors"] = errors
+		return subscription.finish(result), nil
+	}
+	document, plans, errs := executor.parseRequest(request)
+	if len(errs) > 0 {
+		for _, err := range errs {
+			result, err = handleGQLError(result, err)
+			if err != nil {
+				cancel()
+				return nil, err
+			}
+		}
+		return subscription.finish(result), nil
+	}
+
+	reqCtx := &RequestContext{
+		Ctx:        ctx,
+		AppContext: appContext,
+		Document:   document,
+		ErrorList:  &ErrorList{},
+		Variables:  variables,
+		Done:       subscription.done,
+		Loaders:    executor.loaders(ctx),
+		workers:    executor.Scheduler.workers(),
+		plans:      plans,
+	}
+
+	operation := executor.selectOperation(reqCtx, operationName)
+	if operation != nil && operation.Operation != "subscription" {
+		reqCtx.ErrorList.Add(&Error{
+			Error: &GraphQLError{
+				Message: fmt.Sprintf("GraphQL Runtime Error: Subscribe can not execute %s operations", operation.Operation),
+			},
+		})
+	} else if operation != nil && executor.Schema.SubscriptionRoot == nil {
+		reqCtx.ErrorList.Add(&Error{
+			Error: &GraphQLError{
+				Message: "GraphQL Runtime Error: Schema is not configured for subscriptions",
+			},
+		})
+	} else if operation != nil {
+		executor.withinLimits(reqCtx, operation)
+	}
+	if len(reqCtx.ErrorList.Errors) > 0 {
+		result, err := executor.completeResult(reqCtx, result)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		return subscription.finish(result), nil
+	}
+
+	if executor.Before != nil {
+		err := executor.Before(&ResolveParams{
+			Executor: executor,
+			Schema:   executor.Schema.Document,
+			Request:  reqCtx.Document,
+			Context:  reqCtx.AppContext,
+			Ctx:      reqCtx.Ctx,
+			Done:     reqCtx.Done,
+		}, operation.Operation)
+		if err != nil {
+			result, err = handleGQLError(result, err)
+			if err != nil {
+				cancel()
+				return nil, err
+			}
+			return subscription.finish(result), nil
+		}
+	}
+	reqCtx.VariableDefinitionIndex = operation.VariableDefinitionIndex
+
+	subscriptionRoot := executor.Schema.SubscriptionRoot
+	groupedFields, err := executor.collectFields(reqCtx, subscriptionRoot, operation.SelectionSet, &utils.Set{}, nil)
+	if err != nil {
+		result, err = handleGQLError(result, err)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		return subscription.finish(result), nil
+	}
+	if len(groupedFields) == 0 {
+		result["data"] = map[string]interface{}{}
+		result, err = executor.completeResult(reqCtx, result)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		return subscription.finish(result), nil
+	}
+
+	responseKey := groupedFields[0].ResponseKey
+	fields := groupedFields[0].Fields
+	field := fields[0]
+	fieldType := executor.getFieldTypeFromObjectType(subscriptionRoot, field)
+	stream, err := executor.resolveFieldOnObject(reqCtx, &ResponsePath{Key: responseKey}, subscriptionRoot, map[string]interface{}{}, fieldType, field)
+	if err != nil {
+		result, err = handleGQLError(result, err)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		return subscription.finish(result), nil
+	}
+	if len(reqCtx.ErrorList.Errors) > 0 {
+		result, err = executor.completeResult(reqCtx, result)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		return subscription.finish(result), nil
+	}
+
+	var source <-chan interface{}
+	switch stream := stream.(type) {
+	case <-chan interface{}:
+		source = stream
+	case chan interface{}:
+		source = stream
+	default:
+		result, _ = handleGQLError(result, &GraphQLError{
+			Message: fmt.Sprintf("GraphQL Runtime Error (%d:%d) Subscription field %q must resolve to a channel of events", field.Name.LOC.Start.Line, field.Name.LOC.Start.Column, field.Name.Value),
+			Source:  field.Name.LOC.Source,
+			Start:   field.Name.LOC.Start,
+			End:     field.Name.LOC.End,
+		})
+		return subscription.finish(result), nil
+	}
+
+	subSelectionSet := executor.mergeSelectionSets(fields)
+	go func() {
+		defer close(results)
+		defer subscription.Close()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-source:
+				if !ok {
+					return
+				}
+				result := executor.executeEvent(reqCtx, subscriptionRoot, fieldType, field, responseKey, subSelectionSet, event)
+				select {
+				case results <- result:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return subscription, nil
+}
+
+// executeEvent produces the result for a single source event by completing it
+// as the value of the subscription field.
+func (executor *Executor) executeEvent(reqCtx *RequestContext, subscriptionRoot *ObjectTypeDefinition, fieldType ASTNode, field *Field, responseKey string, subSelectionSet *SelectionSet, event interface{}) map[string]interface{} {
+	eventCtx := &RequestContext{
+		Ctx:                     reqCtx.Ctx,
+		AppContext:              reqCtx.AppContext,
+		Document:                reqCtx.Document,
+		ErrorList:               &ErrorList{},
+		Variables:               reqCtx.Variables,
+		VariableDefinitionIndex: reqCtx.VariableDefinitionIndex,
+		Done:                    reqCtx.Done,
+		Loaders:                 executor.loaders(reqCtx.Ctx),
+		workers:                 reqCtx.workers,
+		plans:                   reqCtx.plans,
+	}
+	result := map[string]interface{}{}
+	value, err := executor.completeValueCatchingError(eventCtx, &ResponsePath{Key: responseKey}, subscriptionRoot, fieldType, field, event, subSelectionSet)
+	if err != nil {
+		if _, ok := err.(*GraphQLError); ok {
+			result, _ = handleGQLError(result, err)
+		} else {
+			eventCtx.ErrorList.Add(&Error{
+				Error: err,
+				Field: field,
+				Path:  []interface{}{responseKey},
+			})
+		}
+		result["data"] = map[string]interface{}(nil)
+	} else if executor.OrderedResults {
+		data := NewOrderedMap()
+		data.Set(responseKey, value)
+		result["data"] = data
+	} else {
+		result["data"] = map[string]interface{}{
+			responseKey: value,
+		}
+	}
+
+	completed, err := executor.completeResult(eventCtx, result)
+	if err != nil {
+		result, _ = handleGQLError(map[string]interface{}{}, &GraphQLError{
+			Message: err.Error(),
+		})
+		return result
+	}
+	return completed
+}
@@ -0,0 +1,202 @@
+package graphql
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSubscription(t *testing.T) {
+
+	Convey("Subscribe: Handles subscriptions", t, func() {
+		schema := `
+        type Message {
+            id: ID!
+            body: String
+        }
+        type Query {
+            messages: [Message]
+        }
+        type Subscription {
+            messageAdded(room: String!): Message
+            invalidStream: Message
+        }
+        `
+		stopped := make(chan struct{})
+		resolvers := map[string]interface{}{}
+		resolvers["Subscription/messageAdded"] = func(params *ResolveParams) (interface{}, error) {
+			events := make(chan interface{})
+			room := params.Args["room"].(string)
+			go func() {
+				defer close(stopped)
+				for i := 1; ; i++ {
+					select {
+					case events <- map[string]interface{}{"id": i, "body": room}:
+						if i == 3 && room == "finite" {
+							close(events)
+							return
+						}
+					case <-params.Done:
+						return
+					}
+				}
+			}()
+			return (<-chan interface{})(events), nil
+		}
+		resolvers["Subscription/invalidStream"] = func(params *ResolveParams) (interface{}, error) {
+			return map[string]interface{}{"id": 1}, nil
+		}
+		executor, err := NewGraphQL(&GraphQLParams{
+			SchemaDefinition: schema,
+			QueryRoot:        "Query",
+			SubscriptionRoot: "Subscription",
+			Resolvers:        resolvers,
+		})
+		So(err, ShouldEqual, nil)
+		appContext := map[string]interface{}{}
+		variables := map[string]interface{}{}
+
+		Convey("sends a result for every source event", func() {
+			input := `
+            subscription S($room: String!) {
+                messageAdded(room: $room) {
+                    id
+                    body
+                }
+            }
+            `
+			subscription, err := executor.Subscribe(appContext, input, map[string]interface{}{"room": "finite"}, "")
+			So(err, ShouldEqual, nil)
+			results := []map[string]interface{}{}
+			for result := range subscription.Results {
+				results = append(results, result)
+			}
+			So(results, ShouldResemble, []map[string]interface{}{
+				{"data": map[string]interface{}{"messageAdded": map[string]interface{}{"id": "1", "body": "finite"}}},
+				{"data": map[string]interface{}{"messageAdded": map[string]interface{}{"id": "2", "body": "finite"}}},
+				{"data": map[string]interface{}{"messageAdded": map[string]interface{}{"id": "3", "body": "finite"}}},
+			})
+		})
+
+		Convey("stops the source stream when closed", func() {
+			input := `subscription { messageAdded(room: "endless") { id } }`
+			subscription, err := executor.Subscribe(appContext, input, variables, "")
+			So(err, ShouldEqual, nil)
+			So(<-subscription.Results, ShouldResemble, map[string]interface{}{
+				"data": map[string]interface{}{"messageAdded": map[string]interface{}{"id": "1"}},
+			})
+			subscription.Close()
+			select {
+			case <-stopped:
+			case <-time.After(time.Second):
+				t.Fatal("source stream was not stopped")
+			}
+			for range subscription.Results {
+			}
+		})
+
+		Convey("stops the source stream when its context is cancelled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			input := `subscription { messageAdded(room: "endless") { id } }`
+			subscription, err := executor.SubscribeContext(ctx, appContext, input, variables, "")
+			So(err, ShouldEqual, nil)
+			So(<-subscription.Results, ShouldResemble, map[string]interface{}{
+				"data": map[string]interface{}{"messageAdded": map[string]interface{}{"id": "1"}},
+			})
+			cancel()
+			select {
+			case <-stopped:
+			case <-time.After(time.Second):
+				t.Fatal("source stream was not stopped")
+			}
+			for range subscription.Results {
+			}
+		})
+
+		Convey("reports validation errors without subscribing", func() {
+			input := `subscription { messageAdded(room: "a") { id } invalidStream { id } }`
+			subscription, err := executor.Subscribe(appContext, input, variables, "")
+			So(err, ShouldEqual, nil)
+			So(<-subscription.Results, ShouldResemble, map[string]interface{}{
+				"errors": []map[string]interface{}{
+					{
+						"message": "GraphQL Validation Error (1:1) Anonymous Subscription must select only one top level field",
+						"locations": []map[string]interface{}{
+							{"line": 1, "column": 1},
+						},
+					},
+				},
+			})
+			_, open := <-subscription.Results
+			So(open, ShouldEqual, false)
+		})
+
+		Convey("without leaving goroutines behind once the error is read", func() {
+			goroutines := runtime.NumGoroutine()
+			for i := 0; i < 10; i++ {
+				subscription, err := executor.Subscribe(appContext, `subscription { unknown }`, variables, "")
+				So(err, ShouldEqual, nil)
+				for range subscription.Results {
+				}
+			}
+			// The goroutines delivering the errors may still be exiting
+			deadline := time.Now().Add(time.Second)
+			for runtime.NumGoroutine() > goroutines && time.Now().Before(deadline) {
+				time.Sleep(time.Millisecond)
+			}
+			So(runtime.NumGoroutine(), ShouldBeLessThanOrEqualTo, goroutines)
+		})
+
+		Convey("reports resolvers that do not return a channel", func() {
+			input := `subscription { invalidStream { id } }`
+			subscription, err := executor.Subscribe(appContext, input, variables, "")
+			So(err, ShouldEqual, nil)
+			So(<-subscription.Results, ShouldResemble, map[string]interface{}{
+				"errors": []map[string]interface{}{
+					{
+						"message": "GraphQL Runtime Error (1:16) Subscription field \"invalidStream\" must resolve to a channel of events",
+						"locations": []map[string]interface{}{
+							{"line": 1, "column": 16},
+						},
+					},
+				},
+			})
+		})
+
+		Convey("rejects other operations", func() {
+			subscription, err := executor.Subscribe(appContext, `{ messages { id } }`, variables, "")
+			So(err, ShouldEqual, nil)
+			So(<-subscription.Results, ShouldResemble, map[string]interface{}{
+				"errors": []map[string]interface{}{
+					{"message": "GraphQL Runtime Error: Subscribe can not execute query operations"},
+				},
+			})
+			result, err := executor.Execute(appContext, `subscription { messageAdded(room: "a") { id } }`, variables, "")
+			So(err, ShouldEqual, nil)
+			So(result, ShouldResemble, map[string]interface{}{
+				"data": map[string]interface{}(nil),
+				"errors": []map[string]interface{}{
+					{"message": "GraphQL Runtime Error: Subscription operations must be executed with Subscribe"},
+				},
+			})
+		})
+
+		Convey("exposes the subscription type through introspection", func() {
+			result, err := executor.Execute(appContext, `{ __schema { subscriptionType { name } } }`, variables, "")
+			So(err, ShouldEqual, nil)
+			So(result, ShouldResemble, map[string]interface{}{
+				"data": map[string]interface{}{
+					"__schema": map[string]interface{}{
+						"subscriptionType": map[string]interface{}{
+							"name": "Subscription",
+						},
+					},
+				},
+			})
+		})
+	})
+
+}
@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"container/list"
+	"sync"
+)
+
+// A "thread" safe cache keeping at most MaxSize items, evicting the least
+// recently used item when a new one is added to a full cache.
+type LRU struct {
+	MaxSize int
+	items   map[interface{}]*list.Element
+	order   *list.List
+	sync.Mutex
+}
+
+type lruItem struct {
+	key   interface{}
+	value interface{}
+}
+
+// Creates a new cache holding at most maxSize items.
+func NewLRU(maxSize int) *LRU {
+	return &LRU{
+		MaxSize: maxSize,
+		items:   map[interface{}]*list.Element{},
+		order:   list.New(),
+	}
+}
+
+// Get retrieves an item from the cache and marks it as the most recently used.
+func (lru *LRU) Get(key interface{}) (interface{}, bool) {
+	lru.Lock()
+	defer lru.Unlock()
+	element, ok := lru.items[key]
+	if !ok {
+		return nil, false
+	}
+	lru.order.MoveToFront(element)
+	return element.Value.(*lruItem).value, true
+}
+
+// Add sets the item under the specified key and returns the number of items
+// evicted to make room for it.
+func (lru *LRU) Add(key interface{}, value interface{}) int {
+	lru.Lock()
+	defer lru.Unlock()
+	if element, ok := lru.items[key]; ok {
+		element.Value.(*lruItem).value = value
+		lru.order.MoveToFront(element)
+		return 0
+	}
+	lru.items[key] = lru.order.PushFront(&lruItem{key: key, value: value})
+	evicted := 0
+	for lru.MaxSize > 0 && lru.order.Len() > lru.MaxSize {
+		oldest := lru.order.Back()
+		lru.order.Remove(oldest)
+		delete(lru.items, oldest.Value.(*lruItem).key)
+		evicted++
+	}
+	return evicted
+}
+
+// Remove removes an item from the cache.
+func (lru *LRU) Remove(key interface{}) {
+	lru.Lock()
+	defer lru.Unlock()
+	if element, ok := lru.items[key]; ok {
+		lru.order.Remove(element)
+		delete(lru.items, key)
+	}
+}
+
+// Len returns the number of items in the cache.
+func (lru *LRU) Len() int {
+	lru.Lock()
+	defer lru.Unlock()
+	return lru.order.Len()
+}
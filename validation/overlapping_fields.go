@@ -0,0 +1,256 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	. "github.com/playlyfe/go-graphql/language"
+)
+
+type fieldAndDefinition struct {
+	ParentType ASTNode
+	Field      *Field
+	Definition *FieldDefinition
+}
+
+type fieldMap struct {
+	ResponseNames []string
+	Fields        map[string][]*fieldAndDefinition
+}
+
+type conflict struct {
+	ResponseName string
+	Reason       string
+	Field1       *Field
+	Field2       *Field
+}
+
+/**
+ * Overlapping fields can be merged
+ *
+ * A selection set is only valid if all fields (including spreading any
+ * fragments) either correspond to distinct response names or can be merged
+ * without ambiguity.
+ */
+func OverlappingFieldsCanBeMerged(context *Context) *Visitor {
+	reportedPairs := map[[2]*Field]bool{}
+	return &Visitor{
+		Enter: func(node ASTNode) {
+			selectionSet, ok := node.(*SelectionSet)
+			if !ok {
+				return
+			}
+			fields := collectFieldsAndDefinitions(context, context.ParentType(), selectionSet, map[string]bool{}, nil)
+			for _, conflict := range findConflictsWithin(context, fields) {
+				if reportedPairs[[2]*Field{conflict.Field1, conflict.Field2}] {
+					continue
+				}
+				reportedPairs[[2]*Field{conflict.Field1, conflict.Field2}] = true
+				reportedPairs[[2]*Field{conflict.Field2, conflict.Field1}] = true
+				context.ReportError(conflict.Field2.LOC, "Fields %q conflict because %s. Use different aliases on the fields to fetch both if this was intentional", conflict.ResponseName, conflict.Reason)
+			}
+		},
+	}
+}
+
+// collectFieldsAndDefinitions gathers every field selected within a selection
+// set, including those in inline fragments and fragment spreads, grouped by
+// their response name.
+func collectFieldsAndDefinitions(context *Context, parentType ASTNode, selectionSet *SelectionSet, visitedFragments map[string]bool, fields *fieldMap) *fieldMap {
+	if fields == nil {
+		fields = &fieldMap{
+			ResponseNames: []string{},
+			Fields:        map[string][]*fieldAndDefinition{},
+		}
+	}
+	for _, selection := range selectionSet.Selections {
+		switch selection := selection.(type) {
+		case *Field:
+			var definition *FieldDefinition
+			if parentType != nil {
+				definition = fieldDefinition(context.Schema, parentType, selection.Name.Value)
+			}
+			responseName := selection.Name.Value
+			if selection.Alias != nil {
+				responseName = selection.Alias.Value
+			}
+			if _, ok := fields.Fields[responseName]; !ok {
+				fields.ResponseNames = append(fields.ResponseNames, responseName)
+			}
+			fields.Fields[responseName] = append(fields.Fields[responseName], &fieldAndDefinition{
+				ParentType: parentType,
+				Field:      selection,
+				Definition: definition,
+			})
+		case *InlineFragment:
+			fragmentType := parentType
+			if selection.TypeCondition != nil {
+				fragmentType = context.NamedType(selection.TypeCondition)
+			}
+			if selection.SelectionSet != nil {
+				collectFieldsAndDefinitions(context, fragmentType, selection.SelectionSet, visitedFragments, fields)
+			}
+		case *FragmentSpread:
+			fragmentName := selection.Name.Value
+			if visitedFragments[fragmentName] {
+				continue
+			}
+			visitedFragments[fragmentName] = true
+			fragment := context.Fragment(fragmentName)
+			if fragment == nil {
+				continue
+			}
+			collectFieldsAndDefinitions(context, context.NamedType(fragment.TypeCondition), fragment.SelectionSet, visitedFragments, fields)
+		}
+	}
+	return fields
+}
+
+func findConflictsWithin(context *Context, fields *fieldMap) []*conflict {
+	conflicts := []*conflict{}
+	for _, responseName := range fields.ResponseNames {
+		candidates := fields.Fields[responseName]
+		for i := 0; i < len(candidates); i++ {
+			for j := i + 1; j < len(candidates); j++ {
+				if conflict := findConflict(context, responseName, candidates[i], candidates[j], false); conflict != nil {
+					conflicts = append(conflicts, conflict)
+				}
+			}
+		}
+	}
+	return conflicts
+}
+
+func findConflictsBetween(context *Context, fields1 *fieldMap, fields2 *fieldMap, parentFieldsAreMutuallyExclusive bool) []*conflict {
+	conflicts := []*conflict{}
+	for _, responseName := range fields1.ResponseNames {
+		candidates2, ok := fields2.Fields[responseName]
+		if !ok {
+			continue
+		}
+		for _, candidate1 := range fields1.Fields[responseName] {
+			for _, candidate2 := range candidates2 {
+				if conflict := findConflict(context, responseName, candidate1, candidate2, parentFieldsAreMutuallyExclusive); conflict != nil {
+					conflicts = append(conflicts, conflict)
+				}
+			}
+		}
+	}
+	return conflicts
+}
+
+func findConflict(context *Context, responseName string, field1 *fieldAndDefinition, field2 *fieldAndDefinition, parentFieldsAreMutuallyExclusive bool) *conflict {
+	if field1.Field == field2.Field {
+		return nil
+	}
+	// If the statically known parent types could not possibly apply at the same
+	// time, then it is safe to permit them to diverge as long as the shapes
+	// they return are compatible.
+	_, isObject1 := field1.ParentType.(*ObjectTypeDefinition)
+	_, isObject2 := field2.ParentType.(*ObjectTypeDefinition)
+	areMutuallyExclusive := parentFieldsAreMutuallyExclusive || (isObject1 && isObject2 && typeNameOf(field1.ParentType) != typeNameOf(field2.ParentType))
+
+	var type1, type2 ASTNode
+	if field1.Definition != nil {
+		type1 = field1.Definition.Type
+	}
+	if field2.Definition != nil {
+		type2 = field2.Definition.Type
+	}
+
+	if !areMutuallyExclusive {
+		name1 := field1.Field.Name.Value
+		name2 := field2.Field.Name.Value
+		if name1 != name2 {
+			return &conflict{
+				ResponseName: responseName,
+				Reason:       fmt.Sprintf("%s and %s are different fields", name1, name2),
+				Field1:       field1.Field,
+				Field2:       field2.Field,
+			}
+		}
+		if !sameArguments(field1.Field.Arguments, field2.Field.Arguments) {
+			return &conflict{
+				ResponseName: responseName,
+				Reason:       "they have differing arguments",
+				Field1:       field1.Field,
+				Field2:       field2.Field,
+			}
+		}
+	}
+
+	if type1 != nil && type2 != nil && doTypesConflict(context, type1, type2) {
+		return &conflict{
+			ResponseName: responseName,
+			Reason:       fmt.Sprintf("they return conflicting types %s and %s", Print(type1), Print(type2)),
+			Field1:       field1.Field,
+			Field2:       field2.Field,
+		}
+	}
+
+	if field1.Field.SelectionSet != nil && field2.Field.SelectionSet != nil {
+		fields1 := collectFieldsAndDefinitions(context, context.NamedType(type1), field1.Field.SelectionSet, map[string]bool{}, nil)
+		fields2 := collectFieldsAndDefinitions(context, context.NamedType(type2), field2.Field.SelectionSet, map[string]bool{}, nil)
+		subConflicts := findConflictsBetween(context, fields1, fields2, areMutuallyExclusive)
+		if len(subConflicts) > 0 {
+			reasons := []string{}
+			for _, subConflict := range subConflicts {
+				reasons = append(reasons, fmt.Sprintf("subfields %q conflict because %s", subConflict.ResponseName, subConflict.Reason))
+			}
+			return &conflict{
+				ResponseName: responseName,
+				Reason:       strings.Join(reasons, " and "),
+				Field1:       field1.Field,
+				Field2:       field2.Field,
+			}
+		}
+	}
+	return nil
+}
+
+func sameArguments(arguments1 []*Argument, arguments2 []*Argument) bool {
+	if len(arguments1) != len(arguments2) {
+		return false
+	}
+	for _, argument1 := range arguments1 {
+		found := false
+		for _, argument2 := range arguments2 {
+			if argument1.Name.Value == argument2.Name.Value {
+				found = Print(argument1.Value) == Print(argument2.Value)
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// doTypesConflict reports whether two field types can never produce the same
+// shape of response, which is the case when they differ in list or non-null
+// wrapping or when either of them is a different leaf type.
+func doTypesConflict(context *Context, type1 ASTNode, type2 ASTNode) bool {
+	listType1, isList1 := type1.(*ListType)
+	listType2, isList2 := type2.(*ListType)
+	if isList1 || isList2 {
+		if isList1 && isList2 {
+			return doTypesConflict(context, listType1.Type, listType2.Type)
+		}
+		return true
+	}
+	nonNullType1, isNonNull1 := type1.(*NonNullType)
+	nonNullType2, isNonNull2 := type2.(*NonNullType)
+	if isNonNull1 || isNonNull2 {
+		if isNonNull1 && isNonNull2 {
+			return doTypesConflict(context, nonNullType1.Type, nonNullType2.Type)
+		}
+		return true
+	}
+	definition1 := context.NamedType(type1)
+	definition2 := context.NamedType(type2)
+	if isLeafType(definition1) || isLeafType(definition2) {
+		return typeNameOf(definition1) != typeNameOf(definition2)
+	}
+	return false
+}
@@ -0,0 +1,906 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	. "github.com/playlyfe/go-graphql/language"
+)
+
+// SpecifiedRules is the set of validation rules defined by the GraphQL
+// specification, in the order they are run.
+var SpecifiedRules = []Rule{
+	ExecutableDefinitions,
+	UniqueOperationNames,
+	LoneAnonymousOperation,
+	SingleFieldSubscriptions,
+	KnownTypeNames,
+	FragmentsOnCompositeTypes,
+	VariablesAreInputTypes,
+	ScalarLeafs,
+	FieldsOnCorrectType,
+	UniqueFragmentNames,
+	KnownFragmentNames,
+	NoUnusedFragments,
+	PossibleFragmentSpreads,
+	NoFragmentCycles,
+	UniqueVariableNames,
+	NoUndefinedVariables,
+	NoUnusedVariables,
+	KnownDirectives,
+	UniqueDirectivesPerLocation,
+	KnownArgumentNames,
+	UniqueArgumentNames,
+	ArgumentsOfCorrectType,
+	ProvidedNonNullArguments,
+	DefaultValuesOfCorrectType,
+	VariablesInAllowedPosition,
+	OverlappingFieldsCanBeMerged,
+	UniqueInputFieldNames,
+}
+
+/**
+ * Executable definitions
+ *
+ * A GraphQL document is only valid for execution if all definitions are either
+ * operation or fragment definitions.
+ */
+func ExecutableDefinitions(context *Context) *Visitor {
+	return &Visitor{
+		Enter: func(node ASTNode) {
+			document, ok := node.(*Document)
+			if !ok {
+				return
+			}
+			for _, definition := range document.Definitions {
+				switch definition := definition.(type) {
+				case *OperationDefinition, *FragmentDefinition:
+				case *TypeExtensionDefinition:
+					context.ReportError(definition.LOC, "The %q definition is not executable", typeNameOf(definition.Definition))
+				case *SchemaDefinition, *SchemaExtensionDefinition:
+					context.ReportError(locOf(definition), "The schema definition is not executable")
+				case *DirectiveDefinition:
+					context.ReportError(definition.Name.LOC, "The %q definition is not executable", "@"+definition.Name.Value)
+				default:
+					name := typeName(definition)
+					if name != nil {
+						context.ReportError(name.LOC, "The %q definition is not executable", name.Value)
+					}
+				}
+			}
+		},
+	}
+}
+
+/**
+ * Unique operation names
+ *
+ * A GraphQL document is only valid if all defined operations have unique names.
+ */
+func UniqueOperationNames(context *Context) *Visitor {
+	knownOperationNames := map[string]bool{}
+	return &Visitor{
+		Enter: func(node ASTNode) {
+			if operation, ok := node.(*OperationDefinition); ok && operation.Name != nil {
+				if knownOperationNames[operation.Name.Value] {
+					context.ReportError(operation.Name.LOC, "There can be only one operation named %q", operation.Name.Value)
+				} else {
+					knownOperationNames[operation.Name.Value] = true
+				}
+			}
+		},
+	}
+}
+
+/**
+ * Lone anonymous operation
+ *
+ * A GraphQL document is only valid if when it contains an anonymous operation
+ * (the query short-hand) that it contains only that one operation definition.
+ */
+func LoneAnonymousOperation(context *Context) *Visitor {
+	operationCount := 0
+	return &Visitor{
+		Enter: func(node ASTNode) {
+			switch node := node.(type) {
+			case *Document:
+				for _, definition := range node.Definitions {
+					if _, ok := definition.(*OperationDefinition); ok {
+						operationCount++
+					}
+				}
+			case *OperationDefinition:
+				if node.Name == nil && operationCount > 1 {
+					context.ReportError(node.LOC, "This anonymous operation must be the only defined operation")
+				}
+			}
+		},
+	}
+}
+
+/**
+ * Single field subscriptions
+ *
+ * A GraphQL subscription is valid only if it contains a single root field.
+ */
+func SingleFieldSubscriptions(context *Context) *Visitor {
+	return &Visitor{
+		Enter: func(node ASTNode) {
+			operation, ok := node.(*OperationDefinition)
+			if !ok || operation.Operation != "subscription" {
+				return
+			}
+			if len(operation.SelectionSet.Selections) != 1 {
+				if operation.Name != nil {
+					context.ReportError(operation.LOC, "Subscription %q must select only one top level field", operation.Name.Value)
+				} else {
+					context.ReportError(operation.LOC, "Anonymous Subscription must select only one top level field")
+				}
+			}
+		},
+	}
+}
+
+/**
+ * Known type names
+ *
+ * A GraphQL document is only valid if referenced types (specifically
+ * variable definitions and fragment conditions) are defined by the type schema.
+ */
+func KnownTypeNames(context *Context) *Visitor {
+	return &Visitor{
+		Enter: func(node ASTNode) {
+			if namedType, ok := node.(*NamedType); ok {
+				if context.Schema.TypeIndex == nil || context.Schema.TypeIndex[namedType.Name.Value] == nil {
+					context.ReportError(namedType.LOC, "Unknown type %q", namedType.Name.Value)
+				}
+			}
+		},
+	}
+}
+
+/**
+ * Fragments on composite type
+ *
+ * Fragments use a type condition to determine if they apply, since fragments
+ * can only be spread into a composite type (object, interface, or union), the
+ * type condition must also be a composite type.
+ */
+func FragmentsOnCompositeTypes(context *Context) *Visitor {
+	return &Visitor{
+		Enter: func(node ASTNode) {
+			switch node := node.(type) {
+			case *InlineFragment:
+				if node.TypeCondition == nil {
+					return
+				}
+				definition := context.NamedType(node.TypeCondition)
+				if definition != nil && !isCompositeType(definition) {
+					context.ReportError(node.TypeCondition.LOC, "Fragment cannot condition on non composite type %q", node.TypeCondition.Name.Value)
+				}
+			case *FragmentDefinition:
+				definition := context.NamedType(node.TypeCondition)
+				if definition != nil && !isCompositeType(definition) {
+					context.ReportError(node.TypeCondition.LOC, "Fragment %q cannot condition on non composite type %q", node.Name.Value, node.TypeCondition.Name.Value)
+				}
+			}
+		},
+	}
+}
+
+/**
+ * Variables are input types
+ *
+ * A GraphQL operation is only valid if all the variables it defines are of
+ * input types (scalar, enum, or input object).
+ */
+func VariablesAreInputTypes(context *Context) *Visitor {
+	return &Visitor{
+		Enter: func(node ASTNode) {
+			if variableDefinition, ok := node.(*VariableDefinition); ok {
+				definition := context.NamedType(variableDefinition.Type)
+				if definition != nil && !isInputType(definition) {
+					context.ReportError(locOf(variableDefinition.Type), "Variable \"$%s\" cannot be non-input type %q", variableDefinition.Variable.Name.Value, Print(variableDefinition.Type))
+				}
+			}
+		},
+	}
+}
+
+/**
+ * Scalar leafs
+ *
+ * A GraphQL document is valid only if all leaf fields (fields without
+ * sub selections) are of scalar or enum types.
+ */
+func ScalarLeafs(context *Context) *Visitor {
+	return &Visitor{
+		Enter: func(node ASTNode) {
+			field, ok := node.(*Field)
+			if !ok {
+				return
+			}
+			ttype := context.Type()
+			definition := context.NamedType(ttype)
+			if definition == nil {
+				return
+			}
+			if isLeafType(definition) {
+				if field.SelectionSet != nil {
+					context.ReportError(field.SelectionSet.LOC, "Field %q must not have a selection since type %q has no subfields", field.Name.Value, Print(ttype))
+				}
+			} else if field.SelectionSet == nil {
+				context.ReportError(field.LOC, "Field %q of type %q must have a selection of subfields. Did you mean \"%s { ... }\"?", field.Name.Value, Print(ttype), field.Name.Value)
+			}
+		},
+	}
+}
+
+/**
+ * Fields on correct type
+ *
+ * A GraphQL document is only valid if all fields selected are defined by the
+ * parent type, or are an allowed meta field such as __typename.
+ */
+func FieldsOnCorrectType(context *Context) *Visitor {
+	return &Visitor{
+		Enter: func(node ASTNode) {
+			field, ok := node.(*Field)
+			if !ok {
+				return
+			}
+			parentType := context.ParentType()
+			if parentType != nil && context.FieldDef() == nil {
+				context.ReportError(field.Name.LOC, "Cannot query field %q on type %q", field.Name.Value, typeNameOf(parentType))
+			}
+		},
+	}
+}
+
+/**
+ * Unique fragment names
+ *
+ * A GraphQL document is only valid if all defined fragments have unique names.
+ */
+func UniqueFragmentNames(context *Context) *Visitor {
+	knownFragmentNames := map[string]bool{}
+	return &Visitor{
+		Enter: func(node ASTNode) {
+			if fragment, ok := node.(*FragmentDefinition); ok {
+				if knownFragmentNames[fragment.Name.Value] {
+					context.ReportError(fragment.Name.LOC, "There can be only one fragment named %q", fragment.Name.Value)
+				} else {
+					knownFragmentNames[fragment.Name.Value] = true
+				}
+			}
+		},
+	}
+}
+
+/**
+ * Known fragment names
+ *
+ * A GraphQL document is only valid if all `...Fragment` fragment spreads refer
+ * to fragments defined in the same document.
+ */
+func KnownFragmentNames(context *Context) *Visitor {
+	return &Visitor{
+		Enter: func(node ASTNode) {
+			if spread, ok := node.(*FragmentSpread); ok {
+				if context.Fragment(spread.Name.Value) == nil {
+					context.ReportError(spread.Name.LOC, "Unknown fragment %q", spread.Name.Value)
+				}
+			}
+		},
+	}
+}
+
+/**
+ * No unused fragments
+ *
+ * A GraphQL document is only valid if all fragment definitions are spread
+ * within operations, or spread within other fragments spread within operations.
+ */
+func NoUnusedFragments(context *Context) *Visitor {
+	operations := []*OperationDefinition{}
+	fragments := []*FragmentDefinition{}
+	return &Visitor{
+		Enter: func(node ASTNode) {
+			switch node := node.(type) {
+			case *OperationDefinition:
+				operations = append(operations, node)
+			case *FragmentDefinition:
+				fragments = append(fragments, node)
+			}
+		},
+		Leave: func(node ASTNode) {
+			if _, ok := node.(*Document); !ok {
+				return
+			}
+			fragmentNameUsed := map[string]bool{}
+			for _, operation := range operations {
+				for _, fragment := range context.RecursivelyReferencedFragments(operation) {
+					fragmentNameUsed[fragment.Name.Value] = true
+				}
+			}
+			for _, fragment := range fragments {
+				if !fragmentNameUsed[fragment.Name.Value] {
+					context.ReportError(fragment.LOC, "Fragment %q is never used", fragment.Name.Value)
+				}
+			}
+		},
+	}
+}
+
+/**
+ * Possible fragment spread
+ *
+ * A fragment spread is only valid if the type condition could ever possibly
+ * be true: if there is a non-empty intersection of the possible parent types,
+ * and possible types which pass the type condition.
+ */
+func PossibleFragmentSpreads(context *Context) *Visitor {
+	return &Visitor{
+		Enter: func(node ASTNode) {
+			switch node := node.(type) {
+			case *InlineFragment:
+				fragType := context.NamedType(context.Type())
+				parentType := context.ParentType()
+				if isCompositeType(fragType) && isCompositeType(parentType) && !doTypesOverlap(context.Schema, fragType, parentType) {
+					context.ReportError(node.LOC, "Fragment cannot be spread here as objects of type %q can never be of type %q", typeNameOf(parentType), typeNameOf(fragType))
+				}
+			case *FragmentSpread:
+				fragment := context.Fragment(node.Name.Value)
+				if fragment == nil {
+					return
+				}
+				fragType := context.NamedType(fragment.TypeCondition)
+				parentType := context.ParentType()
+				if isCompositeType(fragType) && isCompositeType(parentType) && !doTypesOverlap(context.Schema, fragType, parentType) {
+					context.ReportError(node.LOC, "Fragment %q cannot be spread here as objects of type %q can never be of type %q", node.Name.Value, typeNameOf(parentType), typeNameOf(fragType))
+				}
+			}
+		},
+	}
+}
+
+/**
+ * No fragment cycles
+ *
+ * A GraphQL document is only valid if fragment spreads do not form cycles.
+ */
+func NoFragmentCycles(context *Context) *Visitor {
+	// Tracks already visited fragments to maintain O(N) and to ensure that
+	// cycles are not redundantly reported.
+	visitedFragments := map[string]bool{}
+	// Array of AST nodes used to produce meaningful errors
+	spreadPath := []*FragmentSpread{}
+	// Position in the spread path
+	spreadPathIndexByName := map[string]int{}
+
+	var detectCycleRecursive func(fragment *FragmentDefinition)
+	detectCycleRecursive = func(fragment *FragmentDefinition) {
+		fragmentName := fragment.Name.Value
+		visitedFragments[fragmentName] = true
+		spreadNodes := context.FragmentSpreads(fragment.SelectionSet)
+		if len(spreadNodes) == 0 {
+			return
+		}
+		spreadPathIndexByName[fragmentName] = len(spreadPath)
+		for _, spreadNode := range spreadNodes {
+			spreadName := spreadNode.Name.Value
+			cycleIndex, ok := spreadPathIndexByName[spreadName]
+			if !ok {
+				spreadPath = append(spreadPath, spreadNode)
+				if !visitedFragments[spreadName] {
+					spreadFragment := context.Fragment(spreadName)
+					if spreadFragment != nil {
+						detectCycleRecursive(spreadFragment)
+					}
+				}
+				spreadPath = spreadPath[:len(spreadPath)-1]
+			} else {
+				cyclePath := spreadPath[cycleIndex:]
+				via := []string{}
+				for _, spread := range cyclePath {
+					via = append(via, spread.Name.Value)
+				}
+				if len(via) > 0 {
+					context.ReportError(spreadNode.LOC, "Cannot spread fragment %q within itself via %s", spreadName, strings.Join(via, ", "))
+				} else {
+					context.ReportError(spreadNode.LOC, "Cannot spread fragment %q within itself", spreadName)
+				}
+			}
+		}
+		delete(spreadPathIndexByName, fragmentName)
+	}
+
+	return &Visitor{
+		Enter: func(node ASTNode) {
+			if fragment, ok := node.(*FragmentDefinition); ok {
+				if !visitedFragments[fragment.Name.Value] {
+					detectCycleRecursive(fragment)
+				}
+			}
+		},
+	}
+}
+
+/**
+ * Unique variable names
+ *
+ * A GraphQL operation is only valid if all its variables are uniquely named.
+ */
+func UniqueVariableNames(context *Context) *Visitor {
+	knownVariableNames := map[string]bool{}
+	return &Visitor{
+		Enter: func(node ASTNode) {
+			switch node := node.(type) {
+			case *OperationDefinition:
+				knownVariableNames = map[string]bool{}
+			case *VariableDefinition:
+				variableName := node.Variable.Name.Value
+				if knownVariableNames[variableName] {
+					context.ReportError(node.Variable.LOC, "There can be only one variable named %q", variableName)
+				} else {
+					knownVariableNames[variableName] = true
+				}
+			}
+		},
+	}
+}
+
+/**
+ * No undefined variables
+ *
+ * A GraphQL operation is only valid if all variables encountered, both directly
+ * and via fragment spreads, are defined by that operation.
+ */
+func NoUndefinedVariables(context *Context) *Visitor {
+	return &Visitor{
+		Leave: func(node ASTNode) {
+			operation, ok := node.(*OperationDefinition)
+			if !ok {
+				return
+			}
+			for _, usage := range context.RecursiveVariableUsages(operation) {
+				variableName := usage.Variable.Name.Value
+				if operation.VariableDefinitionIndex == nil || operation.VariableDefinitionIndex[variableName] == nil {
+					if operation.Name != nil {
+						context.ReportError(usage.Variable.LOC, "Variable \"$%s\" is not defined by operation %q", variableName, operation.Name.Value)
+					} else {
+						context.ReportError(usage.Variable.LOC, "Variable \"$%s\" is not defined", variableName)
+					}
+				}
+			}
+		},
+	}
+}
+
+/**
+ * No unused variables
+ *
+ * A GraphQL operation is only valid if all variables defined by an operation
+ * are used, either directly or within a spread fragment.
+ */
+func NoUnusedVariables(context *Context) *Visitor {
+	return &Visitor{
+		Leave: func(node ASTNode) {
+			operation, ok := node.(*OperationDefinition)
+			if !ok {
+				return
+			}
+			variableNameUsed := map[string]bool{}
+			for _, usage := range context.RecursiveVariableUsages(operation) {
+				variableNameUsed[usage.Variable.Name.Value] = true
+			}
+			for _, variableDefinition := range operation.VariableDefinitions {
+				variableName := variableDefinition.Variable.Name.Value
+				if !variableNameUsed[variableName] {
+					if operation.Name != nil {
+						context.ReportError(variableDefinition.LOC, "Variable \"$%s\" is never used in operation %q", variableName, operation.Name.Value)
+					} else {
+						context.ReportError(variableDefinition.LOC, "Variable \"$%s\" is never used", variableName)
+					}
+				}
+			}
+		},
+	}
+}
+
+/**
+ * Known directives
+ *
+ * A GraphQL document is only valid if all `@directives` are known by the
+ * schema and legally positioned.
+ */
+func KnownDirectives(context *Context) *Visitor {
+	return &Visitor{
+		Enter: func(node ASTNode) {
+			directive, ok := node.(*Directive)
+			if !ok {
+				return
+			}
+			directiveDefinition := context.Directive()
+			if directiveDefinition == nil {
+				context.ReportError(directive.LOC, "Unknown directive %q", directive.Name.Value)
+				return
+			}
+			location := directiveLocation(context.Parent())
+			for _, allowedLocation := range directiveDefinition.Locations {
+				if allowedLocation.Value == location {
+					return
+				}
+			}
+			context.ReportError(directive.LOC, "Directive %q may not be used on %s", directive.Name.Value, location)
+		},
+	}
+}
+
+func directiveLocation(node ASTNode) string {
+	switch node := node.(type) {
+	case *OperationDefinition:
+		switch node.Operation {
+		case "query":
+			return "QUERY"
+		case "mutation":
+			return "MUTATION"
+		case "subscription":
+			return "SUBSCRIPTION"
+		}
+	case *Field:
+		return "FIELD"
+	case *FragmentSpread:
+		return "FRAGMENT_SPREAD"
+	case *InlineFragment:
+		return "INLINE_FRAGMENT"
+	case *FragmentDefinition:
+		return "FRAGMENT_DEFINITION"
+	}
+	return ""
+}
+
+/**
+ * Unique directives per location
+ *
+ * A GraphQL document is only valid if all directives at a given location
+ * are uniquely named.
+ */
+func UniqueDirectivesPerLocation(context *Context) *Visitor {
+	return &Visitor{
+		Enter: func(node ASTNode) {
+			var directives []*Directive
+			switch node := node.(type) {
+			case *OperationDefinition:
+				directives = node.Directives
+			case *Field:
+				directives = node.Directives
+			case *FragmentSpread:
+				directives = node.Directives
+			case *InlineFragment:
+				directives = node.Directives
+			case *FragmentDefinition:
+				directives = node.Directives
+			}
+			knownDirectives := map[string]bool{}
+			for _, directive := range directives {
+				directiveName := directive.Name.Value
+				if knownDirectives[directiveName] {
+					context.ReportError(directive.LOC, "The directive %q can only be used once at this location", directiveName)
+				} else {
+					knownDirectives[directiveName] = true
+				}
+			}
+		},
+	}
+}
+
+/**
+ * Known argument names
+ *
+ * A GraphQL field is only valid if all supplied arguments are defined by
+ * that field.
+ */
+func KnownArgumentNames(context *Context) *Visitor {
+	return &Visitor{
+		Enter: func(node ASTNode) {
+			argument, ok := node.(*Argument)
+			if !ok || context.Argument() != nil {
+				return
+			}
+			switch context.Parent().(type) {
+			case *Field:
+				fieldDef := context.FieldDef()
+				parentType := context.ParentType()
+				if fieldDef != nil && parentType != nil {
+					context.ReportError(argument.LOC, "Unknown argument %q on field %q of type %q", argument.Name.Value, fieldDef.Name.Value, typeNameOf(parentType))
+				}
+			case *Directive:
+				if directive := context.Directive(); directive != nil {
+					context.ReportError(argument.LOC, "Unknown argument %q on directive \"@%s\"", argument.Name.Value, directive.Name.Value)
+				}
+			}
+		},
+	}
+}
+
+/**
+ * Unique argument names
+ *
+ * A GraphQL field or directive is only valid if all supplied arguments are
+ * uniquely named.
+ */
+func UniqueArgumentNames(context *Context) *Visitor {
+	return &Visitor{
+		Enter: func(node ASTNode) {
+			var arguments []*Argument
+			switch node := node.(type) {
+			case *Field:
+				arguments = node.Arguments
+			case *Directive:
+				arguments = node.Arguments
+			default:
+				return
+			}
+			knownArgumentNames := map[string]bool{}
+			for _, argument := range arguments {
+				argumentName := argument.Name.Value
+				if knownArgumentNames[argumentName] {
+					context.ReportError(argument.Name.LOC, "There can be only one argument named %q", argumentName)
+				} else {
+					knownArgumentNames[argumentName] = true
+				}
+			}
+		},
+	}
+}
+
+/**
+ * Argument values of correct type
+ *
+ * A GraphQL document is only valid if all field argument literal values are
+ * of the type expected by their position.
+ */
+func ArgumentsOfCorrectType(context *Context) *Visitor {
+	return &Visitor{
+		Enter: func(node ASTNode) {
+			argument, ok := node.(*Argument)
+			if !ok {
+				return
+			}
+			argumentDefinition := context.Argument()
+			if argumentDefinition == nil {
+				return
+			}
+			errors := isValidLiteralValue(context.Schema, argumentDefinition.Type, argument.Value)
+			if len(errors) > 0 {
+				context.ReportError(locOf(argument.Value), "Argument %q has invalid value %s.\n%s", argument.Name.Value, Print(argument.Value), strings.Join(errors, "\n"))
+			}
+		},
+	}
+}
+
+/**
+ * Provided required arguments
+ *
+ * A field or directive is only valid if all required (non-null) field arguments
+ * have been provided.
+ */
+func ProvidedNonNullArguments(context *Context) *Visitor {
+	return &Visitor{
+		Leave: func(node ASTNode) {
+			switch node := node.(type) {
+			case *Field:
+				fieldDef := context.FieldDef()
+				if fieldDef == nil {
+					return
+				}
+				for _, argumentDefinition := range fieldDef.Arguments {
+					if _, ok := argumentDefinition.Type.(*NonNullType); !ok {
+						continue
+					}
+					if node.ArgumentIndex == nil || node.ArgumentIndex[argumentDefinition.Name.Value] == nil {
+						context.ReportError(node.LOC, "Field %q argument %q of type %q is required but not provided", node.Name.Value, argumentDefinition.Name.Value, Print(argumentDefinition.Type))
+					}
+				}
+			case *Directive:
+				directiveDefinition := context.Directive()
+				if directiveDefinition == nil {
+					return
+				}
+				for _, argumentDefinition := range directiveDefinition.Arguments {
+					if _, ok := argumentDefinition.Type.(*NonNullType); !ok {
+						continue
+					}
+					if node.ArgumentIndex == nil || node.ArgumentIndex[argumentDefinition.Name.Value] == nil {
+						context.ReportError(node.LOC, "Directive \"@%s\" argument %q of type %q is required but not provided", node.Name.Value, argumentDefinition.Name.Value, Print(argumentDefinition.Type))
+					}
+				}
+			}
+		},
+	}
+}
+
+/**
+ * Variable default values of correct type
+ *
+ * A GraphQL document is only valid if all variable default values are of the
+ * type expected by their definition.
+ */
+func DefaultValuesOfCorrectType(context *Context) *Visitor {
+	return &Visitor{
+		Enter: func(node ASTNode) {
+			variableDefinition, ok := node.(*VariableDefinition)
+			if !ok || variableDefinition.DefaultValue == nil {
+				return
+			}
+			variableName := variableDefinition.Variable.Name.Value
+			defaultValue := variableDefinition.DefaultValue.(ASTNode)
+			if nonNullType, ok := variableDefinition.Type.(*NonNullType); ok {
+				context.ReportError(locOf(defaultValue), "Variable \"$%s\" of type %q is required and will not use the default value. Perhaps you meant to use type %q", variableName, Print(nonNullType), Print(nonNullType.Type))
+				return
+			}
+			if context.NamedType(variableDefinition.Type) == nil {
+				return
+			}
+			errors := isValidLiteralValue(context.Schema, variableDefinition.Type, defaultValue)
+			if len(errors) > 0 {
+				context.ReportError(locOf(defaultValue), "Variable \"$%s\" of type %q has invalid default value %s.\n%s", variableName, Print(variableDefinition.Type), Print(defaultValue), strings.Join(errors, "\n"))
+			}
+		},
+	}
+}
+
+/**
+ * Variables passed to field arguments conform to type
+ */
+func VariablesInAllowedPosition(context *Context) *Visitor {
+	return &Visitor{
+		Leave: func(node ASTNode) {
+			operation, ok := node.(*OperationDefinition)
+			if !ok {
+				return
+			}
+			for _, usage := range context.RecursiveVariableUsages(operation) {
+				variableName := usage.Variable.Name.Value
+				if operation.VariableDefinitionIndex == nil || usage.Type == nil {
+					continue
+				}
+				variableDefinition := operation.VariableDefinitionIndex[variableName]
+				if variableDefinition == nil || context.NamedType(variableDefinition.Type) == nil {
+					continue
+				}
+				// A variable with a default value can be used in a non-null
+				// position since it will never be null.
+				variableType := variableDefinition.Type
+				if _, ok := variableType.(*NonNullType); !ok && variableDefinition.DefaultValue != nil {
+					variableType = &NonNullType{Type: variableType}
+				}
+				if !isTypeSubTypeOf(context.Schema, variableType, usage.Type) {
+					context.ReportError(usage.Variable.LOC, "Variable \"$%s\" of type %q used in position expecting type %q", variableName, Print(variableDefinition.Type), Print(usage.Type))
+				}
+			}
+		},
+	}
+}
+
+/**
+ * Unique input field names
+ *
+ * A GraphQL input object value is only valid if all supplied fields are
+ * uniquely named.
+ */
+func UniqueInputFieldNames(context *Context) *Visitor {
+	return &Visitor{
+		Enter: func(node ASTNode) {
+			object, ok := node.(*Object)
+			if !ok {
+				return
+			}
+			knownNames := map[string]bool{}
+			for _, field := range object.Fields {
+				fieldName := field.Name.Value
+				if knownNames[fieldName] {
+					context.ReportError(field.Name.LOC, "There can be only one input field named %q", fieldName)
+				} else {
+					knownNames[fieldName] = true
+				}
+			}
+		},
+	}
+}
+
+// isValidLiteralValue checks a literal value against an input type and returns
+// the reasons it does not conform. Variables are always considered valid here
+// as they are checked by VariablesInAllowedPosition.
+func isValidLiteralValue(schema *Document, ttype ASTNode, valueAST ASTNode) []string {
+	_, isNull := valueAST.(*Null)
+	if nonNullType, ok := ttype.(*NonNullType); ok {
+		if valueAST == nil || isNull {
+			return []string{fmt.Sprintf("Expected %q, found null.", Print(nonNullType))}
+		}
+		return isValidLiteralValue(schema, nonNullType.Type, valueAST)
+	}
+	if valueAST == nil || isNull {
+		return nil
+	}
+	if _, ok := valueAST.(*Variable); ok {
+		return nil
+	}
+	if listType, ok := ttype.(*ListType); ok {
+		if list, ok := valueAST.(*List); ok {
+			errors := []string{}
+			for index, item := range list.Values {
+				for _, err := range isValidLiteralValue(schema, listType.Type, item) {
+					errors = append(errors, fmt.Sprintf("In element #%d: %s", index, err))
+				}
+			}
+			return errors
+		}
+		return isValidLiteralValue(schema, listType.Type, valueAST)
+	}
+	namedType, ok := ttype.(*NamedType)
+	if !ok || schema.TypeIndex == nil {
+		return nil
+	}
+	switch definition := schema.TypeIndex[namedType.Name.Value].(type) {
+	case *InputObjectTypeDefinition:
+		object, ok := valueAST.(*Object)
+		if !ok {
+			return []string{fmt.Sprintf("Expected %q, found not an object.", namedType.Name.Value)}
+		}
+		errors := []string{}
+		for _, field := range object.Fields {
+			if definition.FieldIndex == nil || definition.FieldIndex[field.Name.Value] == nil {
+				errors = append(errors, fmt.Sprintf("In field %q: Unknown field.", field.Name.Value))
+			}
+		}
+		for _, fieldDefinition := range definition.Fields {
+			var fieldValue ASTNode
+			if object.FieldIndex != nil {
+				if field := object.FieldIndex[fieldDefinition.Name.Value]; field != nil {
+					fieldValue = field.Value
+				}
+			}
+			for _, err := range isValidLiteralValue(schema, fieldDefinition.Type, fieldValue) {
+				errors = append(errors, fmt.Sprintf("In field %q: %s", fieldDefinition.Name.Value, err))
+			}
+		}
+		return errors
+	case *EnumTypeDefinition:
+		if enum, ok := valueAST.(*Enum); ok {
+			for _, value := range definition.Values {
+				if value.Name.Value == enum.Value {
+					return nil
+				}
+			}
+		}
+		return []string{fmt.Sprintf("Expected type %q, found %s.", namedType.Name.Value, Print(valueAST))}
+	case *ScalarTypeDefinition:
+		valid := true
+		switch namedType.Name.Value {
+		case "Int":
+			_, valid = valueAST.(*Int)
+		case "Float":
+			switch valueAST.(type) {
+			case *Int, *Float:
+			default:
+				valid = false
+			}
+		case "String":
+			_, valid = valueAST.(*String)
+		case "Boolean":
+			_, valid = valueAST.(*Boolean)
+		case "ID":
+			switch valueAST.(type) {
+			case *Int, *String:
+			default:
+				valid = false
+			}
+		}
+		if !valid {
+			return []string{fmt.Sprintf("Expected type %q, found %s.", namedType.Name.Value, Print(valueAST))}
+		}
+	}
+	return nil
+}
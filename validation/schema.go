@@ -0,0 +1,288 @@
+package validation
+
+import (
+	. "github.com/playlyfe/go-graphql/language"
+)
+
+type schemaValidator struct {
+	schema *Document
+	errors []*GraphQLError
+}
+
+// ValidateSchema checks that the type system defined by the schema document is
+// consistent and returns every error found, in the order of the definitions.
+// Type extensions are expected to have been merged into the types they extend.
+func ValidateSchema(schema *Document) []*GraphQLError {
+	validator := &schemaValidator{
+		schema: schema,
+	}
+	typeNames := map[string]bool{}
+	directiveNames := map[string]bool{}
+	for _, definition := range schema.Definitions {
+		if name := typeName(definition); name != nil {
+			if typeNames[name.Value] {
+				validator.reportError(name.LOC, "There can be only one type named %q", name.Value)
+				continue
+			}
+			typeNames[name.Value] = true
+		}
+		switch definition := definition.(type) {
+		case *ObjectTypeDefinition:
+			validator.validateObjectType(definition)
+		case *InterfaceTypeDefinition:
+			validator.validateDirectives(definition.Directives, "INTERFACE")
+			validator.validateFields(definition.Name, definition.Fields)
+		case *UnionTypeDefinition:
+			validator.validateUnionType(definition)
+		case *ScalarTypeDefinition:
+			validator.validateDirectives(definition.Directives, "SCALAR")
+		case *EnumTypeDefinition:
+			validator.validateEnumType(definition)
+		case *InputObjectTypeDefinition:
+			validator.validateInputObjectType(definition)
+		case *DirectiveDefinition:
+			name := definition.Name
+			if directiveNames[name.Value] {
+				validator.reportError(name.LOC, "There can be only one directive named %q", name.Value)
+				continue
+			}
+			directiveNames[name.Value] = true
+			for _, directiveDefinition := range SpecifiedDirectives {
+				if directiveDefinition.Name.Value == name.Value {
+					validator.reportError(name.LOC, "Directive %q already exists in the schema. It cannot be redefined", name.Value)
+				}
+			}
+			validator.validateArguments("@"+name.Value, definition.Arguments)
+		case *SchemaDefinition:
+			if definition != schema.SchemaDefinition {
+				validator.reportError(definition.LOC, "Must provide only one schema definition")
+			}
+		}
+	}
+	if schema.SchemaDefinition != nil {
+		validator.validateSchemaDefinition(schema.SchemaDefinition)
+	}
+	return validator.errors
+}
+
+func (validator *schemaValidator) reportError(loc *LOC, format string, args ...interface{}) {
+	validator.errors = append(validator.errors, NewError("Schema", loc, format, args...))
+}
+
+// definedType returns the definition of the named type of ttype, reporting an
+// error if it is not defined.
+func (validator *schemaValidator) definedType(ttype ASTNode) ASTNode {
+	named := namedType(ttype)
+	if named == nil {
+		return nil
+	}
+	definition, ok := validator.schema.TypeIndex[named.Name.Value]
+	if _, isFragment := definition.(*FragmentDefinition); !ok || isFragment {
+		validator.reportError(named.LOC, "Unknown type %q", named.Name.Value)
+		return nil
+	}
+	return definition
+}
+
+// validateSchemaDefinition checks that every root type is an object type and
+// that each operation has a single root type.
+func (validator *schemaValidator) validateSchemaDefinition(schemaDefinition *SchemaDefinition) {
+	validator.validateDirectives(schemaDefinition.Directives, "SCHEMA")
+	operations := map[string]bool{}
+	for _, operationType := range schemaDefinition.OperationTypes {
+		if operations[operationType.Operation] {
+			validator.reportError(operationType.LOC, "There can be only one %s type in schema", operationType.Operation)
+			continue
+		}
+		operations[operationType.Operation] = true
+		definition := validator.definedType(operationType.Type)
+		if definition == nil {
+			continue
+		}
+		if _, ok := definition.(*ObjectTypeDefinition); !ok {
+			validator.reportError(operationType.Type.LOC, "The %s root type must be an Object type, it cannot be %q", operationType.Operation, operationType.Type.Name.Value)
+		}
+	}
+}
+
+func (validator *schemaValidator) validateObjectType(objectType *ObjectTypeDefinition) {
+	validator.validateDirectives(objectType.Directives, "OBJECT")
+	validator.validateFields(objectType.Name, objectType.Fields)
+	implemented := map[string]bool{}
+	for _, namedType := range objectType.Interfaces {
+		definition := validator.definedType(namedType)
+		if definition == nil {
+			continue
+		}
+		interfaceType, ok := definition.(*InterfaceTypeDefinition)
+		if !ok {
+			validator.reportError(namedType.LOC, "Type %q must only implement Interface types, it cannot implement %q", objectType.Name.Value, namedType.Name.Value)
+			continue
+		}
+		if implemented[interfaceType.Name.Value] {
+			validator.reportError(namedType.LOC, "Type %q can only implement %q once", objectType.Name.Value, interfaceType.Name.Value)
+			continue
+		}
+		implemented[interfaceType.Name.Value] = true
+		validator.validateImplementation(objectType, interfaceType, namedType.LOC)
+	}
+}
+
+// validateImplementation checks that the object type provides every field of
+// the interface with a compatible type and the same arguments.
+func (validator *schemaValidator) validateImplementation(objectType *ObjectTypeDefinition, interfaceType *InterfaceTypeDefinition, loc *LOC) {
+	for _, interfaceField := range interfaceType.Fields {
+		objectField := objectType.FieldIndex[interfaceField.Name.Value]
+		if objectField == nil {
+			validator.reportError(loc, "Interface field \"%s.%s\" expected but %q does not provide it", interfaceType.Name.Value, interfaceField.Name.Value, objectType.Name.Value)
+			continue
+		}
+		if !isTypeSubTypeOf(validator.schema, objectField.Type, interfaceField.Type) {
+			validator.reportError(locOf(objectField.Type), "Interface field \"%s.%s\" expects type %q but \"%s.%s\" is type %q", interfaceType.Name.Value, interfaceField.Name.Value, Print(interfaceField.Type), objectType.Name.Value, objectField.Name.Value, Print(objectField.Type))
+		}
+		for _, interfaceArgument := range interfaceField.Arguments {
+			objectArgument := objectField.ArgumentIndex[interfaceArgument.Name.Value]
+			if objectArgument == nil {
+				validator.reportError(objectField.Name.LOC, "Interface field argument \"%s.%s(%s:)\" expected but \"%s.%s\" does not provide it", interfaceType.Name.Value, interfaceField.Name.Value, interfaceArgument.Name.Value, objectType.Name.Value, objectField.Name.Value)
+				continue
+			}
+			if !isEqualType(interfaceArgument.Type, objectArgument.Type) {
+				validator.reportError(locOf(objectArgument.Type), "Interface field argument \"%s.%s(%s:)\" expects type %q but \"%s.%s(%s:)\" is type %q", interfaceType.Name.Value, interfaceField.Name.Value, interfaceArgument.Name.Value, Print(interfaceArgument.Type), objectType.Name.Value, objectField.Name.Value, objectArgument.Name.Value, Print(objectArgument.Type))
+			}
+		}
+		for _, objectArgument := range objectField.Arguments {
+			if _, ok := objectArgument.Type.(*NonNullType); !ok {
+				continue
+			}
+			found := false
+			for _, interfaceArgument := range interfaceField.Arguments {
+				if interfaceArgument.Name.Value == objectArgument.Name.Value {
+					found = true
+					break
+				}
+			}
+			if !found {
+				validator.reportError(objectArgument.Name.LOC, "Object field argument \"%s.%s(%s:)\" is of required type %q but is not also provided by the Interface field \"%s.%s\"", objectType.Name.Value, objectField.Name.Value, objectArgument.Name.Value, Print(objectArgument.Type), interfaceType.Name.Value, interfaceField.Name.Value)
+			}
+		}
+	}
+}
+
+func (validator *schemaValidator) validateFields(typeName *Name, fields []*FieldDefinition) {
+	if len(fields) == 0 {
+		validator.reportError(typeName.LOC, "Type %q must define one or more fields", typeName.Value)
+	}
+	fieldNames := map[string]bool{}
+	for _, field := range fields {
+		coordinate := typeName.Value + "." + field.Name.Value
+		if fieldNames[field.Name.Value] {
+			validator.reportError(field.Name.LOC, "Field %q can only be defined once", coordinate)
+			continue
+		}
+		fieldNames[field.Name.Value] = true
+		if definition := validator.definedType(field.Type); definition != nil {
+			if _, ok := definition.(*InputObjectTypeDefinition); ok {
+				validator.reportError(locOf(field.Type), "The type of %q must be Output Type but got: %s", coordinate, Print(field.Type))
+			}
+		}
+		validator.validateArguments(coordinate, field.Arguments)
+		validator.validateDirectives(field.Directives, "FIELD_DEFINITION")
+	}
+}
+
+func (validator *schemaValidator) validateArguments(coordinate string, arguments []*InputValueDefinition) {
+	argumentNames := map[string]bool{}
+	for _, argument := range arguments {
+		argumentCoordinate := coordinate + "(" + argument.Name.Value + ":)"
+		if argumentNames[argument.Name.Value] {
+			validator.reportError(argument.Name.LOC, "Argument %q can only be defined once", argumentCoordinate)
+			continue
+		}
+		argumentNames[argument.Name.Value] = true
+		validator.validateInputValue(argumentCoordinate, argument, "ARGUMENT_DEFINITION")
+	}
+}
+
+func (validator *schemaValidator) validateInputValue(coordinate string, inputValue *InputValueDefinition, location string) {
+	if definition := validator.definedType(inputValue.Type); definition != nil && !isInputType(definition) {
+		validator.reportError(locOf(inputValue.Type), "The type of %q must be Input Type but got: %s", coordinate, Print(inputValue.Type))
+	}
+	validator.validateDirectives(inputValue.Directives, location)
+}
+
+func (validator *schemaValidator) validateUnionType(unionType *UnionTypeDefinition) {
+	validator.validateDirectives(unionType.Directives, "UNION")
+	if len(unionType.Types) == 0 {
+		validator.reportError(unionType.Name.LOC, "Union type %q must define one or more member types", unionType.Name.Value)
+	}
+	memberNames := map[string]bool{}
+	for _, namedType := range unionType.Types {
+		definition := validator.definedType(namedType)
+		if definition == nil {
+			continue
+		}
+		if _, ok := definition.(*ObjectTypeDefinition); !ok {
+			validator.reportError(namedType.LOC, "Union type %q can only include Object types, it cannot include %q", unionType.Name.Value, namedType.Name.Value)
+			continue
+		}
+		if memberNames[namedType.Name.Value] {
+			validator.reportError(namedType.LOC, "Union type %q can only include type %q once", unionType.Name.Value, namedType.Name.Value)
+		}
+		memberNames[namedType.Name.Value] = true
+	}
+}
+
+func (validator *schemaValidator) validateEnumType(enumType *EnumTypeDefinition) {
+	validator.validateDirectives(enumType.Directives, "ENUM")
+	if len(enumType.Values) == 0 {
+		validator.reportError(enumType.Name.LOC, "Enum type %q must define one or more values", enumType.Name.Value)
+	}
+	valueNames := map[string]bool{}
+	for _, value := range enumType.Values {
+		if valueNames[value.Name.Value] {
+			validator.reportError(value.Name.LOC, "Enum value \"%s.%s\" can only be defined once", enumType.Name.Value, value.Name.Value)
+			continue
+		}
+		valueNames[value.Name.Value] = true
+		validator.validateDirectives(value.Directives, "ENUM_VALUE")
+	}
+}
+
+func (validator *schemaValidator) validateInputObjectType(inputObjectType *InputObjectTypeDefinition) {
+	validator.validateDirectives(inputObjectType.Directives, "INPUT_OBJECT")
+	if len(inputObjectType.Fields) == 0 {
+		validator.reportError(inputObjectType.Name.LOC, "Input Object type %q must define one or more fields", inputObjectType.Name.Value)
+	}
+	fieldNames := map[string]bool{}
+	for _, field := range inputObjectType.Fields {
+		coordinate := inputObjectType.Name.Value + "." + field.Name.Value
+		if fieldNames[field.Name.Value] {
+			validator.reportError(field.Name.LOC, "Field %q can only be defined once", coordinate)
+			continue
+		}
+		fieldNames[field.Name.Value] = true
+		validator.validateInputValue(coordinate, field, "INPUT_FIELD_DEFINITION")
+	}
+}
+
+// validateDirectives checks that the directives applied to a type system
+// element are defined and may be used at its location.
+func (validator *schemaValidator) validateDirectives(directives []*Directive, location string) {
+	for _, directive := range directives {
+		directiveDefinition := LookupDirective(validator.schema, directive.Name.Value)
+		if directiveDefinition == nil {
+			validator.reportError(directive.LOC, "Unknown directive %q", directive.Name.Value)
+			continue
+		}
+		allowed := false
+		for _, allowedLocation := range directiveDefinition.Locations {
+			if allowedLocation.Value == location {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			validator.reportError(directive.LOC, "Directive %q may not be used on %s", directive.Name.Value, location)
+		}
+	}
+}
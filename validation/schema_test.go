@@ -0,0 +1,97 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/playlyfe/go-graphql/language"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func validateSchema(source string) []string {
+	parser := &language.Parser{}
+	schema, err := parser.Parse(&language.ParseParams{
+		Source: source,
+	})
+	So(err, ShouldEqual, nil)
+	messages := []string{}
+	for _, err := range ValidateSchema(schema) {
+		messages = append(messages, err.Message)
+	}
+	return messages
+}
+
+func TestValidateSchema(t *testing.T) {
+
+	Convey("ValidateSchema: Type system", t, func() {
+
+		Convey("accepts a consistent schema", func() {
+			So(validateSchema(testSchema+`
+directive @cost(value: Int!) on FIELD_DEFINITION | OBJECT
+
+interface Named {
+    name(format: String): String
+}
+
+type Person implements Named @cost(value: 1) {
+    name(format: String, upper: Boolean): String! @cost(value: 2)
+    pets: [Pet!]
+}
+`), ShouldResemble, []string{})
+		})
+
+		Convey("reports every error with its location", func() {
+			So(validateSchema(`
+scalar String
+scalar Boolean
+interface Pet { name(format: String): String }
+type Dog implements Pet, Pet, Bone { name: Int, name: String }
+type Cat implements Pet { name(upper: Boolean!): String }
+type Bone { size: Size, owner: Owner, filter: Filter }
+union Animal = Dog | Pet | Dog
+enum Size { SMALL, SMALL }
+input Filter { size: Size, dog: Dog, size: String }
+type Dog { id: String }
+type Empty {}
+directive @skip(if: Boolean!) on FIELD
+directive @tag(dog: Dog) on OBJECT
+directive @tag on OBJECT
+type Tagged @tag @unknown @deprecated { id: String @tag }
+`), ShouldResemble, []string{
+				`GraphQL Schema Error (5:44) Unknown type "Int"`,
+				`GraphQL Schema Error (5:49) Field "Dog.name" can only be defined once`,
+				`GraphQL Schema Error (5:49) Interface field argument "Pet.name(format:)" expected but "Dog.name" does not provide it`,
+				`GraphQL Schema Error (5:26) Type "Dog" can only implement "Pet" once`,
+				`GraphQL Schema Error (5:31) Type "Dog" must only implement Interface types, it cannot implement "Bone"`,
+				`GraphQL Schema Error (6:27) Interface field argument "Pet.name(format:)" expected but "Cat.name" does not provide it`,
+				`GraphQL Schema Error (6:32) Object field argument "Cat.name(upper:)" is of required type "Boolean!" but is not also provided by the Interface field "Pet.name"`,
+				`GraphQL Schema Error (7:32) Unknown type "Owner"`,
+				`GraphQL Schema Error (7:47) The type of "Bone.filter" must be Output Type but got: Filter`,
+				`GraphQL Schema Error (8:22) Union type "Animal" can only include Object types, it cannot include "Pet"`,
+				`GraphQL Schema Error (8:28) Union type "Animal" can only include type "Dog" once`,
+				`GraphQL Schema Error (9:20) Enum value "Size.SMALL" can only be defined once`,
+				`GraphQL Schema Error (10:33) The type of "Filter.dog" must be Input Type but got: Dog`,
+				`GraphQL Schema Error (10:38) Field "Filter.size" can only be defined once`,
+				`GraphQL Schema Error (11:6) There can be only one type named "Dog"`,
+				`GraphQL Schema Error (12:6) Type "Empty" must define one or more fields`,
+				`GraphQL Schema Error (13:12) Directive "skip" already exists in the schema. It cannot be redefined`,
+				`GraphQL Schema Error (14:21) The type of "@tag(dog:)" must be Input Type but got: Dog`,
+				`GraphQL Schema Error (15:12) There can be only one directive named "tag"`,
+				`GraphQL Schema Error (16:18) Unknown directive "unknown"`,
+				`GraphQL Schema Error (16:27) Directive "deprecated" may not be used on OBJECT`,
+				`GraphQL Schema Error (16:52) Directive "tag" may not be used on FIELD_DEFINITION`,
+			})
+		})
+
+		Convey("reports incompatible implementations", func() {
+			So(validateSchema(`
+scalar String
+interface Node { id: String!, parent(depth: String): Node }
+type Item implements Node { id: String, parent(depth: [String]): Item }
+`), ShouldResemble, []string{
+				`GraphQL Schema Error (4:33) Interface field "Node.id" expects type "String!" but "Item.id" is type "String"`,
+				`GraphQL Schema Error (4:55) Interface field argument "Node.parent(depth:)" expects type "String" but "Item.parent(depth:)" is type "[String]"`,
+			})
+		})
+	})
+
+}
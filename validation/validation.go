@@ -0,0 +1,781 @@
+package validation
+
+import (
+	"fmt"
+
+	. "github.com/playlyfe/go-graphql/language"
+)
+
+type Visitor struct {
+	Enter func(node ASTNode)
+	Leave func(node ASTNode)
+}
+
+type Rule func(context *Context) *Visitor
+
+type ValidateParams struct {
+	Schema           *Document
+	QueryRoot        *ObjectTypeDefinition
+	MutationRoot     *ObjectTypeDefinition
+	SubscriptionRoot *ObjectTypeDefinition
+	Document         *Document
+	Rules            []Rule
+}
+
+type VariableUsage struct {
+	Variable *Variable
+	Type     ASTNode
+}
+
+type Context struct {
+	Schema           *Document
+	QueryRoot        *ObjectTypeDefinition
+	MutationRoot     *ObjectTypeDefinition
+	SubscriptionRoot *ObjectTypeDefinition
+	Document         *Document
+	Errors           []*GraphQLError
+
+	ancestors       []ASTNode
+	typeStack       []ASTNode
+	parentTypeStack []ASTNode
+	inputTypeStack  []ASTNode
+	fieldDefStack   []*FieldDefinition
+	directive       *DirectiveDefinition
+	argument        *InputValueDefinition
+
+	fragmentSpreads         map[*SelectionSet][]*FragmentSpread
+	recursivelyReferenced   map[*OperationDefinition][]*FragmentDefinition
+	variableUsages          map[ASTNode][]*VariableUsage
+	recursiveVariableUsages map[*OperationDefinition][]*VariableUsage
+}
+
+var booleanType = &NonNullType{
+	Type: &NamedType{
+		Name: &Name{
+			Value: "Boolean",
+		},
+	},
+}
+
+var ifArgument = &InputValueDefinition{
+	Name: &Name{
+		Value: "if",
+	},
+	Description: "The condition value",
+	Type:        booleanType,
+}
+
+var reasonArgument = &InputValueDefinition{
+	Name: &Name{
+		Value: "reason",
+	},
+	Description: "Explains why the element was deprecated",
+	Type: &NamedType{
+		Name: &Name{
+			Value: "String",
+		},
+	},
+	DefaultValue: &String{
+		Value: DEFAULT_DEPRECATION_REASON,
+	},
+}
+
+var conditionArgument = &InputValueDefinition{
+	Name: &Name{
+		Value: "if",
+	},
+	Description: "Delivers the element incrementally when true, which is the default",
+	Type: &NamedType{
+		Name: &Name{
+			Value: "Boolean",
+		},
+	},
+	DefaultValue: &Boolean{
+		Value: true,
+	},
+}
+
+var labelArgument = &InputValueDefinition{
+	Name: &Name{
+		Value: "label",
+	},
+	Description: "Identifies the payloads delivering the element",
+	Type: &NamedType{
+		Name: &Name{
+			Value: "String",
+		},
+	},
+}
+
+var initialCountArgument = &InputValueDefinition{
+	Name: &Name{
+		Value: "initialCount",
+	},
+	Description: "The number of items sent in the initial payload",
+	Type: &NamedType{
+		Name: &Name{
+			Value: "Int",
+		},
+	},
+	DefaultValue: &Int{
+		Value: 0,
+	},
+}
+
+func locations(names ...string) []*Name {
+	locations := []*Name{}
+	for _, name := range names {
+		locations = append(locations, &Name{Value: name})
+	}
+	return locations
+}
+
+// SpecifiedDirectives are the directives defined by the GraphQL specification,
+// along with the @defer and @stream directives of its incremental delivery
+// proposal. They are available in every schema in addition to the directives
+// defined by the schema itself.
+var SpecifiedDirectives = []*DirectiveDefinition{
+	{
+		Name:          &Name{Value: "skip"},
+		Description:   "Conditionally exclude a field or fragment during execution",
+		Arguments:     []*InputValueDefinition{ifArgument},
+		ArgumentIndex: map[string]*InputValueDefinition{"if": ifArgument},
+		Locations:     locations("FIELD", "FRAGMENT_SPREAD", "INLINE_FRAGMENT"),
+	},
+	{
+		Name:          &Name{Value: "include"},
+		Description:   "Conditionally include a field or fragment during execution",
+		Arguments:     []*InputValueDefinition{ifArgument},
+		ArgumentIndex: map[string]*InputValueDefinition{"if": ifArgument},
+		Locations:     locations("FIELD", "FRAGMENT_SPREAD", "INLINE_FRAGMENT"),
+	},
+	{
+		Name:          &Name{Value: "deprecated"},
+		Description:   "Marks an element of the schema as no longer supported",
+		Arguments:     []*InputValueDefinition{reasonArgument},
+		ArgumentIndex: map[string]*InputValueDefinition{"reason": reasonArgument},
+		Locations:     locations("FIELD_DEFINITION", "ARGUMENT_DEFINITION", "INPUT_FIELD_DEFINITION", "ENUM_VALUE"),
+	},
+	{
+		Name:          &Name{Value: "defer"},
+		Description:   "Delivers a fragment after the rest of the response",
+		Arguments:     []*InputValueDefinition{conditionArgument, labelArgument},
+		ArgumentIndex: map[string]*InputValueDefinition{"if": conditionArgument, "label": labelArgument},
+		Locations:     locations("FRAGMENT_SPREAD", "INLINE_FRAGMENT"),
+	},
+	{
+		Name:          &Name{Value: "stream"},
+		Description:   "Delivers the items of a list field after the rest of the response",
+		Arguments:     []*InputValueDefinition{conditionArgument, labelArgument, initialCountArgument},
+		ArgumentIndex: map[string]*InputValueDefinition{"if": conditionArgument, "label": labelArgument, "initialCount": initialCountArgument},
+		Locations:     locations("FIELD"),
+	},
+}
+
+// LookupDirective returns the definition of the named directive from the
+// schema, or from the specified directives if the schema does not define it.
+func LookupDirective(schema *Document, name string) *DirectiveDefinition {
+	if schema != nil {
+		if directiveDefinition, ok := schema.DirectiveIndex[name]; ok {
+			return directiveDefinition
+		}
+	}
+	for _, directiveDefinition := range SpecifiedDirectives {
+		if directiveDefinition.Name.Value == name {
+			return directiveDefinition
+		}
+	}
+	return nil
+}
+
+// Validate runs the given rules against the request document and returns every
+// error found. An empty result means the document may be executed.
+func Validate(params *ValidateParams) []*GraphQLError {
+	context := &Context{
+		Schema:                  params.Schema,
+		QueryRoot:               params.QueryRoot,
+		MutationRoot:            params.MutationRoot,
+		SubscriptionRoot:        params.SubscriptionRoot,
+		Document:                params.Document,
+		fragmentSpreads:         map[*SelectionSet][]*FragmentSpread{},
+		recursivelyReferenced:   map[*OperationDefinition][]*FragmentDefinition{},
+		variableUsages:          map[ASTNode][]*VariableUsage{},
+		recursiveVariableUsages: map[*OperationDefinition][]*VariableUsage{},
+	}
+	visitors := []*Visitor{}
+	for _, rule := range params.Rules {
+		visitors = append(visitors, rule(context))
+	}
+	context.walk(params.Document, visitors)
+	return context.Errors
+}
+
+// NewError returns a GraphQL error of the given kind, such as "Validation" or
+// "Schema", pointing at the location of the offending node when it is known.
+func NewError(kind string, loc *LOC, format string, args ...interface{}) *GraphQLError {
+	err := &GraphQLError{}
+	if loc != nil && loc.Start != nil {
+		err.Message = fmt.Sprintf("GraphQL %s Error (%d:%d) ", kind, loc.Start.Line, loc.Start.Column) + fmt.Sprintf(format, args...)
+		err.Source = loc.Source
+		err.Start = loc.Start
+		err.End = loc.End
+	} else {
+		err.Message = "GraphQL " + kind + " Error " + fmt.Sprintf(format, args...)
+	}
+	return err
+}
+
+func (context *Context) ReportError(loc *LOC, format string, args ...interface{}) {
+	context.Errors = append(context.Errors, NewError("Validation", loc, format, args...))
+}
+
+func (context *Context) Fragment(name string) *FragmentDefinition {
+	if context.Document.FragmentIndex == nil {
+		return nil
+	}
+	return context.Document.FragmentIndex[name]
+}
+
+// FragmentSpreads returns the fragment spreads found within a selection set,
+// including those nested in inline fragments and sub selections.
+func (context *Context) FragmentSpreads(selectionSet *SelectionSet) []*FragmentSpread {
+	if spreads, ok := context.fragmentSpreads[selectionSet]; ok {
+		return spreads
+	}
+	spreads := []*FragmentSpread{}
+	setsToVisit := []*SelectionSet{selectionSet}
+	for len(setsToVisit) > 0 {
+		set := setsToVisit[len(setsToVisit)-1]
+		setsToVisit = setsToVisit[:len(setsToVisit)-1]
+		for _, selection := range set.Selections {
+			switch selection := selection.(type) {
+			case *FragmentSpread:
+				spreads = append(spreads, selection)
+			case *Field:
+				if selection.SelectionSet != nil {
+					setsToVisit = append(setsToVisit, selection.SelectionSet)
+				}
+			case *InlineFragment:
+				if selection.SelectionSet != nil {
+					setsToVisit = append(setsToVisit, selection.SelectionSet)
+				}
+			}
+		}
+	}
+	context.fragmentSpreads[selectionSet] = spreads
+	return spreads
+}
+
+// RecursivelyReferencedFragments returns every fragment reachable from the
+// operation through fragment spreads.
+func (context *Context) RecursivelyReferencedFragments(operation *OperationDefinition) []*FragmentDefinition {
+	if fragments, ok := context.recursivelyReferenced[operation]; ok {
+		return fragments
+	}
+	fragments := []*FragmentDefinition{}
+	collectedNames := map[string]bool{}
+	nodesToVisit := []*SelectionSet{operation.SelectionSet}
+	for len(nodesToVisit) > 0 {
+		node := nodesToVisit[len(nodesToVisit)-1]
+		nodesToVisit = nodesToVisit[:len(nodesToVisit)-1]
+		for _, spread := range context.FragmentSpreads(node) {
+			fragmentName := spread.Name.Value
+			if collectedNames[fragmentName] {
+				continue
+			}
+			collectedNames[fragmentName] = true
+			fragment := context.Fragment(fragmentName)
+			if fragment != nil {
+				fragments = append(fragments, fragment)
+				nodesToVisit = append(nodesToVisit, fragment.SelectionSet)
+			}
+		}
+	}
+	context.recursivelyReferenced[operation] = fragments
+	return fragments
+}
+
+// VariableUsages returns the variables used within an operation or fragment
+// definition along with the input type expected at each position.
+func (context *Context) VariableUsages(node ASTNode) []*VariableUsage {
+	if usages, ok := context.variableUsages[node]; ok {
+		return usages
+	}
+	usages := []*VariableUsage{}
+	collector := &Context{
+		Schema:           context.Schema,
+		QueryRoot:        context.QueryRoot,
+		MutationRoot:     context.MutationRoot,
+		SubscriptionRoot: context.SubscriptionRoot,
+		Document:         context.Document,
+	}
+	collector.walk(node, []*Visitor{
+		{
+			Enter: func(node ASTNode) {
+				if variable, ok := node.(*Variable); ok {
+					if _, ok := collector.Parent().(*VariableDefinition); ok {
+						return
+					}
+					usages = append(usages, &VariableUsage{
+						Variable: variable,
+						Type:     collector.InputType(),
+					})
+				}
+			},
+		},
+	})
+	context.variableUsages[node] = usages
+	return usages
+}
+
+// RecursiveVariableUsages returns the variables used within an operation and
+// every fragment it references.
+func (context *Context) RecursiveVariableUsages(operation *OperationDefinition) []*VariableUsage {
+	if usages, ok := context.recursiveVariableUsages[operation]; ok {
+		return usages
+	}
+	usages := []*VariableUsage{}
+	usages = append(usages, context.VariableUsages(operation)...)
+	for _, fragment := range context.RecursivelyReferencedFragments(operation) {
+		usages = append(usages, context.VariableUsages(fragment)...)
+	}
+	context.recursiveVariableUsages[operation] = usages
+	return usages
+}
+
+func (context *Context) Type() ASTNode {
+	if len(context.typeStack) == 0 {
+		return nil
+	}
+	return context.typeStack[len(context.typeStack)-1]
+}
+
+func (context *Context) ParentType() ASTNode {
+	if len(context.parentTypeStack) == 0 {
+		return nil
+	}
+	return context.parentTypeStack[len(context.parentTypeStack)-1]
+}
+
+func (context *Context) InputType() ASTNode {
+	if len(context.inputTypeStack) == 0 {
+		return nil
+	}
+	return context.inputTypeStack[len(context.inputTypeStack)-1]
+}
+
+func (context *Context) FieldDef() *FieldDefinition {
+	if len(context.fieldDefStack) == 0 {
+		return nil
+	}
+	return context.fieldDefStack[len(context.fieldDefStack)-1]
+}
+
+func (context *Context) Directive() *DirectiveDefinition {
+	return context.directive
+}
+
+func (context *Context) Argument() *InputValueDefinition {
+	return context.argument
+}
+
+// Parent returns the node enclosing the node currently being visited.
+func (context *Context) Parent() ASTNode {
+	if len(context.ancestors) < 2 {
+		return nil
+	}
+	return context.ancestors[len(context.ancestors)-2]
+}
+
+func (context *Context) NamedType(ttype ASTNode) ASTNode {
+	namedType := namedType(ttype)
+	if namedType == nil || context.Schema.TypeIndex == nil {
+		return nil
+	}
+	return context.Schema.TypeIndex[namedType.Name.Value]
+}
+
+func (context *Context) enter(node ASTNode) {
+	context.ancestors = append(context.ancestors, node)
+	switch node := node.(type) {
+	case *SelectionSet:
+		var parentType ASTNode
+		definition := context.NamedType(context.Type())
+		if isCompositeType(definition) {
+			parentType = definition
+		}
+		context.parentTypeStack = append(context.parentTypeStack, parentType)
+	case *Field:
+		var fieldDef *FieldDefinition
+		var fieldType ASTNode
+		parentType := context.ParentType()
+		if parentType != nil {
+			fieldDef = fieldDefinition(context.Schema, parentType, node.Name.Value)
+			if fieldDef != nil {
+				fieldType = fieldDef.Type
+			}
+		}
+		context.fieldDefStack = append(context.fieldDefStack, fieldDef)
+		context.typeStack = append(context.typeStack, fieldType)
+	case *Directive:
+		context.directive = LookupDirective(context.Schema, node.Name.Value)
+	case *OperationDefinition:
+		var ttype ASTNode
+		switch node.Operation {
+		case "query":
+			if context.QueryRoot != nil {
+				ttype = &NamedType{Name: context.QueryRoot.Name}
+			}
+		case "mutation":
+			if context.MutationRoot != nil {
+				ttype = &NamedType{Name: context.MutationRoot.Name}
+			}
+		case "subscription":
+			if context.SubscriptionRoot != nil {
+				ttype = &NamedType{Name: context.SubscriptionRoot.Name}
+			}
+		}
+		context.typeStack = append(context.typeStack, ttype)
+	case *InlineFragment:
+		var ttype ASTNode
+		if node.TypeCondition != nil {
+			ttype = node.TypeCondition
+		} else if parentType := context.ParentType(); parentType != nil {
+			ttype = &NamedType{Name: typeName(parentType)}
+		}
+		context.typeStack = append(context.typeStack, ttype)
+	case *FragmentDefinition:
+		context.typeStack = append(context.typeStack, node.TypeCondition)
+	case *VariableDefinition:
+		context.inputTypeStack = append(context.inputTypeStack, node.Type)
+	case *Argument:
+		var argDef *InputValueDefinition
+		var argType ASTNode
+		if _, ok := context.Parent().(*Directive); ok {
+			if context.directive != nil {
+				argDef = context.directive.ArgumentIndex[node.Name.Value]
+			}
+		} else if fieldDef := context.FieldDef(); fieldDef != nil && fieldDef.ArgumentIndex != nil {
+			argDef = fieldDef.ArgumentIndex[node.Name.Value]
+		}
+		if argDef != nil {
+			argType = argDef.Type
+		}
+		context.argument = argDef
+		context.inputTypeStack = append(context.inputTypeStack, argType)
+	case *List:
+		var itemType ASTNode
+		listType := nullableType(context.InputType())
+		if listType, ok := listType.(*ListType); ok {
+			itemType = listType.Type
+		} else {
+			itemType = listType
+		}
+		context.inputTypeStack = append(context.inputTypeStack, itemType)
+	case *ObjectField:
+		var fieldType ASTNode
+		if inputType, ok := context.NamedType(context.InputType()).(*InputObjectTypeDefinition); ok && inputType.FieldIndex != nil {
+			if inputField := inputType.FieldIndex[node.Name.Value]; inputField != nil {
+				fieldType = inputField.Type
+			}
+		}
+		context.inputTypeStack = append(context.inputTypeStack, fieldType)
+	}
+}
+
+func (context *Context) leave(node ASTNode) {
+	switch node.(type) {
+	case *SelectionSet:
+		context.parentTypeStack = context.parentTypeStack[:len(context.parentTypeStack)-1]
+	case *Field:
+		context.fieldDefStack = context.fieldDefStack[:len(context.fieldDefStack)-1]
+		context.typeStack = context.typeStack[:len(context.typeStack)-1]
+	case *Directive:
+		context.directive = nil
+	case *OperationDefinition, *InlineFragment, *FragmentDefinition:
+		context.typeStack = context.typeStack[:len(context.typeStack)-1]
+	case *Argument:
+		context.argument = nil
+		context.inputTypeStack = context.inputTypeStack[:len(context.inputTypeStack)-1]
+	case *VariableDefinition, *List, *ObjectField:
+		context.inputTypeStack = context.inputTypeStack[:len(context.inputTypeStack)-1]
+	}
+	context.ancestors = context.ancestors[:len(context.ancestors)-1]
+}
+
+// walk visits the node with the given visitors while tracking the type
+// information exposed by the context. Only executable definitions are
+// descended into and names are not visited.
+func (context *Context) walk(node ASTNode, visitors []*Visitor) {
+	leave := func(node ASTNode) {
+		for _, visitor := range visitors {
+			if visitor.Leave != nil {
+				visitor.Leave(node)
+			}
+		}
+		context.leave(node)
+	}
+	Visit(node, &ASTVisitor{
+		Enter: func(params *VisitParams) (VisitAction, ASTNode) {
+			if _, ok := params.Node.(*Name); ok {
+				return VISIT_SKIP, nil
+			}
+			context.enter(params.Node)
+			for _, visitor := range visitors {
+				if visitor.Enter != nil {
+					visitor.Enter(params.Node)
+				}
+			}
+			switch params.Node.(type) {
+			case *ObjectTypeDefinition, *InterfaceTypeDefinition, *UnionTypeDefinition, *ScalarTypeDefinition,
+				*EnumTypeDefinition, *InputObjectTypeDefinition, *TypeExtensionDefinition, *DirectiveDefinition,
+				*SchemaDefinition, *SchemaExtensionDefinition:
+				leave(params.Node)
+				return VISIT_SKIP, nil
+			}
+			return VISIT_CONTINUE, nil
+		},
+		Leave: func(params *VisitParams) (VisitAction, ASTNode) {
+			leave(params.Node)
+			return VISIT_CONTINUE, nil
+		},
+	})
+}
+
+func namedType(ttype ASTNode) *NamedType {
+	for {
+		switch unmodifiedType := ttype.(type) {
+		case *NonNullType:
+			ttype = unmodifiedType.Type
+		case *ListType:
+			ttype = unmodifiedType.Type
+		case *NamedType:
+			return unmodifiedType
+		default:
+			return nil
+		}
+	}
+}
+
+func nullableType(ttype ASTNode) ASTNode {
+	if nonNullType, ok := ttype.(*NonNullType); ok {
+		return nonNullType.Type
+	}
+	return ttype
+}
+
+func typeName(definition ASTNode) *Name {
+	switch definition := definition.(type) {
+	case *ObjectTypeDefinition:
+		return definition.Name
+	case *InterfaceTypeDefinition:
+		return definition.Name
+	case *UnionTypeDefinition:
+		return definition.Name
+	case *ScalarTypeDefinition:
+		return definition.Name
+	case *EnumTypeDefinition:
+		return definition.Name
+	case *InputObjectTypeDefinition:
+		return definition.Name
+	}
+	return nil
+}
+
+func isCompositeType(definition ASTNode) bool {
+	switch definition.(type) {
+	case *ObjectTypeDefinition, *InterfaceTypeDefinition, *UnionTypeDefinition:
+		return true
+	}
+	return false
+}
+
+func isLeafType(definition ASTNode) bool {
+	switch definition.(type) {
+	case *ScalarTypeDefinition, *EnumTypeDefinition:
+		return true
+	}
+	return false
+}
+
+func isInputType(definition ASTNode) bool {
+	switch definition.(type) {
+	case *ScalarTypeDefinition, *EnumTypeDefinition, *InputObjectTypeDefinition:
+		return true
+	}
+	return false
+}
+
+func isAbstractType(definition ASTNode) bool {
+	switch definition.(type) {
+	case *InterfaceTypeDefinition, *UnionTypeDefinition:
+		return true
+	}
+	return false
+}
+
+var typenameFieldDefinition = &FieldDefinition{
+	Name: &Name{
+		Value: "__typename",
+	},
+	Type: &NonNullType{
+		Type: &NamedType{
+			Name: &Name{
+				Value: "String",
+			},
+		},
+	},
+}
+
+func fieldDefinition(schema *Document, parentType ASTNode, fieldName string) *FieldDefinition {
+	if fieldName == "__typename" {
+		return typenameFieldDefinition
+	}
+	switch parentType := parentType.(type) {
+	case *ObjectTypeDefinition:
+		if parentType.FieldIndex != nil {
+			return parentType.FieldIndex[fieldName]
+		}
+	case *InterfaceTypeDefinition:
+		for _, field := range parentType.Fields {
+			if field.Name.Value == fieldName {
+				return field
+			}
+		}
+	}
+	return nil
+}
+
+func isEqualType(typeA ASTNode, typeB ASTNode) bool {
+	switch typeA := typeA.(type) {
+	case *NonNullType:
+		if typeB, ok := typeB.(*NonNullType); ok {
+			return isEqualType(typeA.Type, typeB.Type)
+		}
+	case *ListType:
+		if typeB, ok := typeB.(*ListType); ok {
+			return isEqualType(typeA.Type, typeB.Type)
+		}
+	case *NamedType:
+		if typeB, ok := typeB.(*NamedType); ok {
+			return typeA.Name.Value == typeB.Name.Value
+		}
+	}
+	return false
+}
+
+// isTypeSubTypeOf reports whether a value of maybeSubType may be used where
+// superType is expected.
+func isTypeSubTypeOf(schema *Document, maybeSubType ASTNode, superType ASTNode) bool {
+	if isEqualType(maybeSubType, superType) {
+		return true
+	}
+	if superType, ok := superType.(*NonNullType); ok {
+		if maybeSubType, ok := maybeSubType.(*NonNullType); ok {
+			return isTypeSubTypeOf(schema, maybeSubType.Type, superType.Type)
+		}
+		return false
+	}
+	if maybeSubType, ok := maybeSubType.(*NonNullType); ok {
+		return isTypeSubTypeOf(schema, maybeSubType.Type, superType)
+	}
+	if superType, ok := superType.(*ListType); ok {
+		if maybeSubType, ok := maybeSubType.(*ListType); ok {
+			return isTypeSubTypeOf(schema, maybeSubType.Type, superType.Type)
+		}
+		return false
+	}
+	if _, ok := maybeSubType.(*ListType); ok {
+		return false
+	}
+	superTypeName := namedType(superType)
+	subTypeName := namedType(maybeSubType)
+	if superTypeName == nil || subTypeName == nil {
+		return false
+	}
+	return isPossibleType(schema, schema.TypeIndex[superTypeName.Name.Value], subTypeName.Name.Value)
+}
+
+func isPossibleType(schema *Document, abstractType ASTNode, typeName string) bool {
+	name := typeNameOf(abstractType)
+	if name == "" || !isAbstractType(abstractType) {
+		return false
+	}
+	for _, possibleType := range schema.PossibleTypesIndex[name] {
+		if possibleType != nil && possibleType.Name.Value == typeName {
+			return true
+		}
+	}
+	return false
+}
+
+func possibleTypeNames(schema *Document, definition ASTNode) map[string]bool {
+	names := map[string]bool{}
+	if objectType, ok := definition.(*ObjectTypeDefinition); ok {
+		names[objectType.Name.Value] = true
+		return names
+	}
+	for _, possibleType := range schema.PossibleTypesIndex[typeNameOf(definition)] {
+		if possibleType != nil {
+			names[possibleType.Name.Value] = true
+		}
+	}
+	return names
+}
+
+// doTypesOverlap reports whether the two composite types share at least one
+// possible object type.
+func doTypesOverlap(schema *Document, typeA ASTNode, typeB ASTNode) bool {
+	if typeNameOf(typeA) == typeNameOf(typeB) {
+		return true
+	}
+	possibleTypesB := possibleTypeNames(schema, typeB)
+	for name := range possibleTypeNames(schema, typeA) {
+		if possibleTypesB[name] {
+			return true
+		}
+	}
+	return false
+}
+
+func typeNameOf(definition ASTNode) string {
+	name := typeName(definition)
+	if name == nil {
+		return ""
+	}
+	return name.Value
+}
+
+func locOf(node ASTNode) *LOC {
+	switch node := node.(type) {
+	case *Variable:
+		return node.LOC
+	case *Int:
+		return node.LOC
+	case *Float:
+		return node.LOC
+	case *String:
+		return node.LOC
+	case *Boolean:
+		return node.LOC
+	case *Enum:
+		return node.LOC
+	case *Null:
+		return node.LOC
+	case *List:
+		return node.LOC
+	case *Object:
+		return node.LOC
+	case *NamedType:
+		return node.LOC
+	case *ListType:
+		return node.LOC
+	case *NonNullType:
+		return node.LOC
+	case *SchemaDefinition:
+		return node.LOC
+	case *SchemaExtensionDefinition:
+		return node.LOC
+	}
+	return nil
+}
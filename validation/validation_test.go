@@ -0,0 +1,275 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/playlyfe/go-graphql/language"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var testSchema = `
+scalar String
+scalar Boolean
+scalar Int
+scalar Float
+scalar ID
+
+enum DogCommand {
+    SIT
+    HEEL
+    DOWN
+}
+
+interface Pet {
+    name: String
+}
+
+type Dog implements Pet {
+    name: String
+    nickname: String
+    barkVolume: Int
+    doesKnowCommand(dogCommand: DogCommand!): Boolean
+    isHousetrained(atOtherHomes: Boolean): Boolean
+}
+
+type Cat implements Pet {
+    name: String
+    meowVolume: Int
+}
+
+union CatOrDog = Cat | Dog
+
+directive @cached(seconds: Int!) on FIELD | QUERY
+
+input ComplexInput {
+    requiredField: Boolean!
+    stringField: String
+}
+
+type QueryRoot {
+    dog: Dog
+    pet: Pet
+    catOrDog: CatOrDog
+    complicatedArgs(complexArg: ComplexInput, intArg: Int, stringListArg: [String]): String
+}
+
+type MutationRoot {
+    renameDog(name: String!): Dog
+}
+`
+
+func validate(query string, rules ...Rule) []string {
+	parser := &language.Parser{}
+	schema, err := parser.Parse(&language.ParseParams{
+		Source: testSchema,
+	})
+	So(err, ShouldEqual, nil)
+	document, err := parser.Parse(&language.ParseParams{
+		Source:   query,
+		NoSource: true,
+	})
+	So(err, ShouldEqual, nil)
+	if len(rules) == 0 {
+		rules = SpecifiedRules
+	}
+	errs := Validate(&ValidateParams{
+		Schema:       schema,
+		QueryRoot:    schema.ObjectTypeIndex["QueryRoot"],
+		MutationRoot: schema.ObjectTypeIndex["MutationRoot"],
+		Document:     document,
+		Rules:        rules,
+	})
+	messages := []string{}
+	for _, err := range errs {
+		messages = append(messages, err.Message)
+	}
+	return messages
+}
+
+func TestValidation(t *testing.T) {
+
+	Convey("Validate: Specified rules", t, func() {
+
+		Convey("accepts a valid query", func() {
+			So(validate(`
+            query Q($command: DogCommand!) {
+                dog {
+                    ...dogFields
+                    doesKnowCommand(dogCommand: $command)
+                }
+                pet {
+                    name
+                    ... on Cat {
+                        meowVolume
+                    }
+                }
+                catOrDog {
+                    __typename
+                    ... on Dog {
+                        barkVolume @include(if: true)
+                    }
+                }
+            }
+            fragment dogFields on Dog {
+                name
+                nickname
+            }
+            `), ShouldResemble, []string{})
+		})
+
+		Convey("reports errors with their locations", func() {
+			parser := &language.Parser{}
+			schema, _ := parser.Parse(&language.ParseParams{Source: testSchema})
+			document, _ := parser.Parse(&language.ParseParams{Source: "{\n  dog {\n    unknown\n  }\n}"})
+			errs := Validate(&ValidateParams{
+				Schema:    schema,
+				QueryRoot: schema.ObjectTypeIndex["QueryRoot"],
+				Document:  document,
+				Rules:     []Rule{FieldsOnCorrectType},
+			})
+			So(len(errs), ShouldEqual, 1)
+			So(errs[0].Message, ShouldEqual, `GraphQL Validation Error (3:5) Cannot query field "unknown" on type "Dog"`)
+			So(errs[0].Start.Line, ShouldEqual, 3)
+			So(errs[0].Start.Column, ShouldEqual, 5)
+		})
+
+		Convey("FieldsOnCorrectType", func() {
+			So(validate(`{ dog { meowVolume } }`, FieldsOnCorrectType), ShouldResemble, []string{
+				`GraphQL Validation Error (1:9) Cannot query field "meowVolume" on type "Dog"`,
+			})
+			So(validate(`{ pet { barkVolume } }`, FieldsOnCorrectType), ShouldResemble, []string{
+				`GraphQL Validation Error (1:9) Cannot query field "barkVolume" on type "Pet"`,
+			})
+			So(validate(`{ catOrDog { __typename name } }`, FieldsOnCorrectType), ShouldResemble, []string{
+				`GraphQL Validation Error (1:25) Cannot query field "name" on type "CatOrDog"`,
+			})
+		})
+
+		Convey("ScalarLeafs", func() {
+			So(validate(`{ dog }`, ScalarLeafs), ShouldResemble, []string{
+				`GraphQL Validation Error (1:3) Field "dog" of type "Dog" must have a selection of subfields. Did you mean "dog { ... }"?`,
+			})
+			So(validate(`{ dog { name { length } } }`, ScalarLeafs), ShouldResemble, []string{
+				`GraphQL Validation Error (1:14) Field "name" must not have a selection since type "String" has no subfields`,
+			})
+		})
+
+		Convey("KnownArgumentNames", func() {
+			So(validate(`{ dog { isHousetrained(atOtherHomes: true, unknown: 1) } }`, KnownArgumentNames), ShouldResemble, []string{
+				`GraphQL Validation Error (1:44) Unknown argument "unknown" on field "isHousetrained" of type "Dog"`,
+			})
+			So(validate(`{ dog { name @skip(unless: true) } }`, KnownArgumentNames), ShouldResemble, []string{
+				`GraphQL Validation Error (1:20) Unknown argument "unless" on directive "@skip"`,
+			})
+		})
+
+		Convey("KnownFragmentNames and NoUnusedFragments", func() {
+			So(validate(`{ dog { ...missing } }`, KnownFragmentNames), ShouldResemble, []string{
+				`GraphQL Validation Error (1:12) Unknown fragment "missing"`,
+			})
+			So(validate(`{ dog { name } } fragment unused on Dog { name }`, NoUnusedFragments), ShouldResemble, []string{
+				`GraphQL Validation Error (1:18) Fragment "unused" is never used`,
+			})
+		})
+
+		Convey("NoFragmentCycles", func() {
+			So(validate(`
+            { dog { ...A } }
+            fragment A on Dog { ...B }
+            fragment B on Dog { ...A }
+            `, NoFragmentCycles), ShouldResemble, []string{
+				`GraphQL Validation Error (4:33) Cannot spread fragment "A" within itself via B`,
+			})
+		})
+
+		Convey("PossibleFragmentSpreads", func() {
+			So(validate(`{ dog { ... on Cat { meowVolume } } }`, PossibleFragmentSpreads), ShouldResemble, []string{
+				`GraphQL Validation Error (1:9) Fragment cannot be spread here as objects of type "Dog" can never be of type "Cat"`,
+			})
+			So(validate(`{ pet { ... on Dog { barkVolume } } }`, PossibleFragmentSpreads), ShouldResemble, []string{})
+		})
+
+		Convey("Operation names", func() {
+			So(validate(`query A { dog { name } } query A { pet { name } }`, UniqueOperationNames), ShouldResemble, []string{
+				`GraphQL Validation Error (1:32) There can be only one operation named "A"`,
+			})
+			So(validate(`{ dog { name } } query B { pet { name } }`, LoneAnonymousOperation), ShouldResemble, []string{
+				`GraphQL Validation Error (1:1) This anonymous operation must be the only defined operation`,
+			})
+		})
+
+		Convey("Variables", func() {
+			So(validate(`query Q { dog { doesKnowCommand(dogCommand: $command) } }`, NoUndefinedVariables), ShouldResemble, []string{
+				`GraphQL Validation Error (1:45) Variable "$command" is not defined by operation "Q"`,
+			})
+			So(validate(`query Q($unused: Int) { dog { name } }`, NoUnusedVariables), ShouldResemble, []string{
+				`GraphQL Validation Error (1:9) Variable "$unused" is never used in operation "Q"`,
+			})
+			So(validate(`query Q($dog: Dog) { dog { name } }`, VariablesAreInputTypes), ShouldResemble, []string{
+				`GraphQL Validation Error (1:15) Variable "$dog" cannot be non-input type "Dog"`,
+			})
+			So(validate(`query Q($command: DogCommand) { dog { doesKnowCommand(dogCommand: $command) } }`, VariablesInAllowedPosition), ShouldResemble, []string{
+				`GraphQL Validation Error (1:67) Variable "$command" of type "DogCommand" used in position expecting type "DogCommand!"`,
+			})
+			So(validate(`query Q($command: DogCommand = SIT) { dog { doesKnowCommand(dogCommand: $command) } }`, VariablesInAllowedPosition), ShouldResemble, []string{})
+		})
+
+		Convey("Arguments", func() {
+			So(validate(`{ dog { doesKnowCommand } }`, ProvidedNonNullArguments), ShouldResemble, []string{
+				`GraphQL Validation Error (1:9) Field "doesKnowCommand" argument "dogCommand" of type "DogCommand!" is required but not provided`,
+			})
+			So(validate(`{ dog { doesKnowCommand(dogCommand: JUMP) } }`, ArgumentsOfCorrectType), ShouldResemble, []string{
+				"GraphQL Validation Error (1:37) Argument \"dogCommand\" has invalid value JUMP.\nExpected type \"DogCommand\", found JUMP.",
+			})
+			So(validate(`{ complicatedArgs(complexArg: { stringField: "a" }) }`, ArgumentsOfCorrectType), ShouldResemble, []string{
+				"GraphQL Validation Error (1:31) Argument \"complexArg\" has invalid value {stringField: \"a\"}.\nIn field \"requiredField\": Expected \"Boolean!\", found null.",
+			})
+			So(validate(`{ complicatedArgs(complexArg: { requiredField: true, stringField: null }, intArg: null, stringListArg: [null]) }`, ArgumentsOfCorrectType), ShouldResemble, []string{})
+			So(validate(`{ dog { doesKnowCommand(dogCommand: null) } complicatedArgs(complexArg: { requiredField: null }) }`, ArgumentsOfCorrectType), ShouldResemble, []string{
+				"GraphQL Validation Error (1:37) Argument \"dogCommand\" has invalid value null.\nExpected \"DogCommand!\", found null.",
+				"GraphQL Validation Error (1:73) Argument \"complexArg\" has invalid value {requiredField: null}.\nIn field \"requiredField\": Expected \"Boolean!\", found null.",
+			})
+			So(validate(`{ complicatedArgs(intArg: 1, intArg: 2) }`, UniqueArgumentNames), ShouldResemble, []string{
+				`GraphQL Validation Error (1:30) There can be only one argument named "intArg"`,
+			})
+		})
+
+		Convey("Directives", func() {
+			So(validate(`{ dog { name @unknown } }`, KnownDirectives), ShouldResemble, []string{
+				`GraphQL Validation Error (1:14) Unknown directive "unknown"`,
+			})
+			So(validate(`query Q @skip(if: true) { dog { name } }`, KnownDirectives), ShouldResemble, []string{
+				`GraphQL Validation Error (1:9) Directive "skip" may not be used on QUERY`,
+			})
+			So(validate(`query Q @cached(seconds: 10) { dog { name @cached(seconds: 5) } }`, KnownDirectives, ArgumentsOfCorrectType, ProvidedNonNullArguments), ShouldResemble, []string{})
+			So(validate(`{ dog { ... on Dog @cached(seconds: 10) { name } } }`, KnownDirectives), ShouldResemble, []string{
+				`GraphQL Validation Error (1:20) Directive "cached" may not be used on INLINE_FRAGMENT`,
+			})
+			So(validate(`{ dog { name @skip(if: true) @skip(if: false) } }`, UniqueDirectivesPerLocation), ShouldResemble, []string{
+				`GraphQL Validation Error (1:30) The directive "skip" can only be used once at this location`,
+			})
+		})
+
+		Convey("OverlappingFieldsCanBeMerged", func() {
+			So(validate(`{ dog { name: nickname name } }`, OverlappingFieldsCanBeMerged), ShouldResemble, []string{
+				`GraphQL Validation Error (1:24) Fields "name" conflict because nickname and name are different fields. Use different aliases on the fields to fetch both if this was intentional`,
+			})
+			So(validate(`{ dog { isHousetrained(atOtherHomes: true) isHousetrained(atOtherHomes: false) } }`, OverlappingFieldsCanBeMerged), ShouldResemble, []string{
+				`GraphQL Validation Error (1:44) Fields "isHousetrained" conflict because they have differing arguments. Use different aliases on the fields to fetch both if this was intentional`,
+			})
+			So(validate(`{ dog { name name } }`, OverlappingFieldsCanBeMerged), ShouldResemble, []string{})
+		})
+
+		Convey("ExecutableDefinitions", func() {
+			So(validate(`{ dog { name } } type Extra { name: String }`, ExecutableDefinitions), ShouldResemble, []string{
+				`GraphQL Validation Error (1:23) The "Extra" definition is not executable`,
+			})
+			So(validate(`{ dog { name } } schema { query: QueryRoot } directive @extra on FIELD`, ExecutableDefinitions), ShouldResemble, []string{
+				`GraphQL Validation Error (1:18) The schema definition is not executable`,
+				`GraphQL Validation Error (1:57) The "@extra" definition is not executable`,
+			})
+		})
+
+	})
+
+}
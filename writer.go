@@ -0,0 +1,353 @@
+package graphql
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"reflect"
+	"sort"
+
+	. "github.com/playlyfe/go-graphql/language"
+)
+
+// outputBufferSize is the size of the buffer holding the output of ExecuteTo
+// before it is written out.
+const outputBufferSize = 32 * 1024
+
+// outputWindowSize is the number of items of a list completed at once by
+// ExecuteTo before being written out.
+const outputWindowSize = 64
+
+// ExecuteTo executes the request like ExecuteContext but writes the result to
+// w as JSON while its values are completed, instead of returning it. The
+// fields of objects are written one after the other and the items of lists a
+// few at a time, so that only the values being completed are held in memory.
+// As an error completing a non-null value nulls the value holding it, the
+// fields of an object up to its last non-null field and the lists of non-null
+// items are completed before being written. The After middleware receives the
+// result without its data, which has already been written. When an error is
+// returned the output is incomplete.
+func (executor *Executor) ExecuteTo(ctx context.Context, w io.Writer, appContext interface{}, request string, variables map[string]interface{}, operationName string) error {
+	out := &resultWriter{
+		executor: executor,
+		buffer:   bufio.NewWriterSize(w, outputBufferSize),
+	}
+	result := map[string]interface{}{}
+	reqCtx, selectedOperation, err := executor.startRequest(ctx, appContext, request, variables, operationName, nil, result)
+	if err != nil {
+		return err
+	}
+	if selectedOperation != nil {
+		if err := out.write(`{"data":`); err != nil {
+			return err
+		}
+		if err := out.writeOperation(reqCtx, selectedOperation); err != nil {
+			if _, err := handleGQLError(result, err); err != nil {
+				return err
+			}
+			if err := out.write("null"); err != nil {
+				return err
+			}
+		}
+	}
+	if reqCtx != nil {
+		result, err = executor.completeResult(reqCtx, result)
+		if err != nil {
+			return err
+		}
+	}
+	if selectedOperation == nil {
+		if err := out.encode(result); err != nil {
+			return err
+		}
+		return out.buffer.Flush()
+	}
+
+	keys := make([]string, 0, len(result))
+	for key := range result {
+		if key != "data" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := out.write(","); err != nil {
+			return err
+		}
+		if err := out.writeKey(key); err != nil {
+			return err
+		}
+		if err := out.encode(result[key]); err != nil {
+			return err
+		}
+	}
+	if err := out.write("}"); err != nil {
+		return err
+	}
+	return out.buffer.Flush()
+}
+
+// resultWriter writes the values of a result as they are completed. The
+// methods writing a value only return a GraphQL error before anything has been
+// written for it, so that the error can be caught by the nullable value
+// holding it.
+type resultWriter struct {
+	executor *Executor
+	buffer   *bufio.Writer
+}
+
+func (out *resultWriter) write(s string) error {
+	_, err := out.buffer.WriteString(s)
+	return err
+}
+
+func (out *resultWriter) encode(value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	_, err = out.buffer.Write(data)
+	return err
+}
+
+func (out *resultWriter) writeKey(key string) error {
+	if err := out.encode(key); err != nil {
+		return err
+	}
+	return out.write(":")
+}
+
+// writeOperation writes the data of the selected operation.
+func (out *resultWriter) writeOperation(reqCtx *RequestContext, operation *OperationDefinition) error {
+	executor := out.executor
+	switch operation.Operation {
+	case "query":
+		return out.writeObject(reqCtx, nil, false, executor.Schema.QueryRoot, map[string]interface{}{}, operation.SelectionSet)
+	case "mutation":
+		return out.writeObject(reqCtx, nil, false, executor.Schema.MutationRoot, map[string]interface{}{}, operation.SelectionSet)
+	}
+	data, err := executor.executeOperation(reqCtx, operation)
+	if err != nil {
+		return err
+	}
+	return out.encode(data)
+}
+
+// writeObject writes the object resulting from the execution of the selection
+// set on the source.
+func (out *resultWriter) writeObject(reqCtx *RequestContext, path *ResponsePath, isParallel bool, objectType *ObjectTypeDefinition, source interface{}, selectionSet *SelectionSet) error {
+	executor := out.executor
+	groupedFields, _, err := executor.groupedFields(reqCtx, objectType, selectionSet)
+	if err != nil {
+		return err
+	}
+
+	entries := make([]*fieldEntry, len(groupedFields))
+	if prepared, ok := source.(*preparedObject); ok {
+		copy(entries, prepared.entries)
+		source = prepared.value
+	} else {
+		// The source is resolved first so that resolving the fields of the
+		// object cannot fail once it is being written
+		if src, ok := source.(func() (interface{}, error)); ok {
+			source, err = src()
+			if err != nil {
+				return err
+			}
+		}
+		if isParallel && !executor.Debug {
+			entries = executor.resolveFieldEntries(reqCtx, path, objectType, source, groupedFields)
+			if reqCtx.Loaders != nil {
+				executor.prepareFieldEntries(reqCtx, entries)
+			}
+		} else if reqCtx.Loaders != nil && objectType != executor.Schema.MutationRoot {
+			for index, groupForResponseKey := range groupedFields {
+				entries[index] = executor.resolveFieldEntry(reqCtx, path, objectType, source, groupForResponseKey)
+			}
+			executor.prepareFieldEntries(reqCtx, entries)
+		}
+	}
+	entry := func(index int) *fieldEntry {
+		if entries[index] == nil {
+			entries[index] = executor.resolveFieldEntry(reqCtx, path, objectType, source, groupedFields[index])
+		}
+		return entries[index]
+	}
+
+	// The fields up to the last non-null field are completed before the
+	// object is written, the resolvers still running in order
+	completed := 0
+	for index, groupForResponseKey := range groupedFields {
+		if _, ok := executor.getFieldTypeFromObjectType(objectType, groupForResponseKey.Fields[0]).(*NonNullType); ok {
+			completed = index + 1
+		}
+	}
+	values := make([]interface{}, completed)
+	for index := range values {
+		_, values[index], err = executor.completeFieldEntry(reqCtx, objectType, entry(index))
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := out.write("{"); err != nil {
+		return err
+	}
+	written := 0
+	for index := range groupedFields {
+		entry := entry(index)
+		if entry.err != nil {
+			return entry.err
+		}
+		if entry.fieldType == nil {
+			continue
+		}
+		if written > 0 {
+			if err := out.write(","); err != nil {
+				return err
+			}
+		}
+		written++
+		if err := out.writeKey(entry.responseKey); err != nil {
+			return err
+		}
+		if index < completed {
+			err = out.encode(values[index])
+			values[index] = nil
+		} else {
+			err = out.writeValue(reqCtx, entry.path, objectType, entry.fieldType, entry.fields[0], entry.value, executor.mergeSelectionSets(entry.fields))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return out.write("}")
+}
+
+// writeValue writes the value of a field or list item of a nullable type. Like
+// completeValueCatchingError, a GraphQL error raised while completing the
+// value is added to the errors of the request and the value written as null.
+func (out *resultWriter) writeValue(reqCtx *RequestContext, path *ResponsePath, objectType *ObjectTypeDefinition, fieldType ASTNode, field *Field, value interface{}, subSelectionSet *SelectionSet) error {
+	executor := out.executor
+	value = executor.resolveThunk(reqCtx, path, field, value)
+	if !reflect.ValueOf(value).IsValid() || executor.IsNullish(value) {
+		return out.write("null")
+	}
+	var err error
+	switch fieldType := fieldType.(type) {
+	case *ListType:
+		err = out.writeList(reqCtx, path, objectType, fieldType, field, value, subSelectionSet)
+	case *NamedType:
+		err = out.writeNamedValue(reqCtx, path, objectType, fieldType, field, value, subSelectionSet)
+	default:
+		var completed interface{}
+		completed, err = executor.completeValue(reqCtx, path, objectType, fieldType, field, value, subSelectionSet)
+		if err == nil {
+			err = out.encode(completed)
+		}
+	}
+	if err == nil {
+		return nil
+	}
+	if err := catchError(reqCtx, path, err); err != nil {
+		return err
+	}
+	return out.write("null")
+}
+
+// writeList writes the items of the list, completing a window of items at a
+// time. Lists of non-null items are completed before being written, as an
+// error completing one of their items nulls the list.
+func (out *resultWriter) writeList(reqCtx *RequestContext, path *ResponsePath, objectType *ObjectTypeDefinition, listType *ListType, field *Field, value interface{}, subSelectionSet *SelectionSet) error {
+	executor := out.executor
+	itemType := listType.Type
+	list := reflect.ValueOf(value)
+	if _, ok := itemType.(*NonNullType); ok || list.Kind() != reflect.Slice {
+		completed, err := executor.completeValue(reqCtx, path, objectType, listType, field, value, subSelectionSet)
+		if err != nil {
+			return err
+		}
+		return out.encode(completed)
+	}
+
+	if err := out.write("["); err != nil {
+		return err
+	}
+	items := make([]interface{}, outputWindowSize)
+	errs := make([]error, outputWindowSize)
+	for from := 0; from < list.Len(); from += outputWindowSize {
+		to := from + outputWindowSize
+		if to > list.Len() {
+			to = list.Len()
+		}
+		complete := func(index int) {
+			items[index-from], errs[index-from] = executor.completeValueCatchingError(reqCtx, path.WithKey(index), objectType, itemType, field, list.Index(index).Interface(), subSelectionSet)
+		}
+		if !executor.Debug {
+			batch := executor.Scheduler.batch(reqCtx)
+			inline := executor.inlineItems(itemType)
+			for index := from; index < to; index++ {
+				index := index
+				batch.do(inline, func() {
+					complete(index)
+				})
+			}
+			batch.wait()
+		} else {
+			for index := from; index < to; index++ {
+				complete(index)
+			}
+		}
+		for index := from; index < to; index++ {
+			if errs[index-from] != nil {
+				return errs[index-from]
+			}
+			if index > 0 {
+				if err := out.write(","); err != nil {
+					return err
+				}
+			}
+			if err := out.encode(items[index-from]); err != nil {
+				return err
+			}
+			items[index-from] = nil
+		}
+	}
+	return out.write("]")
+}
+
+// writeNamedValue writes a value of a named type, writing the fields of
+// objects one after the other.
+func (out *resultWriter) writeNamedValue(reqCtx *RequestContext, path *ResponsePath, objectType *ObjectTypeDefinition, namedType *NamedType, field *Field, value interface{}, subSelectionSet *SelectionSet) error {
+	executor := out.executor
+	if prepared, ok := value.(*preparedObject); ok {
+		return out.writeObject(reqCtx, path, true, prepared.objectType, prepared, subSelectionSet)
+	}
+	schema := executor.Schema.Document
+	typeName := namedType.Name.Value
+	if objectType, ok := schema.ObjectTypeIndex[typeName]; ok {
+		return out.writeObject(reqCtx, path, true, objectType, value, subSelectionSet)
+	}
+	var abstractType ASTNode
+	if interfaceType, ok := schema.InterfaceTypeIndex[typeName]; ok {
+		abstractType = interfaceType
+	} else if unionType, ok := schema.UnionTypeIndex[typeName]; ok {
+		abstractType = unionType
+	}
+	if abstractType == nil {
+		completed, err := executor.completeValue(reqCtx, path, objectType, namedType, field, value, subSelectionSet)
+		if err != nil {
+			return err
+		}
+		return out.encode(completed)
+	}
+	resolvedType, err := executor.resolveAbstractType(reqCtx, path, field, abstractType, value)
+	if err != nil {
+		return err
+	}
+	if resolvedType == nil {
+		return out.write("null")
+	}
+	return out.writeObject(reqCtx, path, true, resolvedType, value, subSelectionSet)
+}
@@ -0,0 +1,164 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type chunkWriter struct {
+	chunks []int
+	output bytes.Buffer
+}
+
+func (w *chunkWriter) Write(data []byte) (int, error) {
+	w.chunks = append(w.chunks, len(data))
+	return w.output.Write(data)
+}
+
+type failingWriter struct{}
+
+func (w failingWriter) Write(data []byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+func TestExecuteTo(t *testing.T) {
+
+	Convey("ExecuteTo: Writes the result as JSON while it is completed", t, func() {
+		schema := `
+        interface Named {
+            name: String
+        }
+
+        type Item implements Named {
+            id: Int!
+            name: String
+            fail: String
+        }
+
+        type Other implements Named {
+            name: String
+        }
+
+        type Query {
+            item: Item
+            items: [Item]
+            strict: [Item!]
+            broken: Item
+            numbers: [Int]
+            named: [Named]
+            large(size: Int): [Item]
+        }
+        `
+		resolvers := map[string]interface{}{}
+		resolvers["Query/item"] = func(params *ResolveParams) (interface{}, error) {
+			return map[string]interface{}{"id": 1, "name": "one"}, nil
+		}
+		resolvers["Query/items"] = func(params *ResolveParams) (interface{}, error) {
+			return []interface{}{
+				map[string]interface{}{"id": 1, "name": "one"},
+				map[string]interface{}{"id": 2, "name": "two"},
+				nil,
+			}, nil
+		}
+		resolvers["Query/strict"] = func(params *ResolveParams) (interface{}, error) {
+			return []interface{}{
+				map[string]interface{}{"id": 1, "name": "one"},
+				nil,
+			}, nil
+		}
+		resolvers["Query/broken"] = func(params *ResolveParams) (interface{}, error) {
+			return map[string]interface{}{"name": "broken"}, nil
+		}
+		resolvers["Query/numbers"] = func(params *ResolveParams) (interface{}, error) {
+			return []interface{}{1, 2, 3}, nil
+		}
+		resolvers["Query/named"] = func(params *ResolveParams) (interface{}, error) {
+			return []interface{}{
+				map[string]interface{}{"id": 1, "name": "one"},
+				map[string]interface{}{"name": "other"},
+			}, nil
+		}
+		resolvers["Query/large"] = func(params *ResolveParams) (interface{}, error) {
+			items := []interface{}{}
+			for index := 0; index < int(params.Args["size"].(int32)); index++ {
+				items = append(items, map[string]interface{}{"id": index, "name": fmt.Sprintf("item %d", index)})
+			}
+			return items, nil
+		}
+		resolvers["Item/fail"] = func(params *ResolveParams) (interface{}, error) {
+			if params.Source.(map[string]interface{})["id"] == 2 {
+				return nil, errors.New("failed")
+			}
+			return "ok", nil
+		}
+		executor, err := NewExecutor(schema, "Query", "", resolvers)
+		So(err, ShouldEqual, nil)
+		executor.Scheduler = NewScheduler(1, 1)
+		executor.ResolveType = func(value interface{}) string {
+			if _, ok := value.(map[string]interface{})["id"]; ok {
+				return "Item"
+			}
+			return "Other"
+		}
+		// The fields of the objects written by ExecuteTo are in selection order
+		executor.OrderedResults = true
+
+		executeTo := func(request string) string {
+			output := &bytes.Buffer{}
+			err := executor.ExecuteTo(context.Background(), output, nil, request, map[string]interface{}{}, "")
+			So(err, ShouldEqual, nil)
+			return output.String()
+		}
+		marshal := func(request string) string {
+			result, err := executor.Execute(nil, request, map[string]interface{}{}, "")
+			So(err, ShouldEqual, nil)
+			output, err := json.Marshal(result)
+			So(err, ShouldEqual, nil)
+			return string(output)
+		}
+
+		Convey("like the result of Execute", func() {
+			for _, request := range []string{
+				`{ item { name id } numbers }`,
+				`{ items { fail name id } }`,
+				`{ strict { id } broken { name id } item { id } }`,
+				`{ named { name ... on Item { id } } }`,
+				`{ large(size: 150) { id } }`,
+				`{ unknown }`,
+				`{ large(size: 2) { id } `,
+			} {
+				So(executeTo(request), ShouldEqual, marshal(request))
+			}
+		})
+
+		Convey("by nulling only the nullable values holding an error", func() {
+			So(executeTo(`{ items { id fail } strict { id } }`), ShouldEqual, `{"data":{"items":[{"id":1,"fail":"ok"},{"id":2,"fail":null},null],"strict":null},"errors":[{"locations":[{"column":14,"line":1}],"message":"failed","path":["items",1,"fail"]},{"locations":[{"column":21,"line":1}],"message":"Cannot return null for non-nullable field Query.strict","path":["strict",1]}]}`)
+		})
+
+		Convey("in chunks no larger than its buffer", func() {
+			output := &chunkWriter{}
+			err := executor.ExecuteTo(context.Background(), output, nil, `{ large(size: 5000) { id name } }`, map[string]interface{}{}, "")
+			So(err, ShouldEqual, nil)
+			So(len(output.chunks), ShouldBeGreaterThan, 1)
+			for _, chunk := range output.chunks {
+				So(chunk, ShouldBeLessThanOrEqualTo, outputBufferSize)
+			}
+			result := map[string]map[string][]interface{}{}
+			So(json.Unmarshal(output.output.Bytes(), &result), ShouldEqual, nil)
+			So(len(result["data"]["large"]), ShouldEqual, 5000)
+		})
+
+		Convey("and returns the errors writing the result", func() {
+			err := executor.ExecuteTo(context.Background(), failingWriter{}, nil, `{ large(size: 5000) { id name } }`, map[string]interface{}{}, "")
+			So(err, ShouldNotEqual, nil)
+			So(err.Error(), ShouldEqual, "connection reset")
+		})
+	})
+
+}